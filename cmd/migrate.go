@@ -2,75 +2,212 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"text/tabwriter"
 
 	"inventory-system/internal/config"
+	"inventory-system/internal/migration"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
+// migrationsDir is the folder scanned for versioned migration files.
+const migrationsDir = "migrations"
+
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
-	Short: "Reset database and run latest migration",
+	Short: "Manage versioned database migrations",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		// 1. Setup Config & Infra
-		cfg, err := config.LoadConfig(".")
-		if err != nil {
-			log.Fatalf("Failed to load configuration: %v", err)
+		runMigrator(func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error {
+			count, err := m.Up(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Info("✅ Migrations applied", zap.Int("count", count))
+			return nil
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [N]",
+	Short: "Roll back the last N applied migrations (default 1)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		steps := 1
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				log.Fatalf("Invalid step count %q: must be a positive integer", args[0])
+			}
+			steps = n
 		}
 
-		logger := config.InitLogger(cfg.App.Env)
-		defer logger.Sync()
+		runMigrator(func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error {
+			count, err := m.Down(ctx, steps)
+			if err != nil {
+				return err
+			}
+			logger.Info("✅ Migrations rolled back", zap.Int("count", count))
+			return nil
+		})
+	},
+}
 
-		dbPool, err := config.ConnectDB(cfg.DB.GetURL())
-		if err != nil {
-			logger.Fatal("Failed to connect to database", zap.Error(err))
+var migrateGotoCmd = &cobra.Command{
+	Use:   "goto VERSION",
+	Short: "Migrate up or down to the given version (0 rolls back everything)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || version < 0 {
+			log.Fatalf("Invalid version %q: must be a non-negative integer", args[0])
 		}
-		defer dbPool.Close()
-
-		ctx := context.Background()
-
-		// ==========================================
-		// 2. TOMBOL NUKLIR (Reset Database)
-		// ==========================================
-		logger.Warn("Dropping public schema to reset database...")
-
-		resetQuery := `
-			DROP SCHEMA public CASCADE;
-			CREATE SCHEMA public;
-			GRANT ALL ON SCHEMA public TO public;
-		`
-		_, err = dbPool.Exec(ctx, resetQuery)
-		if err != nil {
-			logger.Fatal("Failed to reset database schema", zap.Error(err))
+
+		runMigrator(func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error {
+			count, err := m.Goto(ctx, version)
+			if err != nil {
+				return err
+			}
+			logger.Info("✅ Database migrated to target version", zap.Int64("version", version), zap.Int("steps", count))
+			return nil
+		})
+	},
+}
+
+var migrateBaselineCmd = &cobra.Command{
+	Use:   "baseline VERSION",
+	Short: "Record migrations up to VERSION as applied without running them, for an existing database",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || version < 1 {
+			log.Fatalf("Invalid version %q: must be a positive integer", args[0])
 		}
-		logger.Info("Database reset successful!")
 
-		// ==========================================
-		// 3. BACA & JALANKAN FILE SQL TERBARU
-		// ==========================================
-		logger.Info("Applying new database schema...")
+		runMigrator(func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error {
+			count, err := m.Baseline(ctx, version)
+			if err != nil {
+				return err
+			}
+			logger.Info("✅ Existing schema recorded as baseline", zap.Int64("version", version), zap.Int("count", count))
+			return nil
+		})
+	},
+}
 
-		// Pastikan path ini sesuai dengan lokasi file SQL lu!
-		sqlFile := "migrations/0001_init_schema.sql"
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied and pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrator(func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error {
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				return err
+			}
 
-		sqlBytes, err := os.ReadFile(sqlFile)
-		if err != nil {
-			logger.Fatal("Failed to read SQL file", zap.Error(err), zap.String("file", sqlFile))
-		}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+			for _, st := range statuses {
+				state, appliedAt := "pending", "-"
+				if st.Applied {
+					state = "applied"
+					appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05 MST")
+				}
+				if st.Modified {
+					state = "applied (MODIFIED)"
+				}
+				if st.Missing {
+					state = "applied (FILE MISSING)"
+				}
+				fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+			}
+			return tw.Flush()
+		})
+	},
+}
 
-		// Eksekusi semua isi file SQL sekaligus
-		_, err = dbPool.Exec(ctx, string(sqlBytes))
-		if err != nil {
-			logger.Fatal("Failed to execute migration", zap.Error(err))
+var migrateResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "DESTRUCTIVE: drop the public schema and re-apply all migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		if !force {
+			log.Fatal("Refusing to reset the database without --force. This drops ALL data.")
 		}
 
-		logger.Info("✅ Migration executed successfully!")
+		runMigrator(func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error {
+			count, err := m.Reset(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Info("✅ Database reset and migrations applied", zap.Int("count", count))
+			return nil
+		}, withProductionGuard())
 	},
 }
 
+type migratorOption func(cfg *config.Config) error
+
+// withProductionGuard aborts the command when it is pointed at a production environment.
+func withProductionGuard() migratorOption {
+	return func(cfg *config.Config) error {
+		if cfg.App.Env == "production" {
+			return fmt.Errorf("refusing to run a destructive command with APP_ENV=production")
+		}
+		return nil
+	}
+}
+
+// runMigrator sets up config, logger and database (sama kayak di root.go),
+// loads every migration file and hands a ready Migrator to fn.
+func runMigrator(fn func(ctx context.Context, m *migration.Migrator, logger *zap.Logger) error, opts ...migratorOption) {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	logger := config.InitLogger(cfg.App.Env)
+	defer logger.Sync()
+
+	migrations, err := migration.Load(os.DirFS(migrationsDir))
+	if err != nil {
+		logger.Fatal("Failed to load migration files", zap.Error(err), zap.String("dir", migrationsDir))
+	}
+
+	dbPool, err := config.ConnectDB(cfg.DB.GetURL())
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer dbPool.Close()
+
+	migrator := migration.NewMigrator(dbPool, migrations, logger)
+	if err := fn(context.Background(), migrator, logger); err != nil {
+		logger.Fatal("Migration command failed", zap.Error(err))
+	}
+}
+
 func init() {
+	migrateResetCmd.Flags().Bool("force", false, "Confirm that all data in the database will be destroyed")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateGotoCmd, migrateBaselineCmd, migrateStatusCmd, migrateResetCmd)
 	rootCmd.AddCommand(migrateCmd)
 }
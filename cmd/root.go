@@ -14,6 +14,7 @@ import (
 	"inventory-system/internal/repository"
 	"inventory-system/internal/router"
 	"inventory-system/internal/service"
+	"inventory-system/pkg/mailer"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -44,11 +45,14 @@ var rootCmd = &cobra.Command{
 
 		// 2. DEPENDENCY INJECTION (Wiring up the app)
 		repos := repository.NewRepository(dbPool)
-		services := service.NewService(repos, logger)
+		services := service.NewService(repos, cfg, newMailer(cfg.Mail, logger), logger)
 		handlers := handler.NewHandler(services, logger)
 
 		// 3. ROUTING & MIDDLEWARE SETUP
-		r := router.SetupRoute(handlers, repos)
+		r, err := router.SetupRoute(handlers, repos, cfg)
+		if err != nil {
+			logger.Fatal("Failed to set up routes", zap.Error(err))
+		}
 
 		// 4. START HTTP SERVER & GRACEFUL SHUTDOWN
 		srv := &http.Server{
@@ -86,3 +90,22 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// newMailer picks the mail delivery configured by MAIL_DRIVER. LoadConfig has already validated it.
+func newMailer(cfg config.MailConfig, logger *zap.Logger) mailer.Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		})
+	case "file":
+		return mailer.NewFileMailer(cfg.FileDir, cfg.From)
+	default:
+		logger.Warn("MAIL_DRIVER is log: emails are only written to the log, never sent")
+		return mailer.NewLogMailer(logger)
+	}
+}
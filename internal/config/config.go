@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,6 +13,35 @@ import (
 type AppConfig struct {
 	Port string `mapstructure:"APP_PORT"`
 	Env  string `mapstructure:"APP_ENV"`
+
+	// Comma separated CIDRs of the reverse proxies in front of the app. Only requests from them may
+	// name the client in X-Forwarded-For or X-Real-IP; empty means the app faces clients directly.
+	TrustedProxies string `mapstructure:"APP_TRUSTED_PROXIES"`
+}
+
+// TrustedProxyPrefixes parses TrustedProxies. A bare address is taken as a single host.
+func (c AppConfig) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, field := range strings.Split(c.TrustedProxies, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			addr, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, fmt.Errorf("APP_TRUSTED_PROXIES: %w", err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("APP_TRUSTED_PROXIES: %w", err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
 }
 
 // DBConfig holds database connection settings
@@ -28,10 +60,55 @@ func (db *DBConfig) GetURL() string {
 		db.User, db.Password, db.Host, db.Port, db.Name, db.SSLMode)
 }
 
+// AuthConfig holds token lifetimes and login protection settings.
+// Durations use Go duration syntax, e.g. "15m" or "168h".
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration `mapstructure:"AUTH_ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `mapstructure:"AUTH_REFRESH_TOKEN_TTL"`
+
+	MaxFailedLogins int           `mapstructure:"AUTH_MAX_FAILED_LOGINS"` // failures before the account is locked
+	LockoutDuration time.Duration `mapstructure:"AUTH_LOCKOUT_DURATION"`  // how long a locked account stays locked
+	LoginRateLimit  int           `mapstructure:"AUTH_LOGIN_RATE_LIMIT"`  // login (and, separately, refresh) attempts per IP per minute
+
+	PasswordResetTTL      time.Duration `mapstructure:"AUTH_PASSWORD_RESET_TTL"`
+	PasswordResetCooldown time.Duration `mapstructure:"AUTH_PASSWORD_RESET_COOLDOWN"` // minimum time between reset emails to one user
+	PasswordResetURL      string        `mapstructure:"AUTH_PASSWORD_RESET_URL"`      // frontend page; the token is appended as ?token=
+
+	Require2FAForAdmins bool   `mapstructure:"AUTH_REQUIRE_2FA_FOR_ADMINS"` // super_admin and admin must use TOTP
+	TOTPIssuer          string `mapstructure:"AUTH_TOTP_ISSUER"`            // name shown in authenticator apps
+
+	// Single sign-on with an OpenID Connect provider, next to password login. Off while the issuer is empty.
+	OIDCIssuerURL     string `mapstructure:"AUTH_OIDC_ISSUER_URL"`
+	OIDCClientID      string `mapstructure:"AUTH_OIDC_CLIENT_ID"`
+	OIDCClientSecret  string `mapstructure:"AUTH_OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL   string `mapstructure:"AUTH_OIDC_REDIRECT_URL"`   // frontend page that posts code and state to /auth/oidc/callback
+	OIDCScopes        string `mapstructure:"AUTH_OIDC_SCOPES"`         // space separated; "openid" is always requested
+	OIDCAutoProvision bool   `mapstructure:"AUTH_OIDC_AUTO_PROVISION"` // create users for unknown verified emails
+	OIDCDefaultRole   string `mapstructure:"AUTH_OIDC_DEFAULT_ROLE"`   // role of auto-provisioned users
+}
+
+// OIDCEnabled reports whether single sign-on is configured.
+func (c AuthConfig) OIDCEnabled() bool {
+	return c.OIDCIssuerURL != ""
+}
+
+// MailConfig selects how outgoing mail is delivered: "smtp", "file" (write .eml files) or "log".
+type MailConfig struct {
+	Driver       string `mapstructure:"MAIL_DRIVER"`
+	From         string `mapstructure:"MAIL_FROM"`
+	SMTPHost     string `mapstructure:"MAIL_SMTP_HOST"`
+	SMTPPort     string `mapstructure:"MAIL_SMTP_PORT"`
+	SMTPUsername string `mapstructure:"MAIL_SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"MAIL_SMTP_PASSWORD"`
+	FileDir      string `mapstructure:"MAIL_FILE_DIR"`
+}
+
 // Config is the master struct that groups all configurations
 type Config struct {
-	App AppConfig `mapstructure:",squash"`
-	DB  DBConfig  `mapstructure:",squash"`
+	App  AppConfig  `mapstructure:",squash"`
+	DB   DBConfig   `mapstructure:",squash"`
+	Auth AuthConfig `mapstructure:",squash"`
+	Mail MailConfig `mapstructure:",squash"`
 }
 
 // LoadConfig reads the configuration from the provided path.
@@ -39,11 +116,71 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigFile(path + "/.env")
 	viper.AutomaticEnv()
 
+	// Defaults for optional settings; the access token is short-lived, the refresh token covers a week.
+	viper.SetDefault("AUTH_ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("AUTH_REFRESH_TOKEN_TTL", "168h")
+	viper.SetDefault("AUTH_MAX_FAILED_LOGINS", 5)
+	viper.SetDefault("AUTH_LOCKOUT_DURATION", "15m")
+	viper.SetDefault("AUTH_LOGIN_RATE_LIMIT", 20)
+	viper.SetDefault("AUTH_PASSWORD_RESET_TTL", "30m")
+	viper.SetDefault("AUTH_PASSWORD_RESET_COOLDOWN", "2m")
+	viper.SetDefault("AUTH_REQUIRE_2FA_FOR_ADMINS", false)
+	viper.SetDefault("AUTH_TOTP_ISSUER", "Inventory System")
+	viper.SetDefault("AUTH_OIDC_SCOPES", "openid email profile")
+	viper.SetDefault("AUTH_OIDC_AUTO_PROVISION", false)
+	viper.SetDefault("AUTH_OIDC_DEFAULT_ROLE", "staff")
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "no-reply@inventory.local")
+	viper.SetDefault("MAIL_SMTP_PORT", "587")
+	viper.SetDefault("MAIL_FILE_DIR", "tmp/mail")
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	if _, err = config.App.TrustedProxyPrefixes(); err != nil {
+		return
+	}
+	if config.Auth.AccessTokenTTL <= 0 || config.Auth.RefreshTokenTTL <= 0 {
+		err = fmt.Errorf("AUTH_ACCESS_TOKEN_TTL and AUTH_REFRESH_TOKEN_TTL must be positive durations")
+		return
+	}
+	if config.Auth.MaxFailedLogins <= 0 || config.Auth.LockoutDuration <= 0 || config.Auth.LoginRateLimit <= 0 {
+		err = fmt.Errorf("AUTH_MAX_FAILED_LOGINS, AUTH_LOCKOUT_DURATION and AUTH_LOGIN_RATE_LIMIT must be positive")
+		return
+	}
+	if config.Auth.PasswordResetTTL <= 0 {
+		err = fmt.Errorf("AUTH_PASSWORD_RESET_TTL must be a positive duration")
+		return
+	}
+	if config.Auth.PasswordResetCooldown < 0 {
+		err = fmt.Errorf("AUTH_PASSWORD_RESET_COOLDOWN must not be negative")
+		return
+	}
+
+	if config.Auth.OIDCEnabled() && (config.Auth.OIDCClientID == "" || config.Auth.OIDCRedirectURL == "") {
+		err = fmt.Errorf("AUTH_OIDC_CLIENT_ID and AUTH_OIDC_REDIRECT_URL are required when AUTH_OIDC_ISSUER_URL is set")
+		return
+	}
+	if config.Auth.OIDCAutoProvision && config.Auth.OIDCDefaultRole == "" {
+		err = fmt.Errorf("AUTH_OIDC_DEFAULT_ROLE is required when AUTH_OIDC_AUTO_PROVISION is on")
+		return
+	}
+
+	switch config.Mail.Driver {
+	case "log", "file":
+	case "smtp":
+		if config.Mail.SMTPHost == "" {
+			err = fmt.Errorf("MAIL_SMTP_HOST is required when MAIL_DRIVER is smtp")
+		}
+	default:
+		err = fmt.Errorf("MAIL_DRIVER must be smtp, file or log, got %q", config.Mail.Driver)
+	}
 	return
 }
@@ -0,0 +1,13 @@
+package request
+
+import "time"
+
+// CreateAPIKeyRequest mints an API key. A key can be limited either to a role's permissions or to an
+// explicit list; with neither it gets everything its owner's role has. Either way it never grants
+// more than the owner's current role.
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,min=3,max=100" example:"Gudang A scanner"`
+	Role        string     `json:"role" validate:"omitempty,max=50" example:"staff"`
+	Permissions []string   `json:"permissions" validate:"omitempty,max=20,dive,required" example:"stock:move"`
+	ExpiresAt   *time.Time `json:"expires_at" example:"2027-01-01T00:00:00Z"` // omit for a key that does not expire
+}
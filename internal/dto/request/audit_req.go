@@ -0,0 +1,19 @@
+package request
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditListQuery holds the pagination and filter options for the audit trail.
+// From is inclusive and To is exclusive.
+type AuditListQuery struct {
+	PaginationQuery
+	ActorID    *uuid.UUID
+	Action     string // e.g. item.price_change
+	EntityType string // user, item, warehouse or stock
+	EntityID   *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+}
@@ -1,8 +1,54 @@
 package request
 
 // LoginRequest defines the JSON payload expected from the frontend during login.
-// We use validation tags later here to ensure data integrity.
+// The validate tags are enforced by utils.BindJSON before the request reaches the service.
 type LoginRequest struct {
-	Email    string `json:"email" example:"admin@gmail.com"`
-	Password string `json:"password" example:"password123"`
+	Email    string `json:"email" validate:"required,email" example:"admin@gmail.com"`
+	Password string `json:"password" validate:"required" example:"password123"`
+}
+
+// OIDCCallbackRequest finishes a single sign-on login with the code and state the OpenID
+// provider sent back to the redirect URL.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" validate:"required,max=2048" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	State string `json:"state" validate:"required,max=256" example:"af0ifjsldkj"`
+}
+
+// ForgotPasswordRequest asks for a password reset email.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email" example:"staff@gmail.com"`
+}
+
+// ResetPasswordRequest sets a new password with the token from the reset email.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required" example:"q7Jx1mZ0b8c3VvKcN2a9dLr4tYp6sWfEh5gUiOo0AzM"`
+	NewPassword string `json:"new_password" validate:"required,password" example:"n3w-Passw0rd"`
+}
+
+// TwoFactorLoginRequest finishes a login that answered with a two-factor challenge.
+// Code is a 6-digit authenticator code or one of the recovery codes.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"Vb0y0m6J2c1b8kq3rYVQmJtJ4n5o9pXHh2uZ7aC1dE0"`
+	Code           string `json:"code" validate:"required,max=32" example:"123456"`
+}
+
+// LoginChallengeRequest refers to the challenge of an unfinished login.
+type LoginChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"Vb0y0m6J2c1b8kq3rYVQmJtJ4n5o9pXHh2uZ7aC1dE0"`
+}
+
+// TwoFactorCodeRequest carries a code proving the caller holds the authenticator.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=32" example:"123456"`
+}
+
+// DisableTwoFactorRequest turns 2FA off; it needs both the password and a current code.
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required" example:"password123"`
+	Code     string `json:"code" validate:"required,max=32" example:"123456"`
+}
+
+// RefreshRequest carries the refresh token to exchange for a new token pair.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"q7Jx1mZ0b8c3VvKcN2a9dLr4tYp6sWfEh5gUiOo0AzM"`
 }
@@ -0,0 +1,16 @@
+package request
+
+import "github.com/google/uuid"
+
+type CreateCategoryRequest struct {
+	Name        string     `json:"name" validate:"required,max=100" example:"Soft Drinks"`
+	Description string     `json:"description" example:"Carbonated beverages"`
+	ParentID    *uuid.UUID `json:"parent_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+}
+
+// UpdateCategoryRequest replaces a category's fields. A null parent_id makes it a root category.
+type UpdateCategoryRequest struct {
+	Name        string     `json:"name" validate:"required,max=100" example:"Soft Drinks"`
+	Description string     `json:"description" example:"Carbonated beverages"`
+	ParentID    *uuid.UUID `json:"parent_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+}
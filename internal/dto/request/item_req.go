@@ -0,0 +1,43 @@
+package request
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type CreateItemRequest struct {
+	SKU        string          `json:"sku" validate:"required,max=50" example:"BEV-COLA-330"`
+	Name       string          `json:"name" validate:"required,max=255" example:"Cola 330ml"`
+	CategoryID *uuid.UUID      `json:"category_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	ShelfID    *uuid.UUID      `json:"shelf_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Price      decimal.Decimal `json:"price" swaggertype:"string" example:"7500.00"`
+
+	// Stock is not accepted: it only moves through the stock ledger.
+	// It is declared so a client sending it gets a clear error instead of a silent no-op.
+	Stock *int `json:"stock,omitempty" swaggerignore:"true"`
+}
+
+// UpdateItemRequest replaces an item's catalogue data. Stock cannot be edited here.
+type UpdateItemRequest struct {
+	SKU        string          `json:"sku" validate:"required,max=50" example:"BEV-COLA-330"`
+	Name       string          `json:"name" validate:"required,max=255" example:"Cola 330ml Can"`
+	CategoryID *uuid.UUID      `json:"category_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	ShelfID    *uuid.UUID      `json:"shelf_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Price      decimal.Decimal `json:"price" swaggertype:"string" example:"8000.00"`
+
+	Stock *int `json:"stock,omitempty" swaggerignore:"true"`
+}
+
+// ItemListQuery holds the pagination, filter and sort options for listing items.
+type ItemListQuery struct {
+	PaginationQuery
+	CategoryID  *uuid.UUID
+	ShelfID     *uuid.UUID
+	WarehouseID *uuid.UUID
+	MinPrice    *decimal.Decimal
+	MaxPrice    *decimal.Decimal
+	MinStock    *int
+	MaxStock    *int
+	Sort        string // name, sku, price or stock
+	Order       string // asc or desc
+}
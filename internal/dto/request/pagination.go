@@ -1,7 +1,31 @@
 package request
 
+const (
+	DefaultPage  = 1
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
 type PaginationQuery struct {
 	Page   int    `json:"page"`
 	Limit  int    `json:"limit"`
 	Search string `json:"search"`
 }
+
+// Normalize applies the default page and limit when they are missing or out of range.
+func (q *PaginationQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = DefaultPage
+	}
+	if q.Limit < 1 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+}
+
+// Offset returns how many records the database should skip: (Page - 1) * Limit.
+func (q PaginationQuery) Offset() int {
+	return (q.Page - 1) * q.Limit
+}
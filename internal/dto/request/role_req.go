@@ -0,0 +1,14 @@
+package request
+
+// CreateRoleRequest defines a custom role. Names are lowercase letters, digits and underscores.
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=3,max=50" example:"warehouse_lead"`
+	Description string   `json:"description" validate:"max=255" example:"Runs one warehouse: moves and adjusts stock"`
+	Permissions []string `json:"permissions" validate:"required,dive,required" example:"stock:move,stock:adjust"`
+}
+
+// UpdateRoleRequest replaces a role's description and its whole permission set.
+type UpdateRoleRequest struct {
+	Description string   `json:"description" validate:"max=255" example:"Runs one warehouse: moves and adjusts stock"`
+	Permissions []string `json:"permissions" validate:"required,dive,required" example:"stock:move,stock:adjust,items:write"`
+}
@@ -0,0 +1,45 @@
+package request
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CartItemRequest is one cart line. Exactly one of ItemID or SKU identifies the item.
+type CartItemRequest struct {
+	ItemID   *uuid.UUID `json:"item_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	SKU      string     `json:"sku" example:"BEV-COLA-330"`
+	Quantity int        `json:"quantity" validate:"required,gt=0" example:"2"`
+}
+
+// CheckoutRequest is the cart submitted at the till. Prices are always taken from the catalogue.
+type CheckoutRequest struct {
+	Items []CartItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// VoidSaleRequest cancels a whole sale on the day it was made.
+type VoidSaleRequest struct {
+	Reason string `json:"reason" validate:"required" example:"Customer cancelled at the till"`
+}
+
+// ReturnItemRequest returns part of one sale line.
+type ReturnItemRequest struct {
+	SaleItemID uuid.UUID `json:"sale_item_id" validate:"required" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Quantity   int       `json:"quantity" validate:"required,gt=0" example:"1"`
+}
+
+// CreateReturnRequest lists the sale lines (and how many units of each) a customer brings back.
+type CreateReturnRequest struct {
+	Reason string              `json:"reason" example:"Dented can"`
+	Items  []ReturnItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// SaleListQuery holds the pagination and filter options for listing sales.
+// From is inclusive and To is exclusive.
+type SaleListQuery struct {
+	PaginationQuery
+	UserID *uuid.UUID
+	From   *time.Time
+	To     *time.Time
+}
@@ -0,0 +1,17 @@
+package request
+
+import "github.com/google/uuid"
+
+type CreateShelfRequest struct {
+	Name string `json:"name" validate:"required,max=50" example:"A-01"`
+}
+
+type UpdateShelfRequest struct {
+	Name string `json:"name" validate:"required,max=50" example:"A-02"`
+}
+
+// ShelfListQuery is the pagination query for shelves, optionally scoped to one warehouse.
+type ShelfListQuery struct {
+	PaginationQuery
+	WarehouseID *uuid.UUID `json:"warehouse_id"`
+}
@@ -0,0 +1,29 @@
+package request
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockMovementRequest receives (IN) or issues (OUT) a positive quantity of an item.
+type StockMovementRequest struct {
+	Quantity    int        `json:"quantity" validate:"required,gt=0" example:"24"`
+	ReferenceID *uuid.UUID `json:"reference_id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Description string     `json:"description" example:"Delivery from PT Sumber Makmur"`
+}
+
+// StockAdjustmentRequest sets an item's stock to a counted value, e.g. after a stock take.
+type StockAdjustmentRequest struct {
+	NewStock int    `json:"new_stock" validate:"gte=0" example:"20"`
+	Reason   string `json:"reason" validate:"required" example:"Monthly stock take: 4 cans damaged"`
+}
+
+// StockMovementQuery holds the pagination and filter options for an item's movement history.
+// From is inclusive and To is exclusive.
+type StockMovementQuery struct {
+	PaginationQuery
+	Type string // IN, OUT or ADJUSTMENT
+	From *time.Time
+	To   *time.Time
+}
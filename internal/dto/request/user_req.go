@@ -1,13 +1,38 @@
 package request
 
+import "github.com/google/uuid"
+
 type CreateUserRequest struct {
-	Name     string `json:"name" validate:"required,min=3" example:"Staff Satu"`
-	Email    string `json:"email" validate:"required,email" example:"staff@gmail.com"`
-	Password string `json:"password" validate:"required,min=6" example:"password123"`
-	Role     string `json:"role" validate:"required" example:"staff"`
+	Name     string `json:"name" validate:"required,min=3,max=100" example:"Staff Satu"`
+	Email    string `json:"email" validate:"required,email,max=100" example:"staff@gmail.com"`
+	Password string `json:"password" validate:"required,password" example:"Passw0rd-baru"`
+	Role     string `json:"role" validate:"required,max=50" example:"staff"` // any existing role, see GET /roles
 }
 
 type UpdateUserRequest struct {
-	Name string `json:"name" validate:"required" example:"Staff Satu Update"`
-	Role string `json:"role" validate:"required,oneof=admin staff" example:"admin"`
+	Name string `json:"name" validate:"required,max=100" example:"Staff Satu Update"`
+	Role string `json:"role" validate:"required,max=50" example:"admin"`
+}
+
+// SetUserWarehousesRequest replaces the warehouses a user is assigned to. An empty list removes
+// every assignment, which leaves a user without warehouses:all unable to see any stock.
+type SetUserWarehousesRequest struct {
+	WarehouseIDs []uuid.UUID `json:"warehouse_ids" validate:"required,max=100,dive,required" example:"0b8f6c3e-3c55-4d7e-9b1a-2f4e8c9d1a7b"`
+}
+
+// UpdateProfileRequest is what a user may change about themselves.
+type UpdateProfileRequest struct {
+	Name string `json:"name" validate:"required,min=3,max=100" example:"Staff Satu"`
+}
+
+// ChangePasswordRequest changes the caller's own password. The new password must meet the password policy.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required" example:"password123"`
+	NewPassword     string `json:"new_password" validate:"required,password" example:"n3w-Passw0rd"`
+}
+
+// UserListQuery holds the query parameters accepted by the user list endpoint.
+type UserListQuery struct {
+	PaginationQuery
+	IncludeDeleted bool `json:"include_deleted"` // needs users:restore
 }
@@ -0,0 +1,11 @@
+package request
+
+type CreateWarehouseRequest struct {
+	Name     string `json:"name" validate:"required,max=100" example:"Gudang Utama"`
+	Location string `json:"location" example:"Jl. Industri No. 1, Bekasi"`
+}
+
+type UpdateWarehouseRequest struct {
+	Name     string `json:"name" validate:"required,max=100" example:"Gudang Utama"`
+	Location string `json:"location" example:"Jl. Industri No. 2, Bekasi"`
+}
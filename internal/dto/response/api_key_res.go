@@ -0,0 +1,46 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyResponse describes an API key without the key itself.
+type APIKeyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name" example:"Gudang A scanner"`
+	KeyPrefix   string     `json:"key_prefix" example:"isk_Q2hhbmdl"`
+	Permissions []string   `json:"permissions" example:"stock:move"` // null: everything your role has
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is returned once, when the key is minted. The key cannot be shown again.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"isk_Q2hhbmdlTWVQbGVhc2VJdHNOb3RBUmVhbEtleQ"`
+}
+
+func ToAPIKeyResponse(key *model.APIKey) APIKeyResponse {
+	var permissions []string
+	if key.Permissions != nil {
+		permissions = make([]string, 0, len(key.Permissions))
+		for _, p := range key.Permissions {
+			permissions = append(permissions, string(p))
+		}
+	}
+
+	return APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		KeyPrefix:   key.KeyPrefix,
+		Permissions: permissions,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		CreatedAt:   key.CreatedAt,
+	}
+}
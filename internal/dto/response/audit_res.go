@@ -0,0 +1,52 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type AuditEventResponse struct {
+	ID         uuid.UUID         `json:"id"`
+	Seq        int64             `json:"seq" example:"42"`
+	ActorID    *uuid.UUID        `json:"actor_id"`   // Nil for changes made outside a request
+	APIKeyID   *uuid.UUID        `json:"api_key_id"` // Set when the actor used an API key
+	Action     model.AuditAction `json:"action" swaggertype:"string" example:"item.price_change"`
+	EntityType model.AuditEntity `json:"entity_type" swaggertype:"string" enums:"user,item,warehouse,stock"`
+	EntityID   uuid.UUID         `json:"entity_id"`
+	Changes    json.RawMessage   `json:"changes" swaggertype:"object"` // {"field": {"old": ..., "new": ...}}
+	RequestID  string            `json:"request_id"`
+	IPAddress  string            `json:"ip_address" example:"203.0.113.7"`
+	CreatedAt  time.Time         `json:"created_at"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+func ToAuditEventResponse(event *model.AuditEvent) AuditEventResponse {
+	return AuditEventResponse{
+		ID:         event.ID,
+		Seq:        event.Seq,
+		ActorID:    event.ActorID,
+		APIKeyID:   event.APIKeyID,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Changes:    event.Changes,
+		RequestID:  event.RequestID,
+		IPAddress:  event.IPAddress,
+		CreatedAt:  event.CreatedAt,
+		PrevHash:   event.PrevHash,
+		Hash:       event.Hash,
+	}
+}
+
+// AuditVerificationResponse reports whether the audit trail is still intact.
+type AuditVerificationResponse struct {
+	Valid       bool   `json:"valid"`
+	Checked     int64  `json:"checked" example:"1200"`     // Events verified before stopping
+	BrokenAtSeq *int64 `json:"broken_at_seq" example:"57"` // First event that does not match its chain, if any
+	LastHash    string `json:"last_hash"`                  // Hash of the last intact event
+}
@@ -1,8 +1,85 @@
 package response
 
-// AuthResponse defines the JSON response sent back after a successful login.
-// It combines the JWT token and the sanitized UserResponse.
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// AuthResponse defines the JSON response sent back after a successful login or refresh.
+// It combines the token pair and the sanitized UserResponse.
 type AuthResponse struct {
-	AccessToken string       `json:"access_token"`
-	User        UserResponse `json:"user"`
+	AccessToken      string       `json:"access_token"`
+	AccessExpiresAt  time.Time    `json:"access_expires_at"`
+	RefreshToken     string       `json:"refresh_token"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	User             UserResponse `json:"user"`
+
+	// Only set when this login also finished a mandatory 2FA enrollment. Shown once.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// TwoFactorChallengeResponse is returned by login instead of tokens when a second factor is needed.
+// Send the challenge token with a code to /auth/login/2fa. With enrollment_required the user has no
+// authenticator yet and must call /auth/login/2fa/setup first.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired  bool      `json:"two_factor_required" example:"true"`
+	EnrollmentRequired bool      `json:"enrollment_required" example:"false"`
+	ChallengeToken     string    `json:"challenge_token" example:"Vb0y0m6J2c1b8kq3rYVQmJtJ4n5o9pXHh2uZ7aC1dE0"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+// OIDCLoginResponse starts a single sign-on login. Send the user's browser to authorization_url;
+// the provider redirects back with code and state, which go to /auth/oidc/callback.
+type OIDCLoginResponse struct {
+	AuthorizationURL string    `json:"authorization_url" example:"https://sso.example.com/authorize?client_id=inventory&code_challenge=...&response_type=code&state=..."`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// TwoFactorSetupResponse carries a new, not yet confirmed TOTP secret. Show provisioning_uri as a QR code.
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Inventory%20System:admin@gmail.com?algorithm=SHA1&digits=6&issuer=Inventory+System&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+// TwoFactorStatusResponse tells the caller whether 2FA is on and whether their role requires it.
+type TwoFactorStatusResponse struct {
+	Enabled   bool       `json:"enabled"`
+	EnabledAt *time.Time `json:"enabled_at,omitempty"`
+	Mandatory bool       `json:"mandatory"`
+}
+
+// RecoveryCodesResponse lists fresh recovery codes. They are shown once; each works once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3j9d-x8q2m,p0w7e-r5t1y"`
+}
+
+// LoginSessionResponse describes one place the user is signed in.
+type LoginSessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	IPAddress  string    `json:"ip_address" example:"203.0.113.7"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64)"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // true for the session making this request
+}
+
+func ToLoginSessionResponse(login *model.LoginSession, current bool) LoginSessionResponse {
+	return LoginSessionResponse{
+		ID:         login.ID,
+		IPAddress:  login.IPAddress,
+		UserAgent:  login.UserAgent,
+		CreatedAt:  login.CreatedAt,
+		LastSeenAt: login.LastSeenAt,
+		ExpiresAt:  login.ExpiresAt,
+		Current:    current,
+	}
+}
+
+// RevokedSessionsResponse reports how many sessions a bulk revoke closed.
+type RevokedSessionsResponse struct {
+	Revoked int64 `json:"revoked" example:"3"`
 }
@@ -0,0 +1,43 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type CategoryResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Version     int        `json:"version" example:"3"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func ToCategoryResponse(category *model.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:          category.ID,
+		Version:     category.Version,
+		Name:        category.Name,
+		Description: category.Description,
+		ParentID:    category.ParentID,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
+
+// CategoryTreeNode is a category with its nested children.
+// ItemCount counts items assigned directly to the node; TotalItemCount includes all descendants.
+type CategoryTreeNode struct {
+	ID             uuid.UUID          `json:"id"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	ParentID       *uuid.UUID         `json:"parent_id"`
+	ItemCount      int64              `json:"item_count"`
+	TotalItemCount int64              `json:"total_item_count"`
+	Children       []CategoryTreeNode `json:"children"`
+}
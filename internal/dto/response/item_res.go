@@ -0,0 +1,45 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type ItemResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Version       int        `json:"version" example:"3"`
+	SKU           string     `json:"sku"`
+	Name          string     `json:"name"`
+	CategoryID    *uuid.UUID `json:"category_id"`
+	CategoryName  string     `json:"category_name"`
+	ShelfID       *uuid.UUID `json:"shelf_id"`
+	ShelfName     string     `json:"shelf_name"`
+	WarehouseID   *uuid.UUID `json:"warehouse_id"`
+	WarehouseName string     `json:"warehouse_name"`
+	Stock         int        `json:"stock"`
+	Price         string     `json:"price" example:"7500.00"` // Fixed 2 decimals, as a string to keep it exact
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func ToItemResponse(item *model.Item) ItemResponse {
+	return ItemResponse{
+		ID:            item.ID,
+		Version:       item.Version,
+		SKU:           item.SKU,
+		Name:          item.Name,
+		CategoryID:    item.CategoryID,
+		CategoryName:  item.CategoryName,
+		ShelfID:       item.ShelfID,
+		ShelfName:     item.ShelfName,
+		WarehouseID:   item.WarehouseID,
+		WarehouseName: item.WarehouseName,
+		Stock:         item.Stock,
+		Price:         item.Price.StringFixed(2),
+		CreatedAt:     item.CreatedAt,
+		UpdatedAt:     item.UpdatedAt,
+	}
+}
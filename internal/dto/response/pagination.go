@@ -32,3 +32,24 @@ func NewPaginatedResponse[T any](data []T, page, limit int, totalItems int64) Pa
 // UserPaginatedResponse is a concrete type for Swagger documentation.
 // This helps 'swag' parser find the definition easily.
 type UserPaginatedResponse PaginatedResponse[UserResponse]
+
+// WarehousePaginatedResponse is a concrete type for Swagger documentation.
+type WarehousePaginatedResponse PaginatedResponse[WarehouseResponse]
+
+// ShelfPaginatedResponse is a concrete type for Swagger documentation.
+type ShelfPaginatedResponse PaginatedResponse[ShelfResponse]
+
+// CategoryPaginatedResponse is a concrete type for Swagger documentation.
+type CategoryPaginatedResponse PaginatedResponse[CategoryResponse]
+
+// ItemPaginatedResponse is a concrete type for Swagger documentation.
+type ItemPaginatedResponse PaginatedResponse[ItemResponse]
+
+// StockMovementPaginatedResponse is a concrete type for Swagger documentation.
+type StockMovementPaginatedResponse PaginatedResponse[StockMovementResponse]
+
+// SalePaginatedResponse is a concrete type for Swagger documentation.
+type SalePaginatedResponse PaginatedResponse[SaleResponse]
+
+// AuditPaginatedResponse is a concrete type for Swagger documentation.
+type AuditPaginatedResponse PaginatedResponse[AuditEventResponse]
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+)
+
+type RoleResponse struct {
+	Name        string    `json:"name" example:"warehouse_lead"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"` // built-in roles cannot be deleted
+	Permissions []string  `json:"permissions" example:"stock:adjust,stock:move"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PermissionResponse describes one permission that can be granted to a role.
+type PermissionResponse struct {
+	Name        string `json:"name" example:"stock:adjust"`
+	Description string `json:"description" example:"Adjust stock levels after a count"`
+}
+
+func ToRoleResponse(role *model.Role) RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		permissions = append(permissions, string(p))
+	}
+
+	return RoleResponse{
+		Name:        string(role.Name),
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type SaleItemResponse struct {
+	ID               uuid.UUID `json:"id"`
+	ItemID           uuid.UUID `json:"item_id"`
+	SKU              string    `json:"sku"`
+	Name             string    `json:"name"`
+	Quantity         int       `json:"quantity"`
+	ReturnedQuantity int       `json:"returned_quantity"`
+	UnitPrice        string    `json:"unit_price" example:"7500.00"`
+	Subtotal         string    `json:"subtotal" example:"15000.00"`
+}
+
+type SaleResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	CashierName string             `json:"cashier_name"`
+	TotalAmount string             `json:"total_amount" example:"15000.00"`
+	Status      model.SaleStatus   `json:"status" swaggertype:"string" enums:"completed,voided"`
+	VoidedAt    *time.Time         `json:"voided_at,omitempty"`
+	VoidedBy    *uuid.UUID         `json:"voided_by,omitempty"`
+	VoidReason  string             `json:"void_reason,omitempty"`
+	Items       []SaleItemResponse `json:"items,omitempty"` // Only included when fetching a single sale
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func ToSaleResponse(sale *model.Sale) SaleResponse {
+	res := SaleResponse{
+		ID:          sale.ID,
+		UserID:      sale.UserID,
+		CashierName: sale.CashierName,
+		TotalAmount: sale.TotalAmount.StringFixed(2),
+		Status:      sale.Status,
+		VoidedAt:    sale.VoidedAt,
+		VoidedBy:    sale.VoidedBy,
+		VoidReason:  sale.VoidReason,
+		CreatedAt:   sale.CreatedAt,
+	}
+
+	if len(sale.Items) > 0 {
+		res.Items = make([]SaleItemResponse, 0, len(sale.Items))
+		for _, line := range sale.Items {
+			res.Items = append(res.Items, SaleItemResponse{
+				ID:               line.ID,
+				ItemID:           line.ItemID,
+				SKU:              line.SKU,
+				Name:             line.Name,
+				Quantity:         line.Quantity,
+				ReturnedQuantity: line.ReturnedQuantity,
+				UnitPrice:        line.UnitPrice.StringFixed(2),
+				Subtotal:         line.Subtotal.StringFixed(2),
+			})
+		}
+	}
+	return res
+}
+
+type SaleReturnItemResponse struct {
+	ID           uuid.UUID `json:"id"`
+	SaleItemID   uuid.UUID `json:"sale_item_id"`
+	ItemID       uuid.UUID `json:"item_id"`
+	SKU          string    `json:"sku"`
+	Name         string    `json:"name"`
+	Quantity     int       `json:"quantity"`
+	RefundAmount string    `json:"refund_amount" example:"7500.00"`
+}
+
+type SaleReturnResponse struct {
+	ID           uuid.UUID                `json:"id"`
+	SaleID       uuid.UUID                `json:"sale_id"`
+	UserID       uuid.UUID                `json:"user_id"`
+	Reason       string                   `json:"reason"`
+	RefundAmount string                   `json:"refund_amount" example:"7500.00"`
+	Items        []SaleReturnItemResponse `json:"items"`
+	CreatedAt    time.Time                `json:"created_at"`
+}
+
+func ToSaleReturnResponse(ret *model.SaleReturn) SaleReturnResponse {
+	res := SaleReturnResponse{
+		ID:           ret.ID,
+		SaleID:       ret.SaleID,
+		UserID:       ret.UserID,
+		Reason:       ret.Reason,
+		RefundAmount: ret.RefundAmount.StringFixed(2),
+		Items:        make([]SaleReturnItemResponse, 0, len(ret.Items)),
+		CreatedAt:    ret.CreatedAt,
+	}
+
+	for _, line := range ret.Items {
+		res.Items = append(res.Items, SaleReturnItemResponse{
+			ID:           line.ID,
+			SaleItemID:   line.SaleItemID,
+			ItemID:       line.ItemID,
+			SKU:          line.SKU,
+			Name:         line.Name,
+			Quantity:     line.Quantity,
+			RefundAmount: line.RefundAmount.StringFixed(2),
+		})
+	}
+	return res
+}
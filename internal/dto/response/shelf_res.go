@@ -0,0 +1,62 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type ShelfResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Version       int       `json:"version" example:"3"`
+	WarehouseID   uuid.UUID `json:"warehouse_id"`
+	WarehouseName string    `json:"warehouse_name"`
+	Name          string    `json:"name"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func ToShelfResponse(shelf *model.Shelf) ShelfResponse {
+	return ShelfResponse{
+		ID:            shelf.ID,
+		Version:       shelf.Version,
+		WarehouseID:   shelf.WarehouseID,
+		WarehouseName: shelf.WarehouseName,
+		Name:          shelf.Name,
+		CreatedAt:     shelf.CreatedAt,
+		UpdatedAt:     shelf.UpdatedAt,
+	}
+}
+
+type ShelfItemResponse struct {
+	ItemID   uuid.UUID `json:"item_id"`
+	SKU      string    `json:"sku"`
+	Name     string    `json:"name"`
+	Quantity int       `json:"quantity"`
+}
+
+// ShelfContentsResponse tells warehouse staff what is physically on a shelf.
+type ShelfContentsResponse struct {
+	Shelf         ShelfResponse       `json:"shelf"`
+	Items         []ShelfItemResponse `json:"items"`
+	TotalQuantity int                 `json:"total_quantity"`
+}
+
+func ToShelfContentsResponse(shelf *model.Shelf, contents []*model.ShelfContent) ShelfContentsResponse {
+	res := ShelfContentsResponse{
+		Shelf: ToShelfResponse(shelf),
+		Items: make([]ShelfItemResponse, 0, len(contents)),
+	}
+	for _, c := range contents {
+		res.Items = append(res.Items, ShelfItemResponse{
+			ItemID:   c.ItemID,
+			SKU:      c.SKU,
+			Name:     c.Name,
+			Quantity: c.Quantity,
+		})
+		res.TotalQuantity += c.Quantity
+	}
+	return res
+}
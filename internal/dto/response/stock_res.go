@@ -0,0 +1,35 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type StockMovementResponse struct {
+	ID           uuid.UUID          `json:"id"`
+	ItemID       uuid.UUID          `json:"item_id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	MovementType model.MovementType `json:"movement_type" swaggertype:"string" enums:"IN,OUT,ADJUSTMENT"`
+	Quantity     int                `json:"quantity"` // Signed delta for ADJUSTMENT
+	BalanceAfter int                `json:"balance_after"`
+	ReferenceID  *uuid.UUID         `json:"reference_id"`
+	Description  string             `json:"description"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+func ToStockMovementResponse(log *model.StockLog) StockMovementResponse {
+	return StockMovementResponse{
+		ID:           log.ID,
+		ItemID:       log.ItemID,
+		UserID:       log.UserID,
+		MovementType: log.MovementType,
+		Quantity:     log.Quantity,
+		BalanceAfter: log.BalanceAfter,
+		ReferenceID:  log.ReferenceID,
+		Description:  log.Description,
+		CreatedAt:    log.CreatedAt,
+	}
+}
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"time"
+
 	"inventory-system/internal/model"
 
 	"github.com/google/uuid"
@@ -9,17 +11,51 @@ import (
 // UserResponse represents the safe, public user data returned to the client.
 // Notice it does not include sensitive fields like PasswordHash.
 type UserResponse struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Email string    `json:"email"`
-	Role  string    `json:"role"`
+	ID      uuid.UUID `json:"id"`
+	Version int       `json:"version" example:"3"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Role    string    `json:"role"`
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // only set when listing with include_deleted
+}
+
+// LockoutResponse shows a user's failed logins and whether the account is currently locked.
+type LockoutResponse struct {
+	UserID              uuid.UUID  `json:"user_id"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email"`
+	Role                string     `json:"role"`
+	FailedLoginAttempts int        `json:"failed_login_attempts" example:"5"`
+	LastFailedLoginAt   *time.Time `json:"last_failed_login_at"`
+	LockedUntil         *time.Time `json:"locked_until"` // null when the account is not locked
+	Locked              bool       `json:"locked"`
+}
+
+func ToLockoutResponse(user *model.User, now time.Time) LockoutResponse {
+	res := LockoutResponse{
+		UserID:              user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		Role:                string(user.Role),
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LastFailedLoginAt:   user.LastFailedLoginAt,
+	}
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		res.LockedUntil = user.LockedUntil
+		res.Locked = true
+	}
+	return res
 }
 
 func ToUserResponse(user *model.User) UserResponse {
 	return UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-		Role:  string(user.Role),
+		ID:      user.ID,
+		Version: user.Version,
+		Name:    user.Name,
+		Email:   user.Email,
+		Role:    string(user.Role),
+
+		DeletedAt: user.DeletedAt,
 	}
 }
@@ -0,0 +1,29 @@
+package response
+
+import (
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type WarehouseResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Version   int       `json:"version" example:"3"`
+	Name      string    `json:"name"`
+	Location  string    `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToWarehouseResponse(warehouse *model.Warehouse) WarehouseResponse {
+	return WarehouseResponse{
+		ID:        warehouse.ID,
+		Version:   warehouse.Version,
+		Name:      warehouse.Name,
+		Location:  warehouse.Location,
+		CreatedAt: warehouse.CreatedAt,
+		UpdatedAt: warehouse.UpdatedAt,
+	}
+}
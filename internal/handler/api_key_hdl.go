@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	logger        *zap.Logger
+}
+
+// NewAPIKeyHandler initializes the APIKeyHandler with necessary dependencies.
+func NewAPIKeyHandler(apiKeyService service.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Mint a named API key for a script or device. Send it in the `X-API-Key` header instead of `Authorization`.
+// @Description  The key acts as you, limited to `role` or `permissions` if given, and never grants more than your current role.
+// @Description  The key is shown only in this response. Needs a login session; API keys cannot create API keys.
+// @Tags         Me
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateAPIKeyRequest true "API key payload"
+// @Success      201  {object}  utils.Response{data=response.CreatedAPIKeyResponse} "API key created successfully"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key, or granting permissions you do not have"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.CreateAPIKeyRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.apiKeyService.CreateAPIKey(r.Context(), actor, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusCreated, "API key created successfully", res)
+}
+
+// GetAPIKeys godoc
+// @Summary      List my API keys
+// @Description  List your API keys that have not been revoked, newest first. Expired keys are listed until you revoke them.
+// @Tags         Me
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]response.APIKeyResponse} "API keys retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.apiKeyService.GetAPIKeys(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "API keys retrieved successfully", res)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Disable one of your API keys immediately.
+// @Tags         Me
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "API key UUID"
+// @Success      200  {object}  utils.Response "API key revoked successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      404  {object}  utils.Response "API key not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	keyID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid API key ID format", nil)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(r.Context(), userID, keyID); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("API key revoked", zap.String("request_id", reqID), zap.String("api_key_id", keyID.String()))
+	utils.Success(w, r, http.StatusOK, "API key revoked successfully", nil)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+	logger       *zap.Logger
+}
+
+// NewAuditHandler initializes the AuditHandler with necessary dependencies.
+func NewAuditHandler(auditService service.AuditService, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// GetAuditEvents godoc
+// @Summary      List audit events
+// @Description  Retrieve the paginated audit trail of changes to users, items, prices, warehouses and stock adjustments, newest first.
+// @Description  Each event holds a `{"field": {"old": ..., "new": ...}}` diff of what changed.
+// @Description  `from`/`to` accept `YYYY-MM-DD` or RFC 3339; a bare `to` date includes that whole day.
+// @Description  **Required Permission:** `audit:read`
+// @Tags         Audit
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page         query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit        query     int     false  "Number of items per page (default: 10)"
+// @Param        actor_id     query     string  false  "Only events caused by this user"
+// @Param        action       query     string  false  "Filter by action, e.g. item.price_change"
+// @Param        entity_type  query     string  false  "Filter by entity type" Enums(user, item, warehouse, stock)
+// @Param        entity_id    query     string  false  "Only events about this entity"
+// @Param        from         query     string  false  "Only events at or after this date/time"
+// @Param        to           query     string  false  "Only events before this date/time"
+// @Success      200  {object}  utils.Response{data=response.AuditPaginatedResponse} "Audit events retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid filter"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/audit [get]
+func (h *AuditHandler) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := request.AuditListQuery{
+		PaginationQuery: parsePaginationQuery(r),
+		Action:          r.URL.Query().Get("action"),
+		EntityType:      r.URL.Query().Get("entity_type"),
+	}
+
+	var err error
+	if query.ActorID, err = queryUUID(r, "actor_id"); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if query.EntityID, err = queryUUID(r, "entity_id"); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if query.From, err = queryTime(r, "from", false); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if query.To, err = queryTime(r, "to", true); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := h.auditService.GetAuditEvents(r.Context(), query)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Audit events retrieved successfully", result)
+}
+
+// VerifyAuditChain godoc
+// @Summary      Verify the audit trail
+// @Description  Recompute the hash chain over every audit event. `valid` is false, and `broken_at_seq` names
+// @Description  the first bad event, when an event was edited, removed or inserted outside the application.
+// @Description  **Required Permission:** `audit:read`
+// @Tags         Audit
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=response.AuditVerificationResponse} "Audit trail verified"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/audit/verify [get]
+func (h *AuditHandler) VerifyAuditChain(w http.ResponseWriter, r *http.Request) {
+	res, err := h.auditService.VerifyAuditChain(r.Context())
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Audit trail verified", res)
+}
@@ -1,11 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
 	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	customMiddleware "inventory-system/internal/middleware"
 	"inventory-system/internal/service"
 	"inventory-system/pkg/utils"
 
@@ -13,6 +14,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// oidcBrowserCookie ties a single sign-on login to the browser that started it. It is only sent
+// back to the OIDC endpoints, so the frontend must call them with credentials included.
+const oidcBrowserCookie = "oidc_login"
+
 // AuthHandler is the concrete implementation of AuthHandler.
 type AuthHandler struct {
 	authService service.AuthService
@@ -29,14 +34,20 @@ func NewAuthHandler(authService service.AuthService, logger *zap.Logger) *AuthHa
 
 // Login godoc
 // @Summary      User Login
-// @Description  Authenticate user with email and password, and return a stateful UUID session token.
+// @Description  Authenticate user with email and password. Returns a short-lived access token and a single-use refresh token.
+// @Description  Failed logins slow the account down progressively and lock it for a while after too many; attempts per IP are rate limited.
+// @Description  When the account uses two-factor authentication, or its role requires it, the response is 202 with a challenge
+// @Description  to finish at /auth/login/2fa instead of tokens.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        request body request.LoginRequest true "Login credentials"
 // @Success      200  {object}  utils.Response{data=response.AuthResponse} "Login successful"
+// @Success      202  {object}  utils.Response{data=response.TwoFactorChallengeResponse} "Two-factor authentication required"
 // @Failure      400  {object}  utils.Response "Invalid request format"
 // @Failure      401  {object}  utils.Response "Invalid email or password"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP, or the account is throttled or locked"
 // @Failure      500  {object}  utils.Response "Internal server error"
 // @Router       /api/v1/auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -47,28 +58,27 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req request.LoginRequest
 
 	// 1. Decode the JSON payload from the request body into the DTO.
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := utils.BindJSON(w, r, &req); err != nil {
 		h.logger.Warn("Failed to decode login request body",
 			zap.String("request_id", reqID),
 			zap.Error(err),
 		)
-		utils.Error(w, r, http.StatusBadRequest, "Invalid request format", nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
 	// 2. Pass the decoded request to the Service layer for business logic processing.
-	res, err := h.authService.Login(r.Context(), req)
+	res, challenge, err := h.authService.Login(r.Context(), req, customMiddleware.ClientInfo(r))
 	if err != nil {
-		// Handle expected business errors (e.g., wrong credentials)
-		if err.Error() == "invalid email or password" {
-			h.logger.Warn("Unauthorized login attempt", zap.String("request_id", reqID), zap.String("email", req.Email))
-			utils.Error(w, r, http.StatusUnauthorized, err.Error(), nil)
-			return
-		}
+		// Expected errors (e.g., wrong credentials) carry their own status; anything else is a 500.
+		h.logger.Warn("Login failed", zap.String("request_id", reqID), zap.String("email", req.Email), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
 
-		// Handle unexpected system errors (e.g., database down, JWT generation failed)
-		h.logger.Error("Internal server error during login", zap.String("request_id", reqID), zap.Error(err))
-		utils.Error(w, r, http.StatusInternalServerError, "Internal server error", nil)
+	// The password was right but a second factor is still needed; no tokens yet.
+	if challenge != nil {
+		utils.Success(w, r, http.StatusAccepted, "Two-factor authentication required", challenge)
 		return
 	}
 
@@ -77,15 +87,269 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, r, http.StatusOK, "Login successful", res)
 }
 
+// StartOIDCLogin godoc
+// @Summary      Start a single sign-on login
+// @Description  Begin signing in through the configured OpenID Connect provider. Send the browser to authorization_url;
+// @Description  the provider redirects to the configured redirect URL with code and state, which go to /auth/oidc/callback.
+// @Description  The login must be finished within 10 minutes, from the same browser: the response sets an HttpOnly cookie
+// @Description  that the callback requires. Returns 404 when single sign-on is not configured.
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=response.OIDCLoginResponse} "Authorization URL created"
+// @Failure      404  {object}  utils.Response "Single sign-on is not configured"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error, or the provider is unreachable"
+// @Router       /api/v1/auth/oidc/login [get]
+func (h *AuthHandler) StartOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	res, binding, err := h.authService.StartOIDCLogin(r.Context())
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcBrowserCookie,
+		Value:    binding,
+		Path:     "/api/v1/auth/oidc",
+		Expires:  res.ExpiresAt,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	utils.Success(w, r, http.StatusOK, "Authorization URL created", res)
+}
+
+// CompleteOIDCLogin godoc
+// @Summary      Finish a single sign-on login
+// @Description  Exchange the code and state from the provider's redirect for tokens. The provider must have verified the email
+// @Description  address, which is matched to an existing user; unknown addresses get an account with the default role only when
+// @Description  auto-provisioning is on. Two-factor authentication applies as with a password login: the response may be 202 with a challenge.
+// @Description  The request must carry the cookie set by /auth/oidc/login in the same browser.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.OIDCCallbackRequest true "Code and state from the redirect"
+// @Success      200  {object}  utils.Response{data=response.AuthResponse} "Login successful"
+// @Success      202  {object}  utils.Response{data=response.TwoFactorChallengeResponse} "Two-factor authentication required"
+// @Failure      400  {object}  utils.Response "Invalid request format"
+// @Failure      401  {object}  utils.Response "State invalid, expired or started in another browser, or the provider did not confirm the identity"
+// @Failure      403  {object}  utils.Response "Email not verified, or no account for it"
+// @Failure      404  {object}  utils.Response "Single sign-on is not configured"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/oidc/callback [post]
+func (h *AuthHandler) CompleteOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.OIDCCallbackRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode OIDC callback request body", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	var binding string
+	if cookie, err := r.Cookie(oidcBrowserCookie); err == nil {
+		binding = cookie.Value
+	}
+	// The state is used up either way, so the cookie has done its job.
+	http.SetCookie(w, &http.Cookie{Name: oidcBrowserCookie, Path: "/api/v1/auth/oidc", MaxAge: -1, HttpOnly: true, Secure: true, SameSite: http.SameSiteLaxMode})
+
+	res, challenge, err := h.authService.CompleteOIDCLogin(r.Context(), req, binding, customMiddleware.ClientInfo(r))
+	if err != nil {
+		h.logger.Warn("OIDC login failed", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	if challenge != nil {
+		utils.Success(w, r, http.StatusAccepted, "Two-factor authentication required", challenge)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Login successful", res)
+}
+
+// CompleteTwoFactorLogin godoc
+// @Summary      Finish a two-factor login
+// @Description  Exchange the login challenge and a 6-digit authenticator code, or one of the recovery codes, for tokens.
+// @Description  If the challenge required enrollment, the code confirms the new authenticator and the response includes the recovery codes, shown only once.
+// @Description  A challenge expires after 5 minutes or 5 wrong codes.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.TwoFactorLoginRequest true "Challenge token and code"
+// @Success      200  {object}  utils.Response{data=response.AuthResponse} "Login successful"
+// @Failure      400  {object}  utils.Response "Invalid request format"
+// @Failure      401  {object}  utils.Response "Challenge invalid or expired"
+// @Failure      409  {object}  utils.Response "Enrollment required but not started"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed, or the code is invalid"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/login/2fa [post]
+func (h *AuthHandler) CompleteTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.TwoFactorLoginRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode two-factor login request body", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.authService.CompleteTwoFactorLogin(r.Context(), req, customMiddleware.ClientInfo(r))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Login successful", res)
+}
+
+// SetupTwoFactorForLogin godoc
+// @Summary      Set up two-factor during login
+// @Description  For a login challenge with `enrollment_required`: create the authenticator secret. Scan the provisioning URI as a QR code,
+// @Description  then finish the login at /auth/login/2fa with a code from the app.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.LoginChallengeRequest true "Challenge token"
+// @Success      200  {object}  utils.Response{data=response.TwoFactorSetupResponse} "Two-factor setup started"
+// @Failure      400  {object}  utils.Response "Invalid request format"
+// @Failure      401  {object}  utils.Response "Challenge invalid or expired"
+// @Failure      409  {object}  utils.Response "Two-factor authentication is already enabled"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/login/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactorForLogin(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.LoginChallengeRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode two-factor setup request body", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.authService.SetupTwoFactorForLogin(r.Context(), req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Two-factor setup started", res)
+}
+
+// Refresh godoc
+// @Summary      Refresh the session
+// @Description  Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once;
+// @Description  replaying one that was already used revokes every token issued since that login.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.RefreshRequest true "Refresh token"
+// @Success      200  {object}  utils.Response{data=response.AuthResponse} "Session refreshed"
+// @Failure      400  {object}  utils.Response "Invalid request format"
+// @Failure      401  {object}  utils.Response "Refresh token invalid, expired or reused"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many refreshes from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.RefreshRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode refresh request body", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.authService.Refresh(r.Context(), req, customMiddleware.ClientInfo(r))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Session refreshed", res)
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Email a single-use password reset link. The response is the same whether or not the email is registered.
+// @Description  A user gets at most one email per cooldown (2 minutes by default); requests within it are accepted but send nothing.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ForgotPasswordRequest true "Account email"
+// @Success      200  {object}  utils.Response "Reset email sent if the account exists"
+// @Failure      400  {object}  utils.Response "Invalid request format"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.ForgotPasswordRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode forgot password request body", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	if err := h.authService.ForgotPassword(r.Context(), req); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary      Reset the password
+// @Description  Choose a new password with the token from the reset email. The token works once; every session of the user is signed out and their API keys are revoked.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ResetPasswordRequest true "Reset token and new password"
+// @Success      200  {object}  utils.Response "Password reset successfully"
+// @Failure      400  {object}  utils.Response "Invalid request format"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed, or the token is invalid or expired"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.ResetPasswordRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode reset password request body", zap.String("request_id", reqID))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(r.Context(), req); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Password reset successfully", nil)
+}
+
 // Logout godoc
 // @Summary      User Logout
-// @Description  Logout user by revoking their current UUID session.
+// @Description  Logout user by revoking their current session and every token issued since the same login.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object}  utils.Response "Logout successful"
 // @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
 // @Failure      500  {object}  utils.Response "Internal server error"
 // @Router       /api/v1/auth/logout [post]
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
@@ -108,10 +372,287 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// 2. Lempar ke Service
 	err := h.authService.Logout(r.Context(), tokenString)
 	if err != nil {
-		utils.Error(w, r, http.StatusInternalServerError, err.Error(), nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
 	// 3. Kembalikan response sukses
 	utils.Success(w, r, http.StatusOK, "Logout successful", nil)
 }
+
+// GetSessions godoc
+// @Summary      List my sessions
+// @Description  List every place the caller is signed in, most recently used first. The session making the request has `current: true`.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]response.LoginSessionResponse} "Sessions retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/sessions [get]
+func (h *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, userOK := currentUserID(r)
+	loginID, loginOK := currentLoginID(r)
+	if !userOK || !loginOK {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.authService.GetSessions(r.Context(), userID, loginID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Sessions retrieved successfully", res)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke one of my sessions
+// @Description  Sign out of one login: its access and refresh tokens stop working immediately.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Session UUID"
+// @Success      200  {object}  utils.Response "Session revoked successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      404  {object}  utils.Response "Session not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	loginID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid session ID format", nil)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), userID, loginID); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeAllSessions godoc
+// @Summary      Log out everywhere
+// @Description  Revoke every session of the caller, including the session making this request.
+// @Description  API keys keep working; revoke them with DELETE /me/api-keys/{id}.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=response.RevokedSessionsResponse} "All sessions revoked successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/auth/sessions [delete]
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	revoked, err := h.authService.RevokeAllSessions(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "All sessions revoked successfully", response.RevokedSessionsResponse{Revoked: revoked})
+}
+
+// GetTwoFactorStatus godoc
+// @Summary      Get my two-factor status
+// @Description  Whether two-factor authentication is on for the caller, and whether their role requires it.
+// @Tags         Me
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=response.TwoFactorStatusResponse} "Two-factor status retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/2fa [get]
+func (h *AuthHandler) GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.authService.GetTwoFactorStatus(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Two-factor status retrieved successfully", res)
+}
+
+// SetupTwoFactor godoc
+// @Summary      Start two-factor setup
+// @Description  Create a new authenticator secret. Scan the provisioning URI as a QR code, then confirm with /me/2fa/enable.
+// @Description  Calling it again before confirming replaces the secret.
+// @Tags         Me
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=response.TwoFactorSetupResponse} "Two-factor setup started"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      409  {object}  utils.Response "Two-factor authentication is already enabled"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.authService.SetupTwoFactor(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Two-factor setup started", res)
+}
+
+// EnableTwoFactor godoc
+// @Summary      Enable two-factor authentication
+// @Description  Confirm the secret from /me/2fa/setup with a code from the authenticator app. Returns the recovery codes, shown only once.
+// @Tags         Me
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.TwoFactorCodeRequest true "Authenticator code"
+// @Success      200  {object}  utils.Response{data=response.RecoveryCodesResponse} "Two-factor authentication enabled"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      409  {object}  utils.Response "Already enabled, or setup not started"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed, or the code is invalid"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.authService.EnableTwoFactor(r.Context(), userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Two-factor authentication enabled", res)
+}
+
+// DisableTwoFactor godoc
+// @Summary      Disable two-factor authentication
+// @Description  Turn two-factor authentication off. Needs the password and a current authenticator or recovery code.
+// @Description  A wrong password or code counts as a failed login and can lock the account.
+// @Description  Refused while the policy makes two-factor authentication mandatory for the caller's role.
+// @Tags         Me
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.DisableTwoFactorRequest true "Password and code"
+// @Success      200  {object}  utils.Response "Two-factor authentication disabled"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Two-factor authentication is mandatory for this role, or authenticated with an API key"
+// @Failure      409  {object}  utils.Response "Two-factor authentication is not enabled"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed, wrong password or invalid code"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP, or the account is locked after wrong passwords or codes"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.DisableTwoFactorRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(r.Context(), userID, req); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate my recovery codes
+// @Description  Replace every recovery code with a fresh set, shown only once. Needs a current authenticator or recovery code.
+// @Description  A wrong code counts as a failed login and can lock the account.
+// @Tags         Me
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.TwoFactorCodeRequest true "Authenticator or recovery code"
+// @Success      200  {object}  utils.Response{data=response.RecoveryCodesResponse} "Recovery codes regenerated"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      409  {object}  utils.Response "Two-factor authentication is not enabled"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed, or the code is invalid"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP, or the account is locked after wrong passwords or codes"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.authService.RegenerateRecoveryCodes(r.Context(), userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Recovery codes regenerated", res)
+}
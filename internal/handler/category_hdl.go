@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryService
+	logger          *zap.Logger
+}
+
+// NewCategoryHandler initializes the CategoryHandler with necessary dependencies.
+func NewCategoryHandler(categoryService service.CategoryService, logger *zap.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+// CreateCategory godoc
+// @Summary      Create a category
+// @Description  Create a new product category, optionally nested under a parent category.
+// @Description  **Required Permission:** `categories:write`
+// @Tags         Categories
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateCategoryRequest true "Category data payload"
+// @Success      201  {object}  utils.Response{data=response.CategoryResponse} "Category created successfully"
+// @Header       201  {string}  ETag  "Version of the category, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Parent category not found"
+// @Failure      409  {object}  utils.Response "Category name already exists under the same parent"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/categories [post]
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.CreateCategoryRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.categoryService.CreateCategory(r.Context(), req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusCreated, "Category created successfully", res)
+}
+
+// GetCategories godoc
+// @Summary      Get all categories
+// @Description  Retrieve a paginated, flat list of categories with optional name search.
+// @Tags         Categories
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit   query     int     false  "Number of items per page (default: 10)"
+// @Param        search  query     string  false  "Search filter for category name"
+// @Success      200  {object}  utils.Response{data=response.CategoryPaginatedResponse} "Categories retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/categories [get]
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	result, err := h.categoryService.GetCategories(r.Context(), parsePaginationQuery(r))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Categories retrieved successfully", result)
+}
+
+// GetCategoryTree godoc
+// @Summary      Get the category tree
+// @Description  Retrieve every category nested under its parent, with direct and total (including descendants) item counts.
+// @Tags         Categories
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]response.CategoryTreeNode} "Category tree retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.categoryService.GetCategoryTree(r.Context())
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Category tree retrieved successfully", tree)
+}
+
+// GetCategory godoc
+// @Summary      Get a category
+// @Description  Retrieve a single category by its UUID.
+// @Tags         Categories
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Category UUID"
+// @Success      200  {object}  utils.Response{data=response.CategoryResponse} "Category retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the category, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      404  {object}  utils.Response "Category not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/categories/{id} [get]
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid category ID format", nil)
+		return
+	}
+
+	res, err := h.categoryService.GetCategory(r.Context(), categoryID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Category retrieved successfully", res)
+}
+
+// UpdateCategory godoc
+// @Summary      Update a category
+// @Description  Update a category's name, description and parent. A null parent_id makes it a root category.
+// @Description  Moving a category under itself or one of its descendants is rejected.
+// @Description  `If-Match` must carry the category's current ETag.
+// @Description  **Required Permission:** `categories:write`
+// @Tags         Categories
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Category UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being updated"
+// @Param        request body request.UpdateCategoryRequest true "Update payload"
+// @Success      200  {object}  utils.Response{data=response.CategoryResponse} "Category updated successfully"
+// @Header       200  {string}  ETag  "New version of the category"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or payload"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Category or parent category not found"
+// @Failure      409  {object}  utils.Response "Name already taken or reparenting would create a cycle"
+// @Failure      412  {object}  utils.Response "Category was changed since it was read"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	categoryID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid category ID format", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	var req request.UpdateCategoryRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.categoryService.UpdateCategory(r.Context(), categoryID, version, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Category updated successfully", res)
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a category
+// @Description  Soft-delete a category. Child categories move up to its parent.
+// @Description  Its items are either moved to the parent (`items=move_to_parent`) or left uncategorised (`items=uncategorize`, default).
+// @Description  `If-Match` must carry the category's current ETag.
+// @Description  **Required Permission:** `categories:write`
+// @Tags         Categories
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id     path      string  true   "Category UUID"
+// @Param        items  query     string  false  "What to do with the category's items" Enums(uncategorize, move_to_parent)
+// @Param        If-Match  header  string  true  "ETag of the version being deleted"
+// @Success      200  {object}  utils.Response "Category deleted successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or item strategy"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Category not found"
+// @Failure      409  {object}  utils.Response "A subcategory clashes by name with a category under the parent it would move to"
+// @Failure      412  {object}  utils.Response "Category was changed since it was read"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid category ID format", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	strategy := model.CategoryItemStrategy(r.URL.Query().Get("items"))
+	if err := h.categoryService.DeleteCategory(r.Context(), categoryID, version, strategy); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Category deleted successfully", nil)
+}
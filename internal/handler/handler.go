@@ -7,13 +7,31 @@ import (
 )
 
 type Handler struct {
-	Auth AuthHandler
-	User UserHandler
+	Auth      AuthHandler
+	User      UserHandler
+	Warehouse WarehouseHandler
+	Shelf     ShelfHandler
+	Category  CategoryHandler
+	Item      ItemHandler
+	Stock     StockHandler
+	Sale      SaleHandler
+	Role      RoleHandler
+	APIKey    APIKeyHandler
+	Audit     AuditHandler
 }
 
 func NewHandler(service *service.Service, logger *zap.Logger) *Handler {
 	return &Handler{
-		Auth: *NewAuthHandler(service.Auth, logger),
-		User: *NewUserHandler(service.User, logger),
+		Auth:      *NewAuthHandler(service.Auth, logger),
+		User:      *NewUserHandler(service.User, logger),
+		Warehouse: *NewWarehouseHandler(service.Warehouse, logger),
+		Shelf:     *NewShelfHandler(service.Shelf, logger),
+		Category:  *NewCategoryHandler(service.Category, logger),
+		Item:      *NewItemHandler(service.Item, logger),
+		Stock:     *NewStockHandler(service.Stock, logger),
+		Sale:      *NewSaleHandler(service.Sale, logger),
+		Role:      *NewRoleHandler(service.Role, logger),
+		APIKey:    *NewAPIKeyHandler(service.APIKey, logger),
+		Audit:     *NewAuditHandler(service.Audit, logger),
 	}
 }
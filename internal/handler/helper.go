@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+	"inventory-system/pkg/apperror"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrIfMatchRequired = apperror.PreconditionRequired("IF_MATCH_REQUIRED", "send the ETag you last read as an If-Match header, so you cannot overwrite someone else's change")
+	ErrInvalidIfMatch  = apperror.BadRequest("INVALID_IF_MATCH", "If-Match must be a single ETag returned by this API")
+)
+
+// parsePaginationQuery reads the page, limit and search values from the URL query string.
+// Missing or malformed numbers are left as zero so the service can apply its defaults.
+func parsePaginationQuery(r *http.Request) request.PaginationQuery {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	return request.PaginationQuery{
+		Page:   page,
+		Limit:  limit,
+		Search: r.URL.Query().Get("search"),
+	}
+}
+
+// parseUUIDParam parses the named chi URL parameter as a UUID.
+func parseUUIDParam(r *http.Request, name string) (uuid.UUID, error) {
+	return uuid.Parse(chi.URLParam(r, name))
+}
+
+// currentUserID returns the authenticated user's ID stored by the auth middleware.
+func currentUserID(r *http.Request) (uuid.UUID, bool) {
+	userID, ok := r.Context().Value(customMiddleware.UserIDKey).(uuid.UUID)
+	return userID, ok
+}
+
+// currentActor returns the authenticated user with their role's permissions, as stored by the auth middleware.
+func currentActor(r *http.Request) (model.Actor, bool) {
+	userID, userOK := currentUserID(r)
+	role, roleOK := r.Context().Value(customMiddleware.UserRoleKey).(string)
+	permissions, permOK := r.Context().Value(customMiddleware.PermissionsKey).(model.Permissions)
+	if !userOK || !roleOK || !permOK {
+		return model.Actor{}, false
+	}
+	return model.Actor{UserID: userID, Role: model.UserRole(role), Permissions: permissions}, true
+}
+
+// currentLoginID returns the ID of the login the request's access token belongs to.
+func currentLoginID(r *http.Request) (uuid.UUID, bool) {
+	loginID, ok := r.Context().Value(customMiddleware.LoginSessionIDKey).(uuid.UUID)
+	return loginID, ok
+}
+
+// queryUUID parses an optional UUID query parameter. A missing value yields nil.
+func queryUUID(r *http.Request, name string) (*uuid.UUID, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format", name)
+	}
+	return &id, nil
+}
+
+// queryInt parses an optional integer query parameter. A missing value yields nil.
+func queryInt(r *http.Request, name string) (*int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be an integer", name)
+	}
+	return &n, nil
+}
+
+// queryBool parses an optional boolean query parameter. A missing value yields false.
+func queryBool(r *http.Request, name string) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: must be true or false", name)
+	}
+	return b, nil
+}
+
+// queryDecimal parses an optional decimal query parameter. A missing value yields nil.
+func queryDecimal(r *http.Request, name string) (*decimal.Decimal, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	d, err := decimal.NewFromString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be a decimal number", name)
+	}
+	return &d, nil
+}
+
+// queryTime parses an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter.
+// A bare date means the start of that day (UTC); with endOfDay it means the start of the
+// next day, so it can be used as an exclusive upper bound. A missing value yields nil.
+func queryTime(r *http.Request, name string, endOfDay bool) (*time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be YYYY-MM-DD or RFC 3339", name)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// setETag sends the version of the resource in the response as its ETag, e.g. "3".
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// ifMatchVersion returns the version named by the If-Match header. Updates and deletes of versioned
+// resources require it, so a client cannot overwrite a change it has not seen.
+func ifMatchVersion(r *http.Request) (int, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" {
+		return 0, ErrIfMatchRequired
+	}
+
+	// Only our own strong ETags make sense here: no "*", no weak W/"..." tags, no lists.
+	tag, quoted := strings.CutPrefix(raw, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	if !quoted || !closed {
+		return 0, ErrInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, ErrInvalidIfMatch
+	}
+	return version, nil
+}
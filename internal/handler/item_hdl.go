@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type ItemHandler struct {
+	itemService service.ItemService
+	logger      *zap.Logger
+}
+
+// NewItemHandler initializes the ItemHandler with necessary dependencies.
+func NewItemHandler(itemService service.ItemService, logger *zap.Logger) *ItemHandler {
+	return &ItemHandler{
+		itemService: itemService,
+		logger:      logger,
+	}
+}
+
+// CreateItem godoc
+// @Summary      Create an item
+// @Description  Add a new item to the catalogue. Items always start with zero stock; use stock movements to receive goods.
+// @Description  **Required Permission:** `items:write`
+// @Tags         Items
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateItemRequest true "Item data payload"
+// @Success      201  {object}  utils.Response{data=response.ItemResponse} "Item created successfully"
+// @Header       201  {string}  ETag  "Version of the item, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid request payload or direct stock edit"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Category or shelf not found"
+// @Failure      409  {object}  utils.Response "SKU already exists"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items [post]
+func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.CreateItemRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.itemService.CreateItem(r.Context(), req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("Item created successfully", zap.String("request_id", reqID), zap.String("sku", res.SKU))
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusCreated, "Item created successfully", res)
+}
+
+// GetItems godoc
+// @Summary      Get all items
+// @Description  Retrieve a paginated list of items with filtering and sorting.
+// @Description  `category_id` also matches items in descendant categories.
+// @Description  Without the `warehouses:all` permission only items on shelves in your assigned warehouses are listed.
+// @Tags         Items
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page          query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 10)"
+// @Param        search        query     string  false  "Search filter for item name or SKU"
+// @Param        category_id   query     string  false  "Filter by category UUID (includes descendants)"
+// @Param        shelf_id      query     string  false  "Filter by shelf UUID"
+// @Param        warehouse_id  query     string  false  "Filter by warehouse UUID"
+// @Param        min_price     query     string  false  "Minimum price (inclusive)"
+// @Param        max_price     query     string  false  "Maximum price (inclusive)"
+// @Param        min_stock     query     int     false  "Minimum stock (inclusive)"
+// @Param        max_stock     query     int     false  "Maximum stock (inclusive)"
+// @Param        sort          query     string  false  "Sort field (default: name)" Enums(name, sku, price, stock)
+// @Param        order         query     string  false  "Sort order (default: asc)" Enums(asc, desc)
+// @Success      200  {object}  utils.Response{data=response.ItemPaginatedResponse} "Items retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid filter or sort parameter"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items [get]
+func (h *ItemHandler) GetItems(w http.ResponseWriter, r *http.Request) {
+	query, err := parseItemListQuery(r)
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := h.itemService.GetItems(r.Context(), query)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Items retrieved successfully", result)
+}
+
+// GetItem godoc
+// @Summary      Get an item
+// @Description  Retrieve a single item by its UUID.
+// @Tags         Items
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Item UUID"
+// @Success      200  {object}  utils.Response{data=response.ItemResponse} "Item retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the item, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id} [get]
+func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request) {
+	itemID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid item ID format", nil)
+		return
+	}
+
+	res, err := h.itemService.GetItem(r.Context(), itemID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Item retrieved successfully", res)
+}
+
+// GetItemBySKU godoc
+// @Summary      Get an item by SKU
+// @Description  Retrieve a single item by its SKU (case-insensitive), e.g. from a barcode scanner.
+// @Tags         Items
+// @Security     BearerAuth
+// @Produce      json
+// @Param        sku  path      string  true  "Item SKU"
+// @Success      200  {object}  utils.Response{data=response.ItemResponse} "Item retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the item, to send as If-Match"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/sku/{sku} [get]
+func (h *ItemHandler) GetItemBySKU(w http.ResponseWriter, r *http.Request) {
+	res, err := h.itemService.GetItemBySKU(r.Context(), chi.URLParam(r, "sku"))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Item retrieved successfully", res)
+}
+
+// UpdateItem godoc
+// @Summary      Update an item
+// @Description  Update an item's catalogue data. Stock cannot be edited here; record a stock movement instead.
+// @Description  `If-Match` must carry the item's current ETag. Sales and stock movements change it as well, so re-read the item after a 412.
+// @Description  **Required Permission:** `items:write`
+// @Tags         Items
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Item UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being updated"
+// @Param        request body request.UpdateItemRequest true "Update payload"
+// @Success      200  {object}  utils.Response{data=response.ItemResponse} "Item updated successfully"
+// @Header       200  {string}  ETag  "New version of the item"
+// @Failure      400  {object}  utils.Response "Invalid UUID format, payload or direct stock edit"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Item, category or shelf not found"
+// @Failure      409  {object}  utils.Response "SKU already exists"
+// @Failure      412  {object}  utils.Response "Item was changed since it was read"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id} [put]
+func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	itemID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid item ID format", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	var req request.UpdateItemRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.itemService.UpdateItem(r.Context(), itemID, version, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Item updated successfully", res)
+}
+
+// DeleteItem godoc
+// @Summary      Delete an item
+// @Description  Soft-delete an item. Refused while the item still has stock.
+// @Description  `If-Match` must carry the item's current ETag.
+// @Description  **Required Permission:** `items:write`
+// @Tags         Items
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Item UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being deleted"
+// @Success      200  {object}  utils.Response "Item deleted successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      409  {object}  utils.Response "Item still has stock"
+// @Failure      412  {object}  utils.Response "Item was changed since it was read"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id} [delete]
+func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	itemID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid item ID format", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	if err := h.itemService.DeleteItem(r.Context(), itemID, version); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Item deleted successfully", nil)
+}
+
+// parseItemListQuery reads the pagination, filter and sort parameters for GetItems.
+func parseItemListQuery(r *http.Request) (request.ItemListQuery, error) {
+	query := request.ItemListQuery{
+		PaginationQuery: parsePaginationQuery(r),
+		Sort:            r.URL.Query().Get("sort"),
+		Order:           r.URL.Query().Get("order"),
+	}
+
+	var err error
+	if query.CategoryID, err = queryUUID(r, "category_id"); err != nil {
+		return query, err
+	}
+	if query.ShelfID, err = queryUUID(r, "shelf_id"); err != nil {
+		return query, err
+	}
+	if query.WarehouseID, err = queryUUID(r, "warehouse_id"); err != nil {
+		return query, err
+	}
+	if query.MinPrice, err = queryDecimal(r, "min_price"); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = queryDecimal(r, "max_price"); err != nil {
+		return query, err
+	}
+	if query.MinStock, err = queryInt(r, "min_stock"); err != nil {
+		return query, err
+	}
+	if query.MaxStock, err = queryInt(r, "max_stock"); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+	logger      *zap.Logger
+}
+
+// NewRoleHandler initializes the RoleHandler with necessary dependencies.
+func NewRoleHandler(roleService service.RoleService, logger *zap.Logger) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+		logger:      logger,
+	}
+}
+
+// GetRoles godoc
+// @Summary      List roles
+// @Description  Retrieve every role together with the permissions it grants.
+// @Description  **Required Permission:** `roles:manage` or `users:manage`
+// @Tags         Roles
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]response.RoleResponse} "Roles retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/roles [get]
+func (h *RoleHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	res, err := h.roleService.GetRoles(r.Context())
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Roles retrieved successfully", res)
+}
+
+// GetPermissions godoc
+// @Summary      List permissions
+// @Description  Retrieve every permission that can be granted to a role.
+// @Description  **Required Permission:** `roles:manage` or `users:manage`
+// @Tags         Roles
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]response.PermissionResponse} "Permissions retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Router       /api/v1/roles/permissions [get]
+func (h *RoleHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	utils.Success(w, r, http.StatusOK, "Permissions retrieved successfully", h.roleService.GetPermissions())
+}
+
+// CreateRole godoc
+// @Summary      Create a custom role
+// @Description  Define a new role from a set of permissions. You can only grant permissions your own role has.
+// @Description  **Required Permission:** `roles:manage`
+// @Tags         Roles
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateRoleRequest true "Role data payload"
+// @Success      201  {object}  utils.Response{data=response.RoleResponse} "Role created successfully"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or granting permissions you do not have"
+// @Failure      409  {object}  utils.Response "Role name already exists"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed or unknown permission"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/roles [post]
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.CreateRoleRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.roleService.CreateRole(r.Context(), req, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusCreated, "Role created successfully", res)
+}
+
+// UpdateRole godoc
+// @Summary      Update a role
+// @Description  Replace a role's description and permission set. Takes effect on the next request of every user holding the role.
+// @Description  The super_admin role cannot be changed.
+// @Description  **Required Permission:** `roles:manage`
+// @Tags         Roles
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        name path      string  true  "Role name"
+// @Param        request body request.UpdateRoleRequest true "Update payload"
+// @Success      200  {object}  utils.Response{data=response.RoleResponse} "Role updated successfully"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, role outranks you, or role is super_admin"
+// @Failure      404  {object}  utils.Response "Role not found"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed or unknown permission"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/roles/{name} [put]
+func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	name := model.UserRole(chi.URLParam(r, "name"))
+
+	var req request.UpdateRoleRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.roleService.UpdateRole(r.Context(), name, req, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Role updated successfully", res)
+}
+
+// DeleteRole godoc
+// @Summary      Delete a custom role
+// @Description  Delete a custom role. Built-in roles cannot be deleted, and a role still assigned to users is refused.
+// @Description  **Required Permission:** `roles:manage`
+// @Tags         Roles
+// @Security     BearerAuth
+// @Produce      json
+// @Param        name path      string  true  "Role name"
+// @Success      200  {object}  utils.Response "Role deleted successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, role outranks you, or built-in role"
+// @Failure      404  {object}  utils.Response "Role not found"
+// @Failure      409  {object}  utils.Response "Role is still assigned to users"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/roles/{name} [delete]
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+	name := chi.URLParam(r, "name")
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	h.logger.Info("Received request to delete role", zap.String("request_id", reqID), zap.String("role", name))
+
+	if err := h.roleService.DeleteRole(r.Context(), model.UserRole(name), actor); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Role deleted successfully", nil)
+}
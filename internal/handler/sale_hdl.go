@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type SaleHandler struct {
+	saleService service.SaleService
+	logger      *zap.Logger
+}
+
+// NewSaleHandler initializes the SaleHandler with necessary dependencies.
+func NewSaleHandler(saleService service.SaleService, logger *zap.Logger) *SaleHandler {
+	return &SaleHandler{
+		saleService: saleService,
+		logger:      logger,
+	}
+}
+
+// Checkout godoc
+// @Summary      Checkout a cart
+// @Description  Record a sale for the authenticated cashier. Each cart line identifies the item by `item_id` or `sku`; repeated items are merged.
+// @Description  Unit prices are taken from the catalogue at the moment of sale and totals are computed server-side.
+// @Description  Stock is decremented and an `OUT` movement referencing the sale is recorded per line, all atomically.
+// @Description  **Required Permission:** `sales:create`
+// @Tags         Sales
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CheckoutRequest true "Cart payload"
+// @Success      201  {object}  utils.Response{data=response.SaleResponse} "Sale completed successfully"
+// @Failure      400  {object}  utils.Response "Invalid cart"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      409  {object}  utils.Response "Insufficient stock"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/sales [post]
+func (h *SaleHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.CheckoutRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.saleService.Checkout(r.Context(), userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("Sale completed successfully", zap.String("request_id", reqID), zap.String("sale_id", res.ID.String()))
+	utils.Success(w, r, http.StatusCreated, "Sale completed successfully", res)
+}
+
+// GetSales godoc
+// @Summary      Get all sales
+// @Description  Retrieve a paginated list of sales, newest first. Without the `sales:view_all` permission you only see your own sales.
+// @Description  Without the `warehouses:all` permission only sales of goods from your assigned warehouses are listed.
+// @Description  `from`/`to` accept `YYYY-MM-DD` or RFC 3339; a bare `to` date includes that whole day.
+// @Tags         Sales
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page     query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit    query     int     false  "Number of items per page (default: 10)"
+// @Param        user_id  query     string  false  "Filter by cashier UUID (ignored without sales:view_all)"
+// @Param        from     query     string  false  "Only sales at or after this date/time"
+// @Param        to       query     string  false  "Only sales before this date/time"
+// @Success      200  {object}  utils.Response{data=response.SalePaginatedResponse} "Sales retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid filter"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/sales [get]
+func (h *SaleHandler) GetSales(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	query := request.SaleListQuery{PaginationQuery: parsePaginationQuery(r)}
+
+	var err error
+	if query.UserID, err = queryUUID(r, "user_id"); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if query.From, err = queryTime(r, "from", false); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if query.To, err = queryTime(r, "to", true); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := h.saleService.GetSales(r.Context(), query, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Sales retrieved successfully", result)
+}
+
+// GetSale godoc
+// @Summary      Get a sale
+// @Description  Retrieve a single sale with its lines. Without the `sales:view_all` permission you can only open your own sales.
+// @Tags         Sales
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Sale UUID"
+// @Success      200  {object}  utils.Response{data=response.SaleResponse} "Sale retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      404  {object}  utils.Response "Sale not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/sales/{id} [get]
+func (h *SaleHandler) GetSale(w http.ResponseWriter, r *http.Request) {
+	saleID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid sale ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.saleService.GetSale(r.Context(), saleID, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Sale retrieved successfully", res)
+}
+
+// VoidSale godoc
+// @Summary      Void a sale
+// @Description  Cancel a whole sale on the day it was made. Every unit not yet returned goes back on stock via an `IN` movement referencing the sale.
+// @Description  **Required Permission:** `sales:void`
+// @Tags         Sales
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Sale UUID"
+// @Param        request body request.VoidSaleRequest true "Void payload"
+// @Success      200  {object}  utils.Response{data=response.SaleResponse} "Sale voided successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or missing reason"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Sale not found"
+// @Failure      409  {object}  utils.Response "Sale already voided or not from today"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/sales/{id}/void [post]
+func (h *SaleHandler) VoidSale(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	saleID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid sale ID format", nil)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.VoidSaleRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.saleService.VoidSale(r.Context(), saleID, userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Sale voided successfully", res)
+}
+
+// CreateReturn godoc
+// @Summary      Return sold items
+// @Description  Take back part of one or more sale lines. Refunds use the unit price of the sale and the goods go back on stock via `IN` movements referencing the sale.
+// @Description  A line can never be returned beyond what was sold, across all returns.
+// @Description  **Required Permission:** `sales:create`
+// @Tags         Sales
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Sale UUID"
+// @Param        request body request.CreateReturnRequest true "Return payload"
+// @Success      201  {object}  utils.Response{data=response.SaleReturnResponse} "Return recorded successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or return lines"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Sale or sale line not found"
+// @Failure      409  {object}  utils.Response "Sale voided or quantity exceeds what is left to return"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/sales/{id}/returns [post]
+func (h *SaleHandler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	saleID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid sale ID format", nil)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.CreateReturnRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.saleService.CreateReturn(r.Context(), saleID, userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusCreated, "Return recorded successfully", res)
+}
@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ShelfHandler struct {
+	shelfService service.ShelfService
+	logger       *zap.Logger
+}
+
+// NewShelfHandler initializes the ShelfHandler with necessary dependencies.
+func NewShelfHandler(shelfService service.ShelfService, logger *zap.Logger) *ShelfHandler {
+	return &ShelfHandler{
+		shelfService: shelfService,
+		logger:       logger,
+	}
+}
+
+// CreateShelf godoc
+// @Summary      Create a shelf
+// @Description  Add a new shelf (bin location) to a warehouse. Shelf names are unique per warehouse.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Warehouse UUID"
+// @Param        request body request.CreateShelfRequest true "Shelf data payload"
+// @Success      201  {object}  utils.Response{data=response.ShelfResponse} "Shelf created successfully"
+// @Header       201  {string}  ETag  "Version of the shelf, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or payload"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Warehouse not found"
+// @Failure      409  {object}  utils.Response "Shelf name already exists in the warehouse"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id}/shelves [post]
+func (h *ShelfHandler) CreateShelf(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return
+	}
+
+	var req request.CreateShelfRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.shelfService.CreateShelf(r.Context(), warehouseID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusCreated, "Shelf created successfully", res)
+}
+
+// GetWarehouseShelves godoc
+// @Summary      Get shelves of a warehouse
+// @Description  Retrieve a paginated list of shelves in a warehouse with optional name search.
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id      path      string  true   "Warehouse UUID"
+// @Param        page    query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit   query     int     false  "Number of items per page (default: 10)"
+// @Param        search  query     string  false  "Search filter for shelf name"
+// @Success      200  {object}  utils.Response{data=response.ShelfPaginatedResponse} "Shelves retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      404  {object}  utils.Response "Warehouse not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id}/shelves [get]
+func (h *ShelfHandler) GetWarehouseShelves(w http.ResponseWriter, r *http.Request) {
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return
+	}
+
+	query := request.ShelfListQuery{
+		PaginationQuery: parsePaginationQuery(r),
+		WarehouseID:     &warehouseID,
+	}
+
+	result, err := h.shelfService.GetShelves(r.Context(), query)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Shelves retrieved successfully", result)
+}
+
+// UpdateShelf godoc
+// @Summary      Update a shelf
+// @Description  Rename a shelf inside a warehouse. `If-Match` must carry the shelf's current ETag.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Warehouse UUID"
+// @Param        shelfID  path      string  true  "Shelf UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being updated"
+// @Param        request body request.UpdateShelfRequest true "Update payload"
+// @Success      200  {object}  utils.Response{data=response.ShelfResponse} "Shelf updated successfully"
+// @Header       200  {string}  ETag  "New version of the shelf"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or payload"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Shelf not found"
+// @Failure      409  {object}  utils.Response "Shelf name already exists in the warehouse"
+// @Failure      412  {object}  utils.Response "Shelf was changed since it was read"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id}/shelves/{shelfID} [put]
+func (h *ShelfHandler) UpdateShelf(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	warehouseID, shelfID, ok := h.parseNestedIDs(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	var req request.UpdateShelfRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.shelfService.UpdateShelf(r.Context(), warehouseID, shelfID, version, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Shelf updated successfully", res)
+}
+
+// DeleteShelf godoc
+// @Summary      Delete a shelf
+// @Description  Soft-delete an empty shelf. Refused while items on the shelf still have stock.
+// @Description  `If-Match` must carry the shelf's current ETag.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path      string  true  "Warehouse UUID"
+// @Param        shelfID  path      string  true  "Shelf UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being deleted"
+// @Success      200  {object}  utils.Response "Shelf deleted successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Shelf not found"
+// @Failure      409  {object}  utils.Response "Shelf still holds stock"
+// @Failure      412  {object}  utils.Response "Shelf was changed since it was read"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id}/shelves/{shelfID} [delete]
+func (h *ShelfHandler) DeleteShelf(w http.ResponseWriter, r *http.Request) {
+	warehouseID, shelfID, ok := h.parseNestedIDs(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	if err := h.shelfService.DeleteShelf(r.Context(), warehouseID, shelfID, version); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Shelf deleted successfully", nil)
+}
+
+// GetShelves godoc
+// @Summary      Look up shelves
+// @Description  Retrieve a paginated list of shelves across all warehouses, optionally filtered by warehouse.
+// @Description  Without the `warehouses:all` permission only shelves in your assigned warehouses are listed.
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Produce      json
+// @Param        warehouse_id  query     string  false  "Filter by warehouse UUID"
+// @Param        page          query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 10)"
+// @Param        search        query     string  false  "Search filter for shelf name"
+// @Success      200  {object}  utils.Response{data=response.ShelfPaginatedResponse} "Shelves retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid warehouse_id format"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/shelves [get]
+func (h *ShelfHandler) GetShelves(w http.ResponseWriter, r *http.Request) {
+	query := request.ShelfListQuery{PaginationQuery: parsePaginationQuery(r)}
+
+	if raw := r.URL.Query().Get("warehouse_id"); raw != "" {
+		warehouseID, err := uuid.Parse(raw)
+		if err != nil {
+			utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse_id format", nil)
+			return
+		}
+		query.WarehouseID = &warehouseID
+	}
+
+	result, err := h.shelfService.GetShelves(r.Context(), query)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Shelves retrieved successfully", result)
+}
+
+// GetShelf godoc
+// @Summary      Get a shelf
+// @Description  Retrieve a single shelf by its UUID.
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Shelf UUID"
+// @Success      200  {object}  utils.Response{data=response.ShelfResponse} "Shelf retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the shelf, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      404  {object}  utils.Response "Shelf not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/shelves/{id} [get]
+func (h *ShelfHandler) GetShelf(w http.ResponseWriter, r *http.Request) {
+	shelfID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid shelf ID format", nil)
+		return
+	}
+
+	res, err := h.shelfService.GetShelf(r.Context(), shelfID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Shelf retrieved successfully", res)
+}
+
+// GetShelfContents godoc
+// @Summary      Get shelf contents
+// @Description  List the items currently placed on a shelf with their quantities, so staff know where to pick goods.
+// @Tags         Shelves
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Shelf UUID"
+// @Success      200  {object}  utils.Response{data=response.ShelfContentsResponse} "Shelf contents retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      404  {object}  utils.Response "Shelf not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/shelves/{id}/items [get]
+func (h *ShelfHandler) GetShelfContents(w http.ResponseWriter, r *http.Request) {
+	shelfID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid shelf ID format", nil)
+		return
+	}
+
+	res, err := h.shelfService.GetShelfContents(r.Context(), shelfID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Shelf contents retrieved successfully", res)
+}
+
+// parseNestedIDs extracts the warehouse and shelf UUIDs from a nested shelf route.
+func (h *ShelfHandler) parseNestedIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	shelfID, err := parseUUIDParam(r, "shelfID")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid shelf ID format", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return warehouseID, shelfID, true
+}
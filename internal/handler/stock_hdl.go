@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type StockHandler struct {
+	stockService service.StockService
+	logger       *zap.Logger
+}
+
+// NewStockHandler initializes the StockHandler with necessary dependencies.
+func NewStockHandler(stockService service.StockService, logger *zap.Logger) *StockHandler {
+	return &StockHandler{
+		stockService: stockService,
+		logger:       logger,
+	}
+}
+
+// StockIn godoc
+// @Summary      Receive stock
+// @Description  Add a quantity to an item's stock and record an `IN` movement in the ledger.
+// @Description  **Required Permission:** `stock:move`
+// @Tags         Stock
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Item UUID"
+// @Param        request body request.StockMovementRequest true "Movement payload"
+// @Success      201  {object}  utils.Response{data=response.StockMovementResponse} "Stock received successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or quantity"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      409  {object}  utils.Response "Stock would exceed the maximum level"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id}/stock/in [post]
+func (h *StockHandler) StockIn(w http.ResponseWriter, r *http.Request) {
+	h.handleMovement(w, r, "Stock received successfully", h.stockService.StockIn)
+}
+
+// StockOut godoc
+// @Summary      Issue stock
+// @Description  Remove a quantity from an item's stock and record an `OUT` movement in the ledger.
+// @Description  Refused when the item does not have enough stock.
+// @Description  **Required Permission:** `stock:move`
+// @Tags         Stock
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Item UUID"
+// @Param        request body request.StockMovementRequest true "Movement payload"
+// @Success      201  {object}  utils.Response{data=response.StockMovementResponse} "Stock issued successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or quantity"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      409  {object}  utils.Response "Insufficient stock"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id}/stock/out [post]
+func (h *StockHandler) StockOut(w http.ResponseWriter, r *http.Request) {
+	h.handleMovement(w, r, "Stock issued successfully", h.stockService.StockOut)
+}
+
+// AdjustStock godoc
+// @Summary      Adjust stock
+// @Description  Set an item's stock to a counted value (e.g. after a stock take) and record an `ADJUSTMENT` movement with the signed difference.
+// @Description  **Required Permission:** `stock:adjust`
+// @Tags         Stock
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Item UUID"
+// @Param        request body request.StockAdjustmentRequest true "Adjustment payload"
+// @Success      201  {object}  utils.Response{data=response.StockMovementResponse} "Stock adjusted successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format, stock value or missing reason"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      409  {object}  utils.Response "Adjustment does not change the stock level"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id}/stock/adjust [post]
+func (h *StockHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	itemID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid item ID format", nil)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.StockAdjustmentRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.stockService.Adjust(r.Context(), itemID, userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusCreated, "Stock adjusted successfully", res)
+}
+
+// GetMovements godoc
+// @Summary      Get an item's stock movements
+// @Description  Retrieve the paginated ledger of stock movements for an item, newest first.
+// @Description  `from`/`to` accept `YYYY-MM-DD` or RFC 3339; a bare `to` date includes that whole day.
+// @Tags         Stock
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id     path      string  true   "Item UUID"
+// @Param        page   query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit  query     int     false  "Number of items per page (default: 10)"
+// @Param        type   query     string  false  "Filter by movement type" Enums(IN, OUT, ADJUSTMENT)
+// @Param        from   query     string  false  "Only movements at or after this date/time"
+// @Param        to     query     string  false  "Only movements before this date/time"
+// @Success      200  {object}  utils.Response{data=response.StockMovementPaginatedResponse} "Stock movements retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or filter"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      404  {object}  utils.Response "Item not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/items/{id}/movements [get]
+func (h *StockHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	itemID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid item ID format", nil)
+		return
+	}
+
+	query := request.StockMovementQuery{
+		PaginationQuery: parsePaginationQuery(r),
+		Type:            r.URL.Query().Get("type"),
+	}
+	if query.From, err = queryTime(r, "from", false); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if query.To, err = queryTime(r, "to", true); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := h.stockService.GetMovements(r.Context(), itemID, query)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Stock movements retrieved successfully", result)
+}
+
+// handleMovement decodes a StockMovementRequest and passes it to an IN or OUT operation.
+func (h *StockHandler) handleMovement(
+	w http.ResponseWriter,
+	r *http.Request,
+	successMsg string,
+	move func(ctx context.Context, itemID, userID uuid.UUID, req request.StockMovementRequest) (*response.StockMovementResponse, error),
+) {
+	reqID := middleware.GetReqID(r.Context())
+
+	itemID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid item ID format", nil)
+		return
+	}
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.StockMovementRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := move(r.Context(), itemID, userID, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusCreated, successMsg, res)
+}
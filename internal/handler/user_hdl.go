@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
-	customMiddleware "inventory-system/internal/middleware"
 	"net/http"
-	"strconv"
 
 	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
 	"inventory-system/internal/service"
 	"inventory-system/pkg/utils"
 
@@ -32,15 +30,19 @@ func NewUserHandler(userService service.UserService, logger *zap.Logger) *UserHa
 // CreateUser godoc
 // @Summary      Create a new user
 // @Description  Register a new user (Admin or Staff) into the system. Requires authentication.
-// @Description  **Required Roles:** `super_admin`, `admin`
+// @Description  **Required Permission:** `users:manage`
 // @Tags         Users
 // @Security     BearerAuth
 // @Accept       json
 // @Produce      json
 // @Param        request body request.CreateUserRequest true "User data payload"
 // @Success      201  {object}  utils.Response{data=response.UserResponse} "User created successfully"
-// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Header       201  {string}  ETag  "Version of the user, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid request payload or role"
 // @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the role has permissions you do not have"
+// @Failure      409  {object}  utils.Response "Email already exists"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
 // @Failure      500  {object}  utils.Response "Internal server error"
 // @Router       /api/v1/users [post]
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
@@ -50,97 +52,132 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req request.CreateUserRequest
 
 	// 1. Decode JSON payload into the request DTO.
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := utils.BindJSON(w, r, &req); err != nil {
 		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
-		utils.Error(w, r, http.StatusBadRequest, "Invalid request payload format", nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	requesterRole, ok := r.Context().Value(customMiddleware.UserRoleKey).(string)
+	actor, ok := currentActor(r)
 	if !ok {
-		utils.Error(w, r, http.StatusUnauthorized, "Role not found in context", nil)
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
 		return
 	}
 
 	// 2. Pass the parsed request to the Service layer for business logic execution.
-	userRes, err := h.userService.CreateUser(r.Context(), req, requesterRole)
+	userRes, err := h.userService.CreateUser(r.Context(), req, actor)
 	if err != nil {
 		// Log the error returned by the service layer
-		h.logger.Error("Service failed to create user", zap.String("request_id", reqID), zap.Error(err))
-
-		// Differentiating client errors (400) vs server errors (500)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "invalid user role. Must be super_admin, admin, or staff" || err.Error() == "email already exists or database error occurred" {
-			statusCode = http.StatusBadRequest
-		}
-
-		utils.Error(w, r, statusCode, err.Error(), nil)
+		h.logger.Warn("Service failed to create user", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
 		return
 	}
 
 	h.logger.Info("User created successfully", zap.String("request_id", reqID), zap.String("email", req.Email))
 
 	// 3. Return a successful 201 Created response.
+	setETag(w, userRes.Version)
 	utils.Success(w, r, http.StatusCreated, "User created successfully", userRes)
 }
 
 // GetUsers godoc
 // @Summary      Get all users
-// @Description  Retrieve a paginated list of users with optional search filtering.
-// @Description  **Required Roles:** `super_admin`, `admin`
+// @Description  Retrieve a paginated list of users with optional search filtering. Deleted users are hidden unless `include_deleted=true`.
+// @Description  **Required Permission:** `users:manage` (`include_deleted` also needs `users:restore`)
 // @Tags         Users
 // @Security     BearerAuth
 // @Accept       json
 // @Produce      json
-// @Param        page    query     int     false  "Page number for pagination (default: 1)"
-// @Param        limit   query     int     false  "Number of items per page (default: 10)"
-// @Param        search  query     string  false  "Search filter for user name or email"
+// @Param        page             query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit            query     int     false  "Number of items per page (default: 10)"
+// @Param        search           query     string  false  "Search filter for user name or email"
+// @Param        include_deleted  query     bool    false  "Also list soft-deleted users (needs users:restore)"
 // @Success 200 {object} utils.Response{data=response.UserPaginatedResponse} "Users retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid filter"
 // @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - include_deleted requires users:restore"
 // @Failure      500  {object}  utils.Response "Internal server error"
 // @Router       /api/v1/users [get]
 func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
-	// 1. Extract values from URL (they are still strings at this stage)
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
-	search := r.URL.Query().Get("search")
+	// 1. Extract page, limit and search from the URL into a Pagination Request DTO
+	query := request.UserListQuery{PaginationQuery: parsePaginationQuery(r)}
 
-	// 2. Convert Strings to Integers (Int)
-	page, _ := strconv.Atoi(pageStr)
-	limit, _ := strconv.Atoi(limitStr)
+	var err error
+	if query.IncludeDeleted, err = queryBool(r, "include_deleted"); err != nil {
+		utils.Error(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 
-	// 3. Wrap into a Pagination Request DTO
-	query := request.PaginationQuery{
-		Page:   page,
-		Limit:  limit,
-		Search: search,
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
 	}
 
-	// 4. Pass the request to the Service layer
-	result, err := h.userService.GetUsers(r.Context(), query)
+	// 2. Pass the request to the Service layer
+	result, err := h.userService.GetUsers(r.Context(), query, actor)
 	if err != nil {
-		utils.Error(w, r, http.StatusInternalServerError, err.Error(), nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	// 5. Return the response to the Client
+	// 3. Return the response to the Client
 	utils.Success(w, r, http.StatusOK, "Users retrieved successfully", result)
 }
 
+// GetUser godoc
+// @Summary      Get a user
+// @Description  Retrieve a single active user by UUID. The ETag header carries its version for If-Match on update and delete.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response{data=response.UserResponse} "User retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the user, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "User not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id} [get]
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	res, err := h.userService.GetUser(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "User retrieved successfully", res)
+}
+
 // UpdateUser godoc
 // @Summary      Update a user
 // @Description  Update user's name and role by their UUID.
-// @Description  **Required Roles:** `super_admin`, `admin`
+// @Description  `If-Match` must carry the user's current version as an ETag, as returned by GET /users/{id} (e.g. `"3"` for `"version": 3`).
+// @Description  **Required Permission:** `users:manage`
 // @Tags         Users
 // @Security     BearerAuth
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string                    true  "User UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being updated"
 // @Param        request body request.UpdateUserRequest true "Update payload"
 // @Success      200  {object}  utils.Response{data=response.UserResponse} "User updated successfully"
+// @Header       200  {string}  ETag  "New version of the user"
 // @Failure      400  {object}  utils.Response "Invalid UUID format or payload"
-// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
 // @Failure      404  {object}  utils.Response "User not found"
+// @Failure      412  {object}  utils.Response "User was changed since it was read"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
 // @Failure      500  {object}  utils.Response "Internal server error"
 // @Router       /api/v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -157,46 +194,53 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
 	// Decode JSON payload
 	var req request.UpdateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := utils.BindJSON(w, r, &req); err != nil {
 		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
-		utils.Error(w, r, http.StatusBadRequest, "Invalid JSON payload", nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
-	requesterRole, ok := r.Context().Value(customMiddleware.UserRoleKey).(string)
+	actor, ok := currentActor(r)
 	if !ok {
-		utils.Error(w, r, http.StatusUnauthorized, "Role not found in context", nil)
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
 		return
 	}
 
 	// Process the update request
-	res, err := h.userService.UpdateUser(r.Context(), userID, req, requesterRole)
+	res, err := h.userService.UpdateUser(r.Context(), userID, version, req, actor)
 	if err != nil {
-		if err.Error() == "user not found" {
-			utils.Error(w, r, http.StatusNotFound, err.Error(), nil)
-			return
-		}
-		utils.Error(w, r, http.StatusInternalServerError, err.Error(), nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
+	setETag(w, res.Version)
 	utils.Success(w, r, http.StatusOK, "User updated successfully", res)
 }
 
 // DeleteUser godoc
 // @Summary      Delete a user
-// @Description  Remove a user from the system by their UUID.
-// @Description  **Required Roles:** `super_admin`, `admin`
+// @Description  Soft-delete a user by their UUID and revoke all of their sessions and API keys. Their sales and stock history are kept.
+// @Description  `If-Match` must carry the user's current version as an ETag.
+// @Description  **Required Permission:** `users:manage`
 // @Tags         Users
 // @Security     BearerAuth
 // @Produce      json
 // @Param        id   path      string  true  "User UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being deleted"
 // @Success      200  {object}  utils.Response "User deleted successfully"
 // @Failure      400  {object}  utils.Response "Invalid UUID format"
-// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
 // @Failure      404  {object}  utils.Response "User not found"
+// @Failure      412  {object}  utils.Response "User was changed since it was read"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
 // @Failure      500  {object}  utils.Response "Internal server error"
 // @Router       /api/v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
@@ -213,22 +257,476 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	requesterRole, ok := r.Context().Value(customMiddleware.UserRoleKey).(string)
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	actor, ok := currentActor(r)
 	if !ok {
-		utils.Error(w, r, http.StatusUnauthorized, "Role not found in context", nil)
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
 		return
 	}
 
 	// Process the delete request
-	err = h.userService.DeleteUser(r.Context(), userID, requesterRole)
+	err = h.userService.DeleteUser(r.Context(), userID, version, actor)
 	if err != nil {
-		if err.Error() == "user not found" {
-			utils.Error(w, r, http.StatusNotFound, err.Error(), nil)
-			return
-		}
-		utils.Error(w, r, http.StatusInternalServerError, err.Error(), nil)
+		utils.HandleError(w, r, err)
 		return
 	}
 
 	utils.Success(w, r, http.StatusOK, "User deleted successfully", nil)
 }
+
+// RestoreUser godoc
+// @Summary      Restore a user
+// @Description  Restore a soft-deleted user by their UUID. The user has to log in again.
+// @Description  **Required Permission:** `users:restore`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response{data=response.UserResponse} "User restored successfully"
+// @Header       200  {string}  ETag  "Version of the user, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Deleted user not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	res, err := h.userService.RestoreUser(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "User restored successfully", res)
+}
+
+// RevokeUserSessions godoc
+// @Summary      Revoke all sessions of a user
+// @Description  Force-sign a user out of every login. Their access and refresh tokens stop working immediately.
+// @Description  Their API keys keep working; revoke those with DELETE /users/{id}/api-keys/{keyID}.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response{data=response.RevokedSessionsResponse} "User sessions revoked successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
+// @Failure      404  {object}  utils.Response "User not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/sessions [delete]
+func (h *UserHandler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	revoked, err := h.userService.RevokeUserSessions(r.Context(), userID, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "User sessions revoked successfully", response.RevokedSessionsResponse{Revoked: revoked})
+}
+
+// GetLockouts godoc
+// @Summary      List login lockouts
+// @Description  List users with failed logins since their last successful login, most recent failure first. `locked` is true while the account is locked.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=[]response.LockoutResponse} "Lockouts retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/lockouts [get]
+func (h *UserHandler) GetLockouts(w http.ResponseWriter, r *http.Request) {
+	res, err := h.userService.GetLockouts(r.Context())
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Lockouts retrieved successfully", res)
+}
+
+// ClearLockout godoc
+// @Summary      Clear a user's lockout
+// @Description  Unlock a user's account and reset their failed login counter.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response "User lockout cleared successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
+// @Failure      404  {object}  utils.Response "User not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/lockout [delete]
+func (h *UserHandler) ClearLockout(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	if err := h.userService.ClearLockout(r.Context(), userID, actor); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("User lockout cleared", zap.String("request_id", reqID), zap.String("target_user_id", userID.String()))
+	utils.Success(w, r, http.StatusOK, "User lockout cleared successfully", nil)
+}
+
+// ResetTwoFactor godoc
+// @Summary      Reset a user's two-factor authentication
+// @Description  Remove a user's authenticator and recovery codes, e.g. after they lost their phone. If their role requires
+// @Description  two-factor authentication, their next login makes them set it up again.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response "User two-factor authentication reset successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
+// @Failure      404  {object}  utils.Response "User not found"
+// @Failure      409  {object}  utils.Response "Two-factor authentication is not enabled"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/2fa [delete]
+func (h *UserHandler) ResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	if err := h.userService.ResetTwoFactor(r.Context(), userID, actor); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("User two-factor authentication reset", zap.String("request_id", reqID), zap.String("target_user_id", userID.String()))
+	utils.Success(w, r, http.StatusOK, "User two-factor authentication reset successfully", nil)
+}
+
+// GetUserAPIKeys godoc
+// @Summary      List a user's API keys
+// @Description  List a user's API keys that have not been revoked, newest first.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response{data=[]response.APIKeyResponse} "User API keys retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
+// @Failure      404  {object}  utils.Response "User not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/api-keys [get]
+func (h *UserHandler) GetUserAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.userService.GetUserAPIKeys(r.Context(), userID, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "User API keys retrieved successfully", res)
+}
+
+// RevokeUserAPIKey godoc
+// @Summary      Revoke a user's API key
+// @Description  Disable one of a user's API keys immediately, without signing the user out.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id     path      string  true  "User UUID"
+// @Param        keyID  path      string  true  "API key UUID"
+// @Success      200  {object}  utils.Response "User API key revoked successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
+// @Failure      404  {object}  utils.Response "User or API key not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/api-keys/{keyID} [delete]
+func (h *UserHandler) RevokeUserAPIKey(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+	keyID, err := parseUUIDParam(r, "keyID")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid API key ID format", nil)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	if err := h.userService.RevokeUserAPIKey(r.Context(), userID, keyID, actor); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("User API key revoked", zap.String("request_id", reqID), zap.String("target_user_id", userID.String()), zap.String("api_key_id", keyID.String()))
+	utils.Success(w, r, http.StatusOK, "User API key revoked successfully", nil)
+}
+
+// GetUserWarehouses godoc
+// @Summary      List a user's warehouses
+// @Description  List the warehouses a user is assigned to. Without `warehouses:all` a user only sees and moves stock in these.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  utils.Response{data=[]response.WarehouseResponse} "User warehouses retrieved successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "User not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/warehouses [get]
+func (h *UserHandler) GetUserWarehouses(w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	res, err := h.userService.GetUserWarehouses(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "User warehouses retrieved successfully", res)
+}
+
+// SetUserWarehouses godoc
+// @Summary      Assign warehouses to a user
+// @Description  Replace the warehouses a user is assigned to. An empty list removes every assignment. Takes effect on the user's next request.
+// @Description  You can only assign warehouses you can see yourself.
+// @Description  **Required Permission:** `users:manage`
+// @Tags         Users
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Param        request body request.SetUserWarehousesRequest true "Warehouse assignment payload"
+// @Success      200  {object}  utils.Response{data=[]response.WarehouseResponse} "User warehouses updated successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or payload"
+// @Failure      403  {object}  utils.Response "Forbidden - Missing permission, or the user's role has permissions you do not have"
+// @Failure      404  {object}  utils.Response "User or warehouse not found"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/users/{id}/warehouses [put]
+func (h *UserHandler) SetUserWarehouses(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid user ID format", nil)
+		return
+	}
+
+	var req request.SetUserWarehousesRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	actor, ok := currentActor(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.userService.SetUserWarehouses(r.Context(), userID, req, actor)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "User warehouses updated successfully", res)
+}
+
+// GetMe godoc
+// @Summary      Get my profile
+// @Description  Return the profile of the authenticated user. Available to every role.
+// @Tags         Me
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=response.UserResponse} "Profile retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the profile, to send as If-Match"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me [get]
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	res, err := h.userService.GetProfile(r.Context(), userID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Profile retrieved successfully", res)
+}
+
+// UpdateMe godoc
+// @Summary      Update my profile
+// @Description  Change the authenticated user's name. Role and email can only be changed by an admin.
+// @Description  `If-Match` must carry the ETag returned by `GET /me`.
+// @Tags         Me
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        If-Match  header  string  true  "ETag of the version being updated"
+// @Param        request body request.UpdateProfileRequest true "Profile payload"
+// @Success      200  {object}  utils.Response{data=response.UserResponse} "Profile updated successfully"
+// @Header       200  {string}  ETag  "New version of the profile"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      412  {object}  utils.Response "Profile was changed since it was read"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me [put]
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, ok := currentUserID(r)
+	if !ok {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	var req request.UpdateProfileRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.userService.UpdateProfile(r.Context(), userID, version, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Profile updated successfully", res)
+}
+
+// ChangeMyPassword godoc
+// @Summary      Change my password
+// @Description  Change the authenticated user's password. The current password is required and the new one must be
+// @Description  8 to 72 characters with at least one letter and one digit. Every other session is signed out and your API keys are revoked; this session stays.
+// @Tags         Me
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.ChangePasswordRequest true "Current and new password"
+// @Success      200  {object}  utils.Response{data=response.RevokedSessionsResponse} "Password changed successfully"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      403  {object}  utils.Response "Forbidden - Authenticated with an API key"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed, wrong current password or unchanged password"
+// @Failure      429  {object}  utils.Response{errors=apperror.RetryAfter} "Too many attempts from this IP, or the account is locked after wrong passwords"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/me/password [post]
+func (h *UserHandler) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	userID, userOK := currentUserID(r)
+	loginID, loginOK := currentLoginID(r)
+	if !userOK || !loginOK {
+		utils.Error(w, r, http.StatusUnauthorized, "User not found in context", nil)
+		return
+	}
+
+	var req request.ChangePasswordRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	revoked, err := h.userService.ChangePassword(r.Context(), userID, loginID, req)
+	if err != nil {
+		h.logger.Warn("Password change failed", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("Password changed", zap.String("request_id", reqID), zap.String("user_id", userID.String()))
+	utils.Success(w, r, http.StatusOK, "Password changed successfully", response.RevokedSessionsResponse{Revoked: revoked})
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"net/http"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/service"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type WarehouseHandler struct {
+	warehouseService service.WarehouseService
+	logger           *zap.Logger
+}
+
+// NewWarehouseHandler initializes the WarehouseHandler with necessary dependencies.
+func NewWarehouseHandler(warehouseService service.WarehouseService, logger *zap.Logger) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseService: warehouseService,
+		logger:           logger,
+	}
+}
+
+// CreateWarehouse godoc
+// @Summary      Create a warehouse
+// @Description  Register a new warehouse.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body request.CreateWarehouseRequest true "Warehouse data payload"
+// @Success      201  {object}  utils.Response{data=response.WarehouseResponse} "Warehouse created successfully"
+// @Header       201  {string}  ETag  "Version of the warehouse, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid request payload"
+// @Failure      401  {object}  utils.Response "Unauthorized"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses [post]
+func (h *WarehouseHandler) CreateWarehouse(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	var req request.CreateWarehouseRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.warehouseService.CreateWarehouse(r.Context(), req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("Warehouse created successfully", zap.String("request_id", reqID), zap.String("warehouse_id", res.ID.String()))
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusCreated, "Warehouse created successfully", res)
+}
+
+// GetWarehouses godoc
+// @Summary      Get all warehouses
+// @Description  Retrieve a paginated list of warehouses with optional search over name and location.
+// @Description  Without the `warehouses:all` permission only your assigned warehouses are listed.
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query     int     false  "Page number for pagination (default: 1)"
+// @Param        limit   query     int     false  "Number of items per page (default: 10)"
+// @Param        search  query     string  false  "Search filter for warehouse name or location"
+// @Success      200  {object}  utils.Response{data=response.WarehousePaginatedResponse} "Warehouses retrieved successfully"
+// @Failure      401  {object}  utils.Response "Unauthorized - Invalid or expired session"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses [get]
+func (h *WarehouseHandler) GetWarehouses(w http.ResponseWriter, r *http.Request) {
+	result, err := h.warehouseService.GetWarehouses(r.Context(), parsePaginationQuery(r))
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Warehouses retrieved successfully", result)
+}
+
+// GetWarehouse godoc
+// @Summary      Get a warehouse
+// @Description  Retrieve a single warehouse by its UUID.
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Warehouse UUID"
+// @Success      200  {object}  utils.Response{data=response.WarehouseResponse} "Warehouse retrieved successfully"
+// @Header       200  {string}  ETag  "Version of the warehouse, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      404  {object}  utils.Response "Warehouse not found"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id} [get]
+func (h *WarehouseHandler) GetWarehouse(w http.ResponseWriter, r *http.Request) {
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return
+	}
+
+	res, err := h.warehouseService.GetWarehouse(r.Context(), warehouseID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Warehouse retrieved successfully", res)
+}
+
+// UpdateWarehouse godoc
+// @Summary      Update a warehouse
+// @Description  Update a warehouse's name and location by its UUID. `If-Match` must carry the warehouse's current ETag.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Warehouse UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being updated"
+// @Param        request body request.UpdateWarehouseRequest true "Update payload"
+// @Success      200  {object}  utils.Response{data=response.WarehouseResponse} "Warehouse updated successfully"
+// @Header       200  {string}  ETag  "New version of the warehouse"
+// @Failure      400  {object}  utils.Response "Invalid UUID format or payload"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Warehouse not found"
+// @Failure      412  {object}  utils.Response "Warehouse was changed since it was read"
+// @Failure      422  {object}  utils.Response{errors=[]apperror.FieldError} "Validation failed"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id} [put]
+func (h *WarehouseHandler) UpdateWarehouse(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	var req request.UpdateWarehouseRequest
+	if err := utils.BindJSON(w, r, &req); err != nil {
+		h.logger.Warn("Failed to decode JSON payload", zap.String("request_id", reqID), zap.Error(err))
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	res, err := h.warehouseService.UpdateWarehouse(r.Context(), warehouseID, version, req)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Warehouse updated successfully", res)
+}
+
+// DeleteWarehouse godoc
+// @Summary      Delete a warehouse
+// @Description  Soft-delete a warehouse. Refused while any of its shelves still hold stock.
+// @Description  `If-Match` must carry the warehouse's current ETag.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Warehouse UUID"
+// @Param        If-Match  header  string  true  "ETag of the version being deleted"
+// @Success      200  {object}  utils.Response "Warehouse deleted successfully"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Warehouse not found"
+// @Failure      409  {object}  utils.Response "Warehouse still has shelves holding stock"
+// @Failure      412  {object}  utils.Response "Warehouse was changed since it was read"
+// @Failure      428  {object}  utils.Response "If-Match header missing"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id} [delete]
+func (h *WarehouseHandler) DeleteWarehouse(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetReqID(r.Context())
+
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	h.logger.Info("Received request to delete warehouse", zap.String("request_id", reqID), zap.String("warehouse_id", warehouseID.String()))
+
+	if err := h.warehouseService.DeleteWarehouse(r.Context(), warehouseID, version); err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	utils.Success(w, r, http.StatusOK, "Warehouse deleted successfully", nil)
+}
+
+// RestoreWarehouse godoc
+// @Summary      Restore a warehouse
+// @Description  Restore a soft-deleted warehouse by its UUID.
+// @Description  **Required Permission:** `warehouses:write`
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Warehouse UUID"
+// @Success      200  {object}  utils.Response{data=response.WarehouseResponse} "Warehouse restored successfully"
+// @Header       200  {string}  ETag  "Version of the warehouse, to send as If-Match"
+// @Failure      400  {object}  utils.Response "Invalid UUID format"
+// @Failure      403  {object}  utils.Response "Forbidden - Insufficient role permissions"
+// @Failure      404  {object}  utils.Response "Deleted warehouse not found, or outside your warehouses"
+// @Failure      500  {object}  utils.Response "Internal server error"
+// @Router       /api/v1/warehouses/{id}/restore [post]
+func (h *WarehouseHandler) RestoreWarehouse(w http.ResponseWriter, r *http.Request) {
+	warehouseID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		utils.Error(w, r, http.StatusBadRequest, "Invalid warehouse ID format", nil)
+		return
+	}
+
+	res, err := h.warehouseService.RestoreWarehouse(r.Context(), warehouseID)
+	if err != nil {
+		utils.HandleError(w, r, err)
+		return
+	}
+
+	setETag(w, res.Version)
+	utils.Success(w, r, http.StatusOK, "Warehouse restored successfully", res)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"inventory-system/internal/model"
 	"inventory-system/internal/repository"
 	"inventory-system/pkg/utils"
 
@@ -17,15 +18,58 @@ type ContextKey string
 const (
 	UserIDKey   ContextKey = "user_id"
 	UserRoleKey ContextKey = "user_role"
+	// PermissionsKey holds the model.Permissions granted to the user's role.
+	PermissionsKey ContextKey = "permissions"
+	// LoginSessionIDKey holds the ID of the login (token family) the access token belongs to.
+	// It is absent when the request was authenticated with an API key.
+	LoginSessionIDKey ContextKey = "login_session_id"
+	// APIKeyIDKey holds the ID of the API key the request was authenticated with.
+	APIKeyIDKey ContextKey = "api_key_id"
 )
 
-// Authenticate verifies the validity of the UUID token against the database.
-func Authenticate(sessionRepo repository.SessionRepository) func(http.Handler) http.Handler {
+// The warehouses a user may work in are stored with model.WithWarehouseScope instead of a
+// ContextKey, because the repositories read them and this package already imports repository.
+// Likewise the caller recorded on audit events is stored with model.WithAuditOrigin.
+
+// APIKeyHeader carries an API key. Scripts and devices send it instead of an Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+// Authenticate verifies the bearer token, or the API key in the X-API-Key header, against the
+// database. Both are opaque random strings looked up by their hash, never by the value itself.
+func Authenticate(sessionRepo repository.SessionRepository, apiKeyRepo repository.APIKeyRepository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
-			// 1. Extract the token from the authorization header.
 			authHeader := r.Header.Get("Authorization")
+
+			// 1. API keys have their own header, so they can never be mistaken for a session token.
+			if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+				if authHeader != "" {
+					utils.Error(w, r, http.StatusUnauthorized, "Send either an access token or an API key, not both", nil)
+					return
+				}
+
+				identity, err := apiKeyRepo.GetValid(r.Context(), utils.HashToken(apiKey))
+				if err != nil {
+					utils.Error(w, r, http.StatusUnauthorized, "API key is expired, revoked or invalid", nil)
+					return
+				}
+
+				ctx := withIdentity(r.Context(), identity.UserID, identity.Role, identity.Permissions, identity.WarehouseIDs)
+				ctx = context.WithValue(ctx, APIKeyIDKey, identity.KeyID)
+				ctx = model.WithAuditOrigin(ctx, model.AuditOrigin{
+					ActorID:   identity.UserID,
+					APIKeyID:  &identity.KeyID,
+					IPAddress: ClientInfo(r).IPAddress,
+				})
+
+				// Best effort, like the session's last-seen update below.
+				_ = apiKeyRepo.Touch(r.Context(), identity.KeyID)
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			// 2. Extract the token from the authorization header.
 			if authHeader == "" {
 				utils.Error(w, r, http.StatusUnauthorized, "Missing authorization header", nil)
 				return
@@ -37,28 +81,57 @@ func Authenticate(sessionRepo repository.SessionRepository) func(http.Handler) h
 				return
 			}
 
-			// 2. Parse the token string into a UUID.
-			sessionID, err := uuid.Parse(parts[1])
-			if err != nil {
+			// 3. Reject an empty token before touching the database.
+			if parts[1] == "" {
 				utils.Error(w, r, http.StatusUnauthorized, "Invalid token format", nil)
 				return
 			}
 
-			// 3. Query the database to check if the session is valid.
-			session, err := sessionRepo.GetValid(r.Context(), sessionID)
+			// 4. Query the database by token hash to check if the session is valid.
+			session, err := sessionRepo.GetValid(r.Context(), utils.HashToken(parts[1]))
 			if err != nil {
 				// If the session is not found, expired, or revoked, reject the request.
 				utils.Error(w, r, http.StatusUnauthorized, "Token is expired or invalid", nil)
 				return
 			}
 
-			// 4. If valid, store the UserID and Role into the Request Context.
+			// 5. If valid, store the user's identity and the login it belongs to into the Request Context.
 			// This allows subsequent endpoints (e.g., /items) to identify the authenticated user.
-			ctx := context.WithValue(r.Context(), UserIDKey, session.UserID)
-			ctx = context.WithValue(ctx, UserRoleKey, string(session.Role))
+			ctx := withIdentity(r.Context(), session.UserID, session.Role, session.Permissions, session.WarehouseIDs)
+			ctx = context.WithValue(ctx, LoginSessionIDKey, session.FamilyID)
+			ctx = model.WithAuditOrigin(ctx, model.AuditOrigin{ActorID: session.UserID, IPAddress: ClientInfo(r).IPAddress})
+
+			// Best effort: a failed last-seen update must not block the request.
+			_ = sessionRepo.Touch(r.Context(), session.FamilyID)
 
 			// Proceed to the next handler with the populated context.
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// withIdentity stores the UserID, Role, its permissions and the warehouses the user may work in.
+// Users whose permissions include warehouses:all are not limited to their assigned warehouses.
+func withIdentity(ctx context.Context, userID uuid.UUID, role model.UserRole, permissions model.Permissions, warehouseIDs []uuid.UUID) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, userID)
+	ctx = context.WithValue(ctx, UserRoleKey, string(role))
+	ctx = context.WithValue(ctx, PermissionsKey, permissions)
+	return model.WithWarehouseScope(ctx, model.WarehouseScope{
+		All: permissions.Has(model.PermWarehousesAll),
+		IDs: warehouseIDs,
+	})
+}
+
+// RequireSession rejects requests authenticated with an API key. Endpoints that manage credentials
+// (password, 2FA, sessions and API keys themselves) need someone who actually logged in, so a
+// leaked key can neither lock its owner out nor mint more keys.
+// NOTE: This middleware MUST be placed AFTER the Authenticate middleware.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(LoginSessionIDKey).(uuid.UUID); !ok {
+			utils.Error(w, r, http.StatusForbidden, "Access denied: API keys cannot be used here, log in instead", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
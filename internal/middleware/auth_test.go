@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	userID, keyID, warehouseID := uuid.New(), uuid.New(), uuid.New()
+	const apiKey = "isk_scanner-gudang-a"
+
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockAPIKeyRepo := new(repository.MockAPIKeyRepository)
+	mockAPIKeyRepo.On("GetValid", mock.Anything, utils.HashToken(apiKey)).Return(&model.APIKeyIdentity{
+		KeyID:        keyID,
+		UserID:       userID,
+		Role:         model.RoleStaff,
+		Permissions:  model.Permissions{model.PermStockMove},
+		WarehouseIDs: []uuid.UUID{warehouseID},
+	}, nil)
+	mockAPIKeyRepo.On("GetValid", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+	mockAPIKeyRepo.On("Touch", mock.Anything, keyID).Return(nil)
+
+	var seen *http.Request
+	handler := Authenticate(mockSessionRepo, mockAPIKeyRepo)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("valid key acts as its owner", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items/x/stock-in", nil)
+		req.Header.Set(APIKeyHeader, apiKey)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, userID, seen.Context().Value(UserIDKey))
+		assert.Equal(t, keyID, seen.Context().Value(APIKeyIDKey))
+		assert.Equal(t, model.Permissions{model.PermStockMove}, seen.Context().Value(PermissionsKey))
+		assert.Nil(t, seen.Context().Value(LoginSessionIDKey))
+
+		// Tanpa warehouses:all, key cuma boleh nyentuh gudang pemiliknya
+		scope := model.WarehouseScopeFrom(seen.Context())
+		assert.False(t, scope.All)
+		assert.True(t, scope.Allows(warehouseID))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+		req.Header.Set(APIKeyHeader, "isk_tebak-tebak")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("key and bearer token together", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+		req.Header.Set(APIKeyHeader, apiKey)
+		req.Header.Set("Authorization", "Bearer some-session-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockSessionRepo.AssertNotCalled(t, "GetValid", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRequireSession_RejectsAPIKeys(t *testing.T) {
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockAPIKeyRepo := new(repository.MockAPIKeyRepository)
+
+	sessionToken, apiKey := "session-token", "isk_nightly-export"
+	familyID := uuid.New()
+	mockSessionRepo.On("GetValid", mock.Anything, utils.HashToken(sessionToken)).Return(&model.Session{
+		UserID:   uuid.New(),
+		FamilyID: familyID,
+		Role:     model.RoleStaff,
+	}, nil)
+	mockSessionRepo.On("Touch", mock.Anything, familyID).Return(nil)
+	mockAPIKeyRepo.On("GetValid", mock.Anything, utils.HashToken(apiKey)).Return(&model.APIKeyIdentity{UserID: uuid.New(), Role: model.RoleStaff}, nil)
+	mockAPIKeyRepo.On("Touch", mock.Anything, mock.Anything).Return(nil)
+
+	handler := Authenticate(mockSessionRepo, mockAPIKeyRepo)(RequireSession(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	withSession := httptest.NewRequest(http.MethodPost, "/api/v1/me/api-keys", nil)
+	withSession.Header.Set("Authorization", "Bearer "+sessionToken)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, withSession)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Key yang bocor gak boleh dipakai bikin key baru
+	withKey := httptest.NewRequest(http.MethodPost, "/api/v1/me/api-keys", nil)
+	withKey.Header.Set(APIKeyHeader, apiKey)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, withKey)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"inventory-system/internal/model"
+)
+
+// maxUserAgentLength caps what is stored per login; real user agents are far shorter.
+const maxUserAgentLength = 512
+
+// ClientInfo describes the caller for the session list and the audit trail. RemoteAddr has
+// already been rewritten by the RealIP middleware when the request came through a trusted proxy.
+func ClientInfo(r *http.Request) model.ClientInfo {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	// 🛡️ GUARD: Header proxy bisa diisi sembarang, simpan hanya IP yang valid
+	ip := ""
+	if parsed := net.ParseIP(host); parsed != nil {
+		ip = parsed.String()
+	}
+
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return model.ClientInfo{IPAddress: ip, UserAgent: userAgent}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/utils"
+)
+
+// ErrRateLimited is returned once a client has used up its requests for the current window.
+var ErrRateLimited = apperror.TooManyRequests("RATE_LIMITED", "too many requests; slow down and try again later")
+
+// RateLimit allows each client IP at most limit requests per window, using a fixed window counter.
+// Counters live in memory, so every instance of the app enforces its own limit.
+// NOTE: Place it after the RealIP middleware so clients behind a trusted proxy are told apart.
+func RateLimit(limit int, window time.Duration) func(http.Handler) http.Handler {
+	limiter := newRateLimiter(limit, window, time.Now)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if retryAfter, ok := limiter.allow(clientIP(r)); !ok {
+				utils.HandleError(w, r, ErrRateLimited.WithRetryAfter(retryAfter))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	now     func() time.Time
+	clients map[string]*rateWindow
+	sweptAt time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     now,
+		clients: make(map[string]*rateWindow),
+		sweptAt: now(),
+	}
+}
+
+// allow counts a request from key. When the limit is reached it returns false and how long
+// until the key's window resets.
+func (l *rateLimiter) allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	// Forget clients whose window has ended, at most once per window, so the map cannot grow forever.
+	if now.Sub(l.sweptAt) >= l.window {
+		for k, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, k)
+			}
+		}
+		l.sweptAt = now
+	}
+
+	w, ok := l.clients[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.clients[key] = &rateWindow{start: now, count: 1}
+		return 0, true
+	}
+
+	if w.count >= l.limit {
+		return w.start.Add(l.window).Sub(now), false
+	}
+	w.count++
+	return 0, true
+}
+
+// clientIP returns the host part of RemoteAddr, which RealIP has already rewritten for trusted proxies.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_LimitsEachClientPerWindow(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, time.Minute, func() time.Time { return now })
+
+	_, ok := limiter.allow("203.0.113.7")
+	assert.True(t, ok)
+	_, ok = limiter.allow("203.0.113.7")
+	assert.True(t, ok)
+
+	now = now.Add(20 * time.Second)
+	retryAfter, ok := limiter.allow("203.0.113.7")
+	assert.False(t, ok)
+	assert.Equal(t, 40*time.Second, retryAfter)
+
+	// IP lain punya jatah sendiri
+	_, ok = limiter.allow("198.51.100.1")
+	assert.True(t, ok)
+
+	// Window baru, jatah di-reset
+	now = now.Add(40 * time.Second)
+	_, ok = limiter.allow("203.0.113.7")
+	assert.True(t, ok)
+}
+
+func TestRateLimit_Returns429WithRetryAfter(t *testing.T) {
+	handler := RateLimit(1, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil))
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.NotEmpty(t, second.Header().Get("Retry-After"))
+}
@@ -4,31 +4,31 @@ import (
 	"net/http"
 	"slices"
 
+	"inventory-system/internal/model"
 	"inventory-system/pkg/utils"
 )
 
-// RequireRole restricts access to endpoints based on the user's role.
-// It accepts a variadic list of allowed roles.
+// RequirePermission restricts access to users whose role grants at least one of the given permissions.
+// Which role holds which permission lives in the database, so no role names appear in the routes.
 // NOTE: This middleware MUST be placed AFTER the Authenticate middleware.
-func RequireRole(allowedRoles ...string) func(http.Handler) http.Handler {
+func RequirePermission(anyOf ...model.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-			// 1. Extract the user role from the request context.
-			userRole, ok := r.Context().Value(UserRoleKey).(string)
-			if !ok || userRole == "" {
-				utils.Error(w, r, http.StatusForbidden, "Access denied: Role identifier is missing", nil)
+			// 1. Extract the permissions of the user's role from the request context.
+			granted, ok := r.Context().Value(PermissionsKey).(model.Permissions)
+			if !ok {
+				utils.Error(w, r, http.StatusForbidden, "Access denied: Permissions are missing", nil)
 				return
 			}
 
-			// 2 & 3. Check if the role is allowed AND block if it's not (IN ONE LINE!)
-			// slices.Contains bakal otomatis ngecek apakah userRole ada di dalam allowedRoles
-			if !slices.Contains(allowedRoles, userRole) {
+			// 2. Block the request unless one of the required permissions was granted.
+			if !slices.ContainsFunc(anyOf, granted.Has) {
 				utils.Error(w, r, http.StatusForbidden, "Access denied: Insufficient permissions", nil)
 				return
 			}
 
-			// 4. Role is valid, proceed to the actual handler.
+			// 3. Permission is granted, proceed to the actual handler.
 			next.ServeHTTP(w, r)
 		})
 	}
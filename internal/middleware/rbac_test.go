@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inventory-system/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	handler := RequirePermission(model.PermStockAdjust, model.PermItemsWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name    string
+		granted any
+		want    int
+	}{
+		{"no permissions in context", nil, http.StatusForbidden},
+		{"none of the required permissions", model.Permissions{model.PermStockMove, model.PermSalesCreate}, http.StatusForbidden},
+		{"one of the required permissions is enough", model.Permissions{model.PermStockMove, model.PermItemsWrite}, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/items", nil)
+			if tc.granted != nil {
+				req = req.WithContext(context.WithValue(req.Context(), PermissionsKey, tc.granted))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.want, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP rewrites RemoteAddr to the client address reported by X-Forwarded-For or X-Real-IP,
+// but only when the request comes straight from one of the trusted proxies. Anyone else could
+// put any address in those headers, so their requests keep the socket address.
+func RealIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := forwardedIP(r, trustedProxies); ok {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP returns the client address a trusted proxy forwarded the request for.
+func forwardedIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	peer, ok := parseIP(r.RemoteAddr)
+	if !ok || !isTrusted(peer, trusted) {
+		return netip.Addr{}, false
+	}
+
+	// Every proxy appends the address it received the request from, so the header is read from
+	// the right: the first hop that is not one of our proxies is the client.
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		var client netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, ok := parseIP(strings.TrimSpace(hops[i]))
+			if !ok {
+				break
+			}
+			client = hop
+			if !isTrusted(hop, trusted) {
+				break
+			}
+		}
+		if client.IsValid() {
+			return client, true
+		}
+	}
+
+	if ip, ok := parseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ok {
+		return ip, true
+	}
+	return netip.Addr{}, false
+}
+
+// parseIP accepts a bare address or a host:port pair.
+func parseIP(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	ip, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP_TrustsHeadersOnlyFromProxies(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		xRealIP    string
+		want       string
+	}{
+		{"direct client cannot spoof X-Forwarded-For", "203.0.113.7:51000", "198.51.100.1", "", "203.0.113.7:51000"},
+		{"direct client cannot spoof X-Real-IP", "203.0.113.7:51000", "", "198.51.100.1", "203.0.113.7:51000"},
+		{"trusted proxy names the client", "10.0.0.2:443", "198.51.100.1", "", "198.51.100.1"},
+		{"client-supplied hops left of the proxy are ignored", "10.0.0.2:443", "1.2.3.4, 198.51.100.1, 10.0.0.3", "", "198.51.100.1"},
+		{"trusted proxy with X-Real-IP", "10.0.0.2:443", "", "198.51.100.1", "198.51.100.1"},
+		{"garbage header keeps the socket address", "10.0.0.2:443", "not-an-ip", "", "10.0.0.2:443"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			handler := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.xff != "" {
+				req.Header.Set("X-Forwarded-For", tc.xff)
+			}
+			if tc.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tc.xRealIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Header proxy cuma dipercaya kalau datangnya dari proxy kita sendiri
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
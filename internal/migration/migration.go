@@ -0,0 +1,124 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+// fileNamePattern matches migration files such as "0002_add_categories.sql".
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_\-]+)\.sql$`)
+
+// Migration is a single versioned schema change discovered on disk.
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+// Reversible reports whether the migration declares a Down section.
+func (m Migration) Reversible() bool {
+	return strings.TrimSpace(m.DownSQL) != ""
+}
+
+// Load discovers every migration file in fsys and returns them ordered by version.
+// Each file may contain an optional "-- +migrate Up" section and a "-- +migrate Down"
+// section. A file without markers is treated as up-only (irreversible).
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[int64]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q (expected <version>_<name>.sql)", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d (%s and %s)", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		up, down, err := parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse migration %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     match[2],
+			UpSQL:    up,
+			DownSQL:  down,
+			Checksum: Checksum(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Checksum returns the hex encoded SHA-256 of a migration file's raw content.
+// It is stored alongside every applied version so edited files can be detected.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// parse splits a migration file into its Up and Down sections.
+func parse(content string) (up, down string, err error) {
+	upIdx := strings.Index(content, upMarker)
+	downIdx := strings.Index(content, downMarker)
+
+	switch {
+	case upIdx == -1 && downIdx == -1:
+		up = content
+	case upIdx == -1:
+		return "", "", fmt.Errorf("%q section found without %q section", downMarker, upMarker)
+	case downIdx == -1:
+		up = content[upIdx+len(upMarker):]
+	case downIdx < upIdx:
+		return "", "", fmt.Errorf("%q section must come after %q section", downMarker, upMarker)
+	default:
+		up = content[upIdx+len(upMarker) : downIdx]
+		down = content[downIdx+len(downMarker):]
+	}
+
+	if strings.TrimSpace(up) == "" {
+		return "", "", fmt.Errorf("migration has an empty %q section", upMarker)
+	}
+
+	return strings.TrimSpace(up), strings.TrimSpace(down), nil
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_OrdersAndSplitsSections(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.sql": {Data: []byte("-- +migrate Up\nCREATE INDEX idx ON t(a);\n-- +migrate Down\nDROP INDEX idx;\n")},
+		"0001_init.sql":      {Data: []byte("CREATE TABLE t (a INT);\n")},
+		"README.md":          {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	// File tanpa marker dianggap up-only
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "CREATE TABLE t (a INT);", migrations[0].UpSQL)
+	assert.False(t, migrations[0].Reversible())
+
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, "CREATE INDEX idx ON t(a);", migrations[1].UpSQL)
+	assert.Equal(t, "DROP INDEX idx;", migrations[1].DownSQL)
+	assert.True(t, migrations[1].Reversible())
+
+	// Checksum harus berubah kalau isi file diedit
+	assert.Equal(t, Checksum(fsys["0001_init.sql"].Data), migrations[0].Checksum)
+	assert.NotEqual(t, Checksum([]byte("CREATE TABLE t (a BIGINT);\n")), migrations[0].Checksum)
+}
+
+func TestLoad_RejectsInvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"duplicate version": {
+			"0001_a.sql": {Data: []byte("SELECT 1;")},
+			"001_b.sql":  {Data: []byte("SELECT 1;")},
+		},
+		"bad file name": {
+			"init.sql": {Data: []byte("SELECT 1;")},
+		},
+		"down before up": {
+			"0001_a.sql": {Data: []byte("-- +migrate Down\nSELECT 1;\n-- +migrate Up\nSELECT 2;")},
+		},
+		"empty up": {
+			"0001_a.sql": {Data: []byte("-- +migrate Up\n-- +migrate Down\nSELECT 1;")},
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
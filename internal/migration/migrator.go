@@ -0,0 +1,432 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"inventory-system/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// advisoryLockKey serialises concurrent migration runs against the same database.
+const advisoryLockKey int64 = 7_310_529_001
+
+var (
+	// ErrChecksumMismatch is returned when an applied migration file was edited after it ran.
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrMissingMigration is returned when the database knows a version that has no file on disk.
+	ErrMissingMigration = errors.New("applied migration file is missing")
+	// ErrIrreversible is returned when rolling back a migration without a Down section.
+	ErrIrreversible = errors.New("migration has no down section")
+	// ErrUnknownVersion is returned by Goto and Baseline when the target version does not exist.
+	ErrUnknownVersion = errors.New("unknown migration version")
+	// ErrUnrecordedSchema is returned by Up when the database already has tables but no recorded
+	// migrations, e.g. one created before migrations were tracked. Baseline records it first.
+	ErrUnrecordedSchema = errors.New("database has tables but no recorded migrations; run `migrate baseline VERSION` first")
+	// ErrAlreadyBaselined is returned by Baseline when migrations are already recorded.
+	ErrAlreadyBaselined = errors.New("migrations are already recorded")
+)
+
+// AppliedMigration is a row of the schema_migrations tracking table.
+type AppliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status describes a known migration and whether it has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // true when the file checksum differs from the applied one
+	Missing   bool // true when the version is applied but the file is gone
+}
+
+// Migrator applies and rolls back versioned migrations, recording progress
+// in the schema_migrations table.
+type Migrator struct {
+	db         repository.PgxIface
+	migrations []Migration
+	logger     *zap.Logger
+}
+
+// NewMigrator creates a Migrator for the given, already ordered, migrations.
+func NewMigrator(db repository.PgxIface, migrations []Migration, logger *zap.Logger) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Up applies every pending migration in version order and returns how many ran.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.upTo(ctx, -1)
+}
+
+// Down rolls back the n most recently applied migrations and returns how many ran.
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("down step count must be at least 1, got %d", n)
+	}
+
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(applied) - 1; i >= 0 && count < n; i-- {
+		if err := m.rollback(ctx, m.find(applied[i].Version)); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Goto migrates up or down until version is the latest applied migration.
+// Version 0 rolls back everything.
+func (m *Migrator) Goto(ctx context.Context, version int64) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Roll back anything newer than the target first, newest first.
+	count := 0
+	for i := len(applied) - 1; i >= 0; i-- {
+		if applied[i].Version <= version {
+			break
+		}
+		if err := m.rollback(ctx, m.find(applied[i].Version)); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	ran, err := m.upTo(ctx, version)
+	return count + ran, err
+}
+
+// Baseline records every migration up to and including version as applied without running it,
+// for a database whose schema already matches that version. It only works while nothing is
+// recorded yet, and returns how many migrations it recorded.
+func (m *Migrator) Baseline(ctx context.Context, version int64) (int, error) {
+	if m.find(version) == nil {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, advisoryLockKey); err != nil {
+		return 0, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	var recorded bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations)`).Scan(&recorded); err != nil {
+		return 0, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	if recorded {
+		return 0, ErrAlreadyBaselined
+	}
+
+	count := 0
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		insertQuery := `
+			INSERT INTO schema_migrations (version, name, checksum)
+			VALUES ($1, $2, $3)
+		`
+		if _, err := tx.Exec(ctx, insertQuery, mig.Version, mig.Name, mig.Checksum); err != nil {
+			return 0, fmt.Errorf("failed to record migration %d: %w", mig.Version, err)
+		}
+		count++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	m.logger.Info("Recorded existing schema as baseline", zap.Int64("version", version), zap.Int("count", count))
+	return count, nil
+}
+
+// Status lists every known migration (on disk or applied) with its state.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedByVersion := make(map[int64]AppliedMigration, len(applied))
+	for _, a := range applied {
+		appliedByVersion[a.Version] = a
+	}
+
+	var statuses []Status
+	for _, mig := range m.migrations {
+		st := Status{Migration: mig}
+		if a, ok := appliedByVersion[mig.Version]; ok {
+			appliedAt := a.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &appliedAt
+			st.Modified = a.Checksum != mig.Checksum
+			delete(appliedByVersion, mig.Version)
+		}
+		statuses = append(statuses, st)
+	}
+
+	// Versions recorded in the database without a matching file.
+	for _, a := range applied {
+		if _, ok := appliedByVersion[a.Version]; !ok {
+			continue
+		}
+		appliedAt := a.AppliedAt
+		statuses = append(statuses, Status{
+			Migration: Migration{Version: a.Version, Name: a.Name, Checksum: a.Checksum},
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Reset drops every object in the public schema and re-applies all migrations.
+// It is destructive and must only be reachable behind an explicit confirmation.
+func (m *Migrator) Reset(ctx context.Context) (int, error) {
+	m.logger.Warn("Dropping public schema to reset database...")
+
+	resetQuery := `
+		DROP SCHEMA public CASCADE;
+		CREATE SCHEMA public;
+		GRANT ALL ON SCHEMA public TO public;
+	`
+	if _, err := m.db.Exec(ctx, resetQuery); err != nil {
+		return 0, fmt.Errorf("failed to reset database schema: %w", err)
+	}
+
+	return m.Up(ctx)
+}
+
+// upTo applies pending migrations up to and including target (-1 means all).
+func (m *Migrator) upTo(ctx context.Context, target int64) (int, error) {
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// 🛡️ GUARD: Database lama yang belum pernah dicatat jangan ditimpa migration 0001 lagi
+	if len(applied) == 0 {
+		untracked, err := m.hasUntrackedTables(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if untracked {
+			return 0, ErrUnrecordedSchema
+		}
+	}
+
+	done := make(map[int64]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	count := 0
+	for _, mig := range m.migrations {
+		if target >= 0 && mig.Version > target {
+			break
+		}
+		if done[mig.Version] {
+			continue
+		}
+		if err := m.apply(ctx, mig); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// apply runs a single migration's Up section and records it, atomically.
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	m.logger.Info("Applying migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	// Another runner may have applied this version while we waited for the lock.
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, mig.Version).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check migration %d: %w", mig.Version, err)
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, mig.UpSQL); err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	insertQuery := `
+		INSERT INTO schema_migrations (version, name, checksum)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(ctx, insertQuery, mig.Version, mig.Name, mig.Checksum); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", mig.Version, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// rollback runs a single migration's Down section and forgets it, atomically.
+func (m *Migrator) rollback(ctx context.Context, mig *Migration) error {
+	if !mig.Reversible() {
+		return fmt.Errorf("%w: %d_%s", ErrIrreversible, mig.Version, mig.Name)
+	}
+
+	m.logger.Info("Rolling back migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+	if err != nil {
+		return fmt.Errorf("failed to unrecord migration %d: %w", mig.Version, err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Already rolled back by a concurrent runner.
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, mig.DownSQL); err != nil {
+		return fmt.Errorf("failed to roll back migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// verify ensures the tracking table exists and that every applied migration
+// still has an unmodified file on disk. It returns the applied migrations.
+func (m *Migrator) verify(ctx context.Context) ([]AppliedMigration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range applied {
+		mig := m.find(a.Version)
+		if mig == nil {
+			return nil, fmt.Errorf("%w: version %d (%s)", ErrMissingMigration, a.Version, a.Name)
+		}
+		if mig.Checksum != a.Checksum {
+			return nil, fmt.Errorf("%w: version %d (%s) was edited after it was applied", ErrChecksumMismatch, a.Version, a.Name)
+		}
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	if _, err := m.db.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// hasUntrackedTables reports whether the current schema has any table besides schema_migrations.
+func (m *Migrator) hasUntrackedTables(ctx context.Context) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'
+		)
+	`
+	var exists bool
+	if err := m.db.QueryRow(ctx, query).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to inspect existing tables: %w", err)
+	}
+	return exists, nil
+}
+
+// applied returns the rows of schema_migrations ordered by version.
+func (m *Migrator) applied(ctx context.Context) ([]AppliedMigration, error) {
+	query := `
+		SELECT version, name, checksum, applied_at
+		FROM schema_migrations
+		ORDER BY version ASC
+	`
+	rows, err := m.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
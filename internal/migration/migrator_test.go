@@ -0,0 +1,288 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeDB meniru schema_migrations di memori dan mencatat SQL migration yang dijalankan.
+type fakeDB struct {
+	repository.PgxIface
+	recorded  map[int64]AppliedMigration
+	tables    bool     // ada tabel lain selain schema_migrations
+	ran       []string // Up/Down SQL yang sudah dieksekusi, berurutan
+	failOnSQL string   // SQL migration yang dibuat gagal
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{recorded: make(map[int64]AppliedMigration)}
+}
+
+func (db *fakeDB) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	// Cuma CREATE TABLE IF NOT EXISTS schema_migrations yang lewat pool langsung
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *fakeDB) Query(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	var applied []AppliedMigration
+	for _, a := range db.recorded {
+		applied = append(applied, a)
+	}
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version < applied[j].Version })
+	return &fakeRows{applied: applied, idx: -1}, nil
+}
+
+func (db *fakeDB) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	return boolRow(db.tables)
+}
+
+func (db *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{db: db, pending: make(map[int64]*AppliedMigration)}, nil
+}
+
+// fakeTx menampung perubahan sampai Commit, biar migration yang gagal gak tercatat.
+type fakeTx struct {
+	pgx.Tx
+	db      *fakeDB
+	pending map[int64]*AppliedMigration // nil = dihapus
+	ran     []string
+}
+
+func (tx *fakeTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "pg_advisory_xact_lock"):
+		return pgconn.CommandTag{}, nil
+	case strings.Contains(sql, "INSERT INTO schema_migrations"):
+		version := args[0].(int64)
+		tx.pending[version] = &AppliedMigration{Version: version, Name: args[1].(string), Checksum: args[2].(string), AppliedAt: time.Now()}
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	case strings.Contains(sql, "DELETE FROM schema_migrations"):
+		version := args[0].(int64)
+		if _, ok := tx.db.recorded[version]; !ok {
+			return pgconn.NewCommandTag("DELETE 0"), nil
+		}
+		tx.pending[version] = nil
+		return pgconn.NewCommandTag("DELETE 1"), nil
+	}
+
+	if sql == tx.db.failOnSQL {
+		return pgconn.CommandTag{}, errors.New("syntax error")
+	}
+	tx.ran = append(tx.ran, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (tx *fakeTx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	if len(args) == 0 {
+		// SELECT EXISTS (SELECT 1 FROM schema_migrations)
+		return boolRow(len(tx.db.recorded) > 0)
+	}
+	_, ok := tx.db.recorded[args[0].(int64)]
+	return boolRow(ok)
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	for version, a := range tx.pending {
+		if a == nil {
+			delete(tx.db.recorded, version)
+			continue
+		}
+		tx.db.recorded[version] = *a
+	}
+	tx.db.ran = append(tx.db.ran, tx.ran...)
+	tx.pending = nil
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error { return nil }
+
+type boolRow bool
+
+func (r boolRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+	return nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+	applied []AppliedMigration
+	idx     int
+}
+
+func (r *fakeRows) Next() bool { r.idx++; return r.idx < len(r.applied) }
+func (r *fakeRows) Err() error { return nil }
+func (r *fakeRows) Close()     {}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	a := r.applied[r.idx]
+	*dest[0].(*int64), *dest[1].(*string), *dest[2].(*string), *dest[3].(*time.Time) = a.Version, a.Name, a.Checksum, a.AppliedAt
+	return nil
+}
+
+// testMigrations: 1 dan 2 bisa di-rollback, 3 up-only.
+func testMigrations() []Migration {
+	migs := []Migration{
+		{Version: 1, Name: "init", UpSQL: "CREATE TABLE a", DownSQL: "DROP TABLE a"},
+		{Version: 2, Name: "b", UpSQL: "CREATE TABLE b", DownSQL: "DROP TABLE b"},
+		{Version: 3, Name: "c", UpSQL: "CREATE TABLE c"},
+	}
+	for i := range migs {
+		migs[i].Checksum = Checksum([]byte(migs[i].UpSQL + migs[i].DownSQL))
+	}
+	return migs
+}
+
+func recordedVersions(db *fakeDB) []int64 {
+	var versions []int64
+	for _, mig := range testMigrations() {
+		if _, ok := db.recorded[mig.Version]; ok {
+			versions = append(versions, mig.Version)
+		}
+	}
+	return versions
+}
+
+func TestMigrator_UpAppliesPendingInOrder(t *testing.T) {
+	db := newFakeDB()
+	m := NewMigrator(db, testMigrations(), zap.NewNop())
+
+	count, err := m.Up(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"CREATE TABLE a", "CREATE TABLE b", "CREATE TABLE c"}, db.ran)
+	assert.Equal(t, testMigrations()[1].Checksum, db.recorded[2].Checksum)
+
+	// Jalan lagi gak ngapa-ngapain
+	count, err = m.Up(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Len(t, db.ran, 3)
+}
+
+func TestMigrator_FailedMigrationIsNotRecorded(t *testing.T) {
+	db := newFakeDB()
+	db.failOnSQL = "CREATE TABLE b"
+	m := NewMigrator(db, testMigrations(), zap.NewNop())
+
+	count, err := m.Up(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []int64{1}, recordedVersions(db))
+}
+
+func TestMigrator_DownRollsBackNewestFirst(t *testing.T) {
+	db := newFakeDB()
+	m := NewMigrator(db, testMigrations()[:2], zap.NewNop())
+	_, err := m.Up(context.Background())
+	require.NoError(t, err)
+
+	count, err := m.Down(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "DROP TABLE b", db.ran[len(db.ran)-1])
+	assert.Equal(t, []int64{1}, recordedVersions(db))
+
+	_, err = m.Down(context.Background(), 0)
+	assert.Error(t, err)
+}
+
+func TestMigrator_DownStopsAtIrreversibleMigration(t *testing.T) {
+	db := newFakeDB()
+	m := NewMigrator(db, testMigrations(), zap.NewNop())
+	_, err := m.Up(context.Background())
+	require.NoError(t, err)
+
+	count, err := m.Down(context.Background(), 2)
+
+	assert.ErrorIs(t, err, ErrIrreversible)
+	assert.Zero(t, count)
+	assert.Equal(t, []int64{1, 2, 3}, recordedVersions(db))
+}
+
+func TestMigrator_GotoMovesBothWays(t *testing.T) {
+	db := newFakeDB()
+	m := NewMigrator(db, testMigrations()[:2], zap.NewNop())
+
+	count, err := m.Goto(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []int64{1}, recordedVersions(db))
+
+	count, err = m.Goto(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []int64{1, 2}, recordedVersions(db))
+
+	// Versi 0 = rollback semuanya
+	count, err = m.Goto(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Empty(t, recordedVersions(db))
+
+	_, err = m.Goto(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+}
+
+func TestMigrator_RefusesEditedOrMissingFiles(t *testing.T) {
+	t.Run("edited after it was applied", func(t *testing.T) {
+		db := newFakeDB()
+		_, err := NewMigrator(db, testMigrations(), zap.NewNop()).Up(context.Background())
+		require.NoError(t, err)
+
+		edited := testMigrations()
+		edited[0].Checksum = Checksum([]byte("CREATE TABLE a (id INT)"))
+		_, err = NewMigrator(db, edited, zap.NewNop()).Up(context.Background())
+
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("file removed after it was applied", func(t *testing.T) {
+		db := newFakeDB()
+		_, err := NewMigrator(db, testMigrations(), zap.NewNop()).Up(context.Background())
+		require.NoError(t, err)
+
+		_, err = NewMigrator(db, testMigrations()[1:], zap.NewNop()).Down(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrMissingMigration)
+	})
+}
+
+func TestMigrator_BaselineRecordsExistingSchema(t *testing.T) {
+	db := newFakeDB()
+	db.tables = true // database lama, dibuat sebelum migration dicatat
+	m := NewMigrator(db, testMigrations(), zap.NewNop())
+
+	_, err := m.Up(context.Background())
+	require.ErrorIs(t, err, ErrUnrecordedSchema)
+	assert.Empty(t, db.ran)
+
+	_, err = m.Baseline(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+
+	count, err := m.Baseline(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Empty(t, db.ran, "baseline must not run any SQL")
+
+	// Setelah baseline, cuma migration yang lebih baru yang dijalankan
+	count, err = m.Up(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"CREATE TABLE c"}, db.ran)
+
+	_, err = m.Baseline(context.Background(), 3)
+	assert.ErrorIs(t, err, ErrAlreadyBaselined)
+}
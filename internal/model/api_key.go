@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, so a leaked key is easy to recognise in logs and code.
+const APIKeyPrefix = "isk_"
+
+// APIKey is a named, long-lived credential a user mints for scripts and devices. It acts as its
+// owner, limited to Permissions. Only a hash of the key is stored.
+type APIKey struct {
+	BaseSimple
+	UserID      uuid.UUID   `json:"user_id" db:"user_id"`
+	Name        string      `json:"name" db:"name"`
+	KeyPrefix   string      `json:"key_prefix" db:"key_prefix"`
+	KeyHash     string      `json:"-" db:"key_hash"`
+	Permissions Permissions `json:"permissions" db:"permissions"` // nil: everything the owner's role has
+	ExpiresAt   *time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt  *time.Time  `json:"last_used_at" db:"last_used_at"`
+	RevokedAt   *time.Time  `json:"revoked_at" db:"revoked_at"`
+}
+
+// APIKeyIdentity is who an API key acts as right now: its owner's current role, the permissions
+// the key grants within that role, and the owner's warehouses.
+type APIKeyIdentity struct {
+	KeyID        uuid.UUID
+	UserID       uuid.UUID
+	Role         UserRole
+	Permissions  Permissions
+	WarehouseIDs []uuid.UUID
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntity is the kind of record an audit event is about.
+type AuditEntity string
+
+const (
+	AuditEntityUser      AuditEntity = "user"
+	AuditEntityItem      AuditEntity = "item"
+	AuditEntityWarehouse AuditEntity = "warehouse"
+	AuditEntityStock     AuditEntity = "stock"
+)
+
+// AuditAction names what happened, written as "entity.verb".
+type AuditAction string
+
+const (
+	AuditUserCreate        AuditAction = "user.create"
+	AuditUserUpdate        AuditAction = "user.update"
+	AuditUserDelete        AuditAction = "user.delete"
+	AuditUserRestore       AuditAction = "user.restore"
+	AuditUserSetWarehouses AuditAction = "user.set_warehouses"
+	AuditItemCreate        AuditAction = "item.create"
+	AuditItemUpdate        AuditAction = "item.update"
+	AuditItemPriceChange   AuditAction = "item.price_change"
+	AuditItemDelete        AuditAction = "item.delete"
+	AuditWarehouseCreate   AuditAction = "warehouse.create"
+	AuditWarehouseUpdate   AuditAction = "warehouse.update"
+	AuditWarehouseDelete   AuditAction = "warehouse.delete"
+	AuditWarehouseRestore  AuditAction = "warehouse.restore"
+	AuditStockAdjust       AuditAction = "stock.adjust"
+)
+
+// AuditEvent is one row of the append-only "audit_events" table. Every event carries the hash of
+// the one before it, so removing or editing a row breaks the chain from that row on.
+type AuditEvent struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	Seq        int64           `json:"seq" db:"seq"` // chain order, assigned by the database
+	ActorID    *uuid.UUID      `json:"actor_id" db:"actor_id"`
+	APIKeyID   *uuid.UUID      `json:"api_key_id" db:"api_key_id"`
+	Action     AuditAction     `json:"action" db:"action"`
+	EntityType AuditEntity     `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id" db:"entity_id"`
+	Changes    json.RawMessage `json:"changes" db:"changes"` // {"field": {"old": ..., "new": ...}}
+	RequestID  string          `json:"request_id" db:"request_id"`
+	IPAddress  string          `json:"ip_address" db:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	PrevHash   string          `json:"prev_hash" db:"prev_hash"`
+	Hash       string          `json:"hash" db:"hash"`
+}
+
+// ComputeHash returns the SHA-256 over the event's content and PrevHash. CreatedAt must already be
+// truncated to microseconds, the precision PostgreSQL stores, or a stored event will not verify.
+func (e *AuditEvent) ComputeHash() string {
+	optional := func(id *uuid.UUID) string {
+		if id == nil {
+			return ""
+		}
+		return id.String()
+	}
+
+	// A JSON array keeps the fields apart: no value can run into the next one.
+	content, _ := json.Marshal([]string{
+		e.PrevHash,
+		e.ID.String(),
+		optional(e.ActorID),
+		optional(e.APIKeyID),
+		string(e.Action),
+		string(e.EntityType),
+		e.EntityID.String(),
+		string(e.Changes),
+		e.RequestID,
+		e.IPAddress,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditOrigin is who made a request and from where, as recorded on the audit events it causes.
+type AuditOrigin struct {
+	ActorID   uuid.UUID
+	APIKeyID  *uuid.UUID // set when the request used an API key instead of a login
+	IPAddress string
+}
+
+type auditOriginKey struct{}
+
+// WithAuditOrigin returns a copy of ctx carrying origin. The authentication middleware sets it once
+// per request so services can audit changes without passing the caller through every call.
+func WithAuditOrigin(ctx context.Context, origin AuditOrigin) context.Context {
+	return context.WithValue(ctx, auditOriginKey{}, origin)
+}
+
+// AuditOriginFrom returns the origin stored in ctx. Work outside a request (seeding, tests) has none.
+func AuditOriginFrom(ctx context.Context) (AuditOrigin, bool) {
+	origin, ok := ctx.Value(auditOriginKey{}).(AuditOrigin)
+	return origin, ok
+}
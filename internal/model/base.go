@@ -9,6 +9,7 @@ import (
 // BaseModel contains common fields used across all database models.
 type BaseModel struct {
 	ID        uuid.UUID  `json:"id" db:"id"`
+	Version   int        `json:"version" db:"version"` // bumped by every edit, for optimistic concurrency
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
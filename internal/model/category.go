@@ -0,0 +1,21 @@
+package model
+
+import "github.com/google/uuid"
+
+// CategoryItemStrategy decides what happens to a category's items when it is deleted.
+type CategoryItemStrategy string
+
+const (
+	// CategoryItemsUncategorize leaves the items without a category (like ON DELETE SET NULL).
+	CategoryItemsUncategorize CategoryItemStrategy = "uncategorize"
+	// CategoryItemsMoveToParent moves the items up to the deleted category's parent.
+	CategoryItemsMoveToParent CategoryItemStrategy = "move_to_parent"
+)
+
+// Category represents the "categories" table. A nil ParentID marks a root category.
+type Category struct {
+	BaseModel
+	Name        string     `json:"name" db:"name"`
+	Description string     `json:"description" db:"description"`
+	ParentID    *uuid.UUID `json:"parent_id" db:"parent_id"`
+}
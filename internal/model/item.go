@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Item represents the "items" table, the core of the inventory.
+// Stock is only ever changed through the stock ledger (stock_logs).
+type Item struct {
+	BaseModel
+	SKU        string          `json:"sku" db:"sku"`
+	Name       string          `json:"name" db:"name"`
+	CategoryID *uuid.UUID      `json:"category_id" db:"category_id"`
+	ShelfID    *uuid.UUID      `json:"shelf_id" db:"shelf_id"`
+	Stock      int             `json:"stock" db:"stock"`
+	Price      decimal.Decimal `json:"price" db:"price"`
+
+	// Read-only fields joined from related tables for display.
+	CategoryName  string     `json:"category_name" db:"-"`
+	ShelfName     string     `json:"shelf_name" db:"-"`
+	WarehouseID   *uuid.UUID `json:"warehouse_id" db:"-"`
+	WarehouseName string     `json:"warehouse_name" db:"-"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// OIDCLoginState remembers a single sign-on login that was sent to the OpenID provider.
+// It is used up when the user comes back with the matching state, or expires at ExpiresAt.
+// BrowserHash is the SHA-256 of the cookie given to the browser that started the login.
+type OIDCLoginState struct {
+	BaseSimple
+	StateHash    string    `json:"-" db:"state_hash"`
+	BrowserHash  string    `json:"-" db:"browser_hash"`
+	Nonce        string    `json:"-" db:"nonce"`
+	CodeVerifier string    `json:"-" db:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+}
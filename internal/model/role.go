@@ -0,0 +1,92 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission is one action a role may be granted, written as "resource:action".
+// Reading master data only needs a valid session and is not a permission.
+type Permission string
+
+const (
+	PermUsersManage     Permission = "users:manage"
+	PermUsersRestore    Permission = "users:restore"
+	PermRolesManage     Permission = "roles:manage"
+	PermWarehousesWrite Permission = "warehouses:write"
+	PermWarehousesAll   Permission = "warehouses:all"
+	PermCategoriesWrite Permission = "categories:write"
+	PermItemsWrite      Permission = "items:write"
+	PermStockMove       Permission = "stock:move"
+	PermStockAdjust     Permission = "stock:adjust"
+	PermSalesCreate     Permission = "sales:create"
+	PermSalesViewAll    Permission = "sales:view_all"
+	PermSalesVoid       Permission = "sales:void"
+	PermAuditRead       Permission = "audit:read"
+)
+
+// PermissionDescriptions lists every permission the application checks, with what it allows.
+var PermissionDescriptions = map[Permission]string{
+	PermUsersManage:     "Create, update and delete users; revoke their sessions, clear lockouts and reset 2FA",
+	PermUsersRestore:    "List and restore deleted users",
+	PermRolesManage:     "Create, update and delete roles",
+	PermWarehousesWrite: "Create, update, delete and restore warehouses and their shelves",
+	PermWarehousesAll:   "See and move stock in every warehouse instead of only the assigned ones",
+	PermCategoriesWrite: "Create, update and delete categories",
+	PermItemsWrite:      "Create, update and delete items",
+	PermStockMove:       "Receive and issue stock",
+	PermStockAdjust:     "Adjust stock levels after a count",
+	PermSalesCreate:     "Check out sales and process returns",
+	PermSalesViewAll:    "See every user's sales instead of only your own",
+	PermSalesVoid:       "Void sales",
+	PermAuditRead:       "Read and verify the audit trail of administrative changes",
+}
+
+// Valid reports whether p is a permission the application knows about.
+func (p Permission) Valid() bool {
+	_, ok := PermissionDescriptions[p]
+	return ok
+}
+
+// Permissions is the set of permissions granted to a role.
+type Permissions []Permission
+
+// Has reports whether p is granted.
+func (ps Permissions) Has(p Permission) bool {
+	return slices.Contains(ps, p)
+}
+
+// Covers reports whether every permission in other is also granted here.
+func (ps Permissions) Covers(other Permissions) bool {
+	for _, p := range other {
+		if !ps.Has(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Role represents the "roles" table with the permissions from "role_permissions".
+// System roles are built in and cannot be deleted.
+type Role struct {
+	Name        UserRole    `json:"name" db:"name"`
+	Description string      `json:"description" db:"description"`
+	IsSystem    bool        `json:"is_system" db:"is_system"`
+	Permissions Permissions `json:"permissions" db:"-"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// Actor is the authenticated user a request acts on behalf of.
+type Actor struct {
+	UserID      uuid.UUID
+	Role        UserRole
+	Permissions Permissions
+}
+
+// Can reports whether the actor was granted p.
+func (a Actor) Can(p Permission) bool {
+	return a.Permissions.Has(p)
+}
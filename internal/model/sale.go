@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type SaleStatus string
+
+const (
+	SaleCompleted SaleStatus = "completed"
+	SaleVoided    SaleStatus = "voided"
+)
+
+// Sale represents the "sales" table: one checkout by a cashier.
+type Sale struct {
+	BaseSimple
+	UserID      uuid.UUID       `json:"user_id" db:"user_id"`
+	TotalAmount decimal.Decimal `json:"total_amount" db:"total_amount"`
+	Status      SaleStatus      `json:"status" db:"status"`
+	VoidedAt    *time.Time      `json:"voided_at" db:"voided_at"`
+	VoidedBy    *uuid.UUID      `json:"voided_by" db:"voided_by"`
+	VoidReason  string          `json:"void_reason" db:"void_reason"`
+
+	// Joined fields, not columns of "sales".
+	CashierName string     `json:"cashier_name" db:"-"`
+	Items       []SaleItem `json:"items" db:"-"`
+}
+
+// SaleItem represents the "sale_items" table. UnitPrice is a snapshot of the item's price at checkout.
+type SaleItem struct {
+	BaseSimple
+	SaleID           uuid.UUID       `json:"sale_id" db:"sale_id"`
+	ItemID           uuid.UUID       `json:"item_id" db:"item_id"`
+	Quantity         int             `json:"quantity" db:"quantity"`
+	ReturnedQuantity int             `json:"returned_quantity" db:"returned_quantity"`
+	UnitPrice        decimal.Decimal `json:"unit_price" db:"unit_price"`
+	Subtotal         decimal.Decimal `json:"subtotal" db:"subtotal"`
+
+	// Joined from "items" for display.
+	SKU  string `json:"sku" db:"-"`
+	Name string `json:"name" db:"-"`
+}
+
+// SaleReturn represents the "sale_returns" table: goods brought back against an earlier sale.
+type SaleReturn struct {
+	BaseSimple
+	SaleID       uuid.UUID       `json:"sale_id" db:"sale_id"`
+	UserID       uuid.UUID       `json:"user_id" db:"user_id"`
+	Reason       string          `json:"reason" db:"reason"`
+	RefundAmount decimal.Decimal `json:"refund_amount" db:"refund_amount"`
+
+	Items []SaleReturnItem `json:"items" db:"-"`
+}
+
+// SaleReturnItem represents the "sale_return_items" table.
+type SaleReturnItem struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	ReturnID     uuid.UUID       `json:"return_id" db:"return_id"`
+	SaleItemID   uuid.UUID       `json:"sale_item_id" db:"sale_item_id"`
+	Quantity     int             `json:"quantity" db:"quantity"`
+	RefundAmount decimal.Decimal `json:"refund_amount" db:"refund_amount"`
+
+	// Copied from the sale line for display and restocking.
+	ItemID uuid.UUID `json:"item_id" db:"-"`
+	SKU    string    `json:"sku" db:"-"`
+	Name   string    `json:"name" db:"-"`
+}
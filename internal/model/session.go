@@ -6,10 +6,48 @@ import (
 	"github.com/google/uuid"
 )
 
+// ClientInfo describes the client a login came from.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// LoginSession is one sign-in of a user: every access session and refresh token issued from
+// that login shares its ID as their FamilyID. This is what users see as "a session".
+type LoginSession struct {
+	BaseSimple
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	IPAddress  string     `json:"ip_address" db:"ip_address"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// Session is a short-lived access token. Only a hash of the bearer token is stored.
 type Session struct {
 	BaseSimple
+	TokenHash string     `json:"-" db:"token_hash"`
 	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	FamilyID  uuid.UUID  `json:"family_id" db:"family_id"` // the LoginSession this access token belongs to
 	Role      UserRole   `json:"role" db:"role"`
 	ExpiredAt time.Time  `json:"expired_at" db:"expired_at"`
 	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+
+	// Permissions of Role and the user's warehouse assignments as they are now, loaded with the
+	// session on every request.
+	Permissions  Permissions `json:"-" db:"-"`
+	WarehouseIDs []uuid.UUID `json:"-" db:"-"`
+}
+
+// RefreshToken is a long-lived, single-use token that can be exchanged for a new session.
+// All tokens issued from one login share a FamilyID.
+type RefreshToken struct {
+	BaseSimple
+	TokenHash string     `json:"-" db:"token_hash"`
+	FamilyID  uuid.UUID  `json:"family_id" db:"family_id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	ExpiredAt time.Time  `json:"expired_at" db:"expired_at"`
+	UsedAt    *time.Time `json:"used_at" db:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import "github.com/google/uuid"
+
+// Shelf represents the "shelves" table (a bin location inside a warehouse).
+type Shelf struct {
+	BaseModel
+	WarehouseID   uuid.UUID `json:"warehouse_id" db:"warehouse_id"`
+	WarehouseName string    `json:"warehouse_name" db:"-"` // Joined from warehouses for display
+	Name          string    `json:"name" db:"name"`
+}
+
+// ShelfContent is a single item placed on a shelf together with its quantity.
+type ShelfContent struct {
+	ItemID   uuid.UUID `json:"item_id" db:"id"`
+	SKU      string    `json:"sku" db:"sku"`
+	Name     string    `json:"name" db:"name"`
+	Quantity int       `json:"quantity" db:"stock"`
+}
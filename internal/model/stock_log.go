@@ -0,0 +1,24 @@
+package model
+
+import "github.com/google/uuid"
+
+type MovementType string
+
+const (
+	MovementIn         MovementType = "IN"
+	MovementOut        MovementType = "OUT"
+	MovementAdjustment MovementType = "ADJUSTMENT"
+)
+
+// StockLog represents a row of the "stock_logs" ledger. Rows are append-only.
+// Quantity is positive for IN/OUT and signed (the delta) for ADJUSTMENT.
+type StockLog struct {
+	BaseSimple
+	ItemID       uuid.UUID    `json:"item_id" db:"item_id"`
+	UserID       uuid.UUID    `json:"user_id" db:"user_id"`
+	MovementType MovementType `json:"movement_type" db:"movement_type"`
+	Quantity     int          `json:"quantity" db:"quantity"`
+	BalanceAfter int          `json:"balance_after" db:"balance_after"`
+	ReferenceID  *uuid.UUID   `json:"reference_id" db:"reference_id"`
+	Description  string       `json:"description" db:"description"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor is a user's TOTP enrollment. It is active once EnabledAt is set.
+type TwoFactor struct {
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	Secret       string     `json:"-" db:"secret"`
+	EnabledAt    *time.Time `json:"enabled_at" db:"enabled_at"`
+	LastUsedStep int64      `json:"-" db:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Enabled reports whether the enrollment was confirmed.
+func (t *TwoFactor) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// LoginChallenge is issued by a login whose password was right but still needs a second factor.
+// EnrollmentRequired means the user has to set up 2FA before the login can finish.
+type LoginChallenge struct {
+	BaseSimple
+	UserID             uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash          string     `json:"-" db:"token_hash"`
+	EnrollmentRequired bool       `json:"enrollment_required" db:"enrollment_required"`
+	Attempts           int        `json:"attempts" db:"attempts"`
+	ExpiresAt          time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt             *time.Time `json:"used_at" db:"used_at"`
+}
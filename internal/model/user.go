@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type UserRole string
 
 const (
@@ -15,4 +17,9 @@ type User struct {
 	Email        string   `json:"email" db:"email"`
 	PasswordHash string   `json:"-" db:"password_hash"`
 	Role         UserRole `json:"role" db:"role"`
+
+	// Login protection: consecutive failed logins since the last success, and the lockout they caused.
+	FailedLoginAttempts int        `json:"failed_login_attempts" db:"failed_login_attempts"`
+	LastFailedLoginAt   *time.Time `json:"last_failed_login_at" db:"last_failed_login_at"`
+	LockedUntil         *time.Time `json:"locked_until" db:"locked_until"`
 }
@@ -0,0 +1,8 @@
+package model
+
+// Warehouse represents the "warehouses" table in the database.
+type Warehouse struct {
+	BaseModel
+	Name     string `json:"name" db:"name"`
+	Location string `json:"location" db:"location"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseScope is the set of warehouses a request may see and move stock in.
+type WarehouseScope struct {
+	All bool        // the role has warehouses:all; IDs is ignored
+	IDs []uuid.UUID // the user's assigned warehouses
+}
+
+// Allows reports whether the scope includes the warehouse.
+func (s WarehouseScope) Allows(warehouseID uuid.UUID) bool {
+	return s.All || slices.Contains(s.IDs, warehouseID)
+}
+
+type warehouseScopeKey struct{}
+
+// WithWarehouseScope returns a copy of ctx limited to scope. It is set once per request by the
+// authentication middleware and read by the repositories, which filter every warehouse-bound query.
+func WithWarehouseScope(ctx context.Context, scope WarehouseScope) context.Context {
+	return context.WithValue(ctx, warehouseScopeKey{}, scope)
+}
+
+// WarehouseScopeFrom returns the scope stored in ctx. Code running outside an authenticated request
+// (migrations, seeding, tests) has no scope and sees every warehouse.
+func WarehouseScopeFrom(ctx context.Context) WarehouseScope {
+	scope, ok := ctx.Value(warehouseScopeKey{}).(WarehouseScope)
+	if !ok {
+		return WarehouseScope{All: true}
+	}
+	return scope
+}
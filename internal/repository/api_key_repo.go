@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// APIKeyRepository defines the contract for API key database operations.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	GetValid(ctx context.Context, keyHash string) (*model.APIKeyIdentity, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+type apiKeyRepository struct {
+	db PgxIface
+}
+
+// NewAPIKeyRepository creates and returns a new APIKeyRepository instance.
+func NewAPIKeyRepository(db PgxIface) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, user_id, name, key_prefix, key_hash, permissions, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+	return r.db.QueryRow(ctx, query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		fromPermissions(key.Permissions),
+		key.ExpiresAt,
+	).Scan(&key.CreatedAt)
+}
+
+// FindByUser lists a user's keys that have not been revoked, newest first. Expired keys are
+// included so their owner can see why a script stopped working.
+func (r *apiKeyRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, key_prefix, permissions, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*model.APIKey
+	for rows.Next() {
+		var k model.APIKey
+		var permissions []string
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.KeyPrefix, &permissions, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt); err != nil {
+			return nil, err
+		}
+		if permissions != nil {
+			k.Permissions = toPermissions(permissions)
+		}
+		keys = append(keys, &k)
+	}
+	return keys, rows.Err()
+}
+
+// Revoke disables one of the user's keys. Unknown, foreign or already revoked keys return ErrNotFound.
+func (r *apiKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetValid looks up a live key by its hash and resolves what it may do now: the owner's current
+// role, narrowed to the key's permissions, and the owner's warehouses. Keys of deleted users are
+// not valid.
+func (r *apiKeyRepository) GetValid(ctx context.Context, keyHash string) (*model.APIKeyIdentity, error) {
+	query := `
+		SELECT k.id, k.user_id, u.role,
+		       COALESCE(ARRAY(
+		           SELECT rp.permission FROM role_permissions rp
+		           WHERE rp.role = u.role AND (k.permissions IS NULL OR rp.permission = ANY(k.permissions))
+		       ), '{}'),
+		       COALESCE(ARRAY(SELECT uw.warehouse_id FROM user_warehouses uw WHERE uw.user_id = k.user_id), '{}')
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id AND u.deleted_at IS NULL
+		WHERE k.key_hash = $1
+		  AND k.revoked_at IS NULL
+		  AND (k.expires_at IS NULL OR k.expires_at > NOW())
+	`
+
+	identity := &model.APIKeyIdentity{}
+	var permissions []string
+	err := r.db.QueryRow(ctx, query, keyHash).Scan(&identity.KeyID, &identity.UserID, &identity.Role, &permissions, &identity.WarehouseIDs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	identity.Permissions = toPermissions(permissions)
+	return identity, nil
+}
+
+// Touch records that a key was used. Writes are throttled to one per minute per key.
+func (r *apiKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// fromPermissions converts permissions for a TEXT[] column, keeping nil as NULL.
+func fromPermissions(permissions model.Permissions) []string {
+	if permissions == nil {
+		return nil
+	}
+	raw := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		raw = append(raw, string(p))
+	}
+	return raw
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository adalah "Stuntman" untuk APIKeyRepository asli kita
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetValid(ctx context.Context, keyHash string) (*model.APIKeyIdentity, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.APIKeyIdentity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// auditChainLockKey serialises appends so each event links to the one committed before it.
+// It differs from the migrator's advisory lock key.
+const auditChainLockKey = 7_452_091_384
+
+// AuditFilter narrows audit listings. Nil/empty values mean "no filter". From is inclusive, To is exclusive.
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	Action     model.AuditAction
+	EntityType model.AuditEntity
+	EntityID   *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditRepository interface {
+	Append(ctx context.Context, event *model.AuditEvent) error
+	Count(ctx context.Context, filter AuditFilter) (int64, error)
+	FindAll(ctx context.Context, limit, offset int, filter AuditFilter) ([]*model.AuditEvent, error)
+	FindAfter(ctx context.Context, seq int64, limit int) ([]*model.AuditEvent, error)
+}
+
+type auditRepository struct {
+	db PgxIface
+}
+
+func NewAuditRepository(db PgxIface) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+const auditSelect = `
+	SELECT seq, id, actor_id, api_key_id, action, entity_type, entity_id, changes, request_id, ip_address,
+	       created_at, prev_hash, hash
+	FROM audit_events
+`
+
+func scanAuditEvent(row pgx.Row) (*model.AuditEvent, error) {
+	var e model.AuditEvent
+	var changes []byte
+	err := row.Scan(
+		&e.Seq,
+		&e.ID,
+		&e.ActorID,
+		&e.APIKeyID,
+		&e.Action,
+		&e.EntityType,
+		&e.EntityID,
+		&changes,
+		&e.RequestID,
+		&e.IPAddress,
+		&e.CreatedAt,
+		&e.PrevHash,
+		&e.Hash,
+	)
+	if err != nil {
+		return nil, err
+	}
+	e.Changes = changes
+	return &e, nil
+}
+
+// Append links event to the latest event and stores it: PrevHash, Hash and Seq are filled in here.
+// Appends take turns on an advisory lock, so concurrent events still form a single chain.
+func (r *auditRepository) Append(ctx context.Context, event *model.AuditEvent) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockKey); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `SELECT hash FROM audit_events ORDER BY seq DESC LIMIT 1`).Scan(&event.PrevHash)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		event.PrevHash = ""
+	}
+	event.Hash = event.ComputeHash()
+
+	query := `
+		INSERT INTO audit_events (id, actor_id, api_key_id, action, entity_type, entity_id, changes, request_id,
+		                          ip_address, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING seq
+	`
+	err = tx.QueryRow(ctx, query,
+		event.ID,
+		event.ActorID,
+		event.APIKeyID,
+		event.Action,
+		event.EntityType,
+		event.EntityID,
+		string(event.Changes),
+		event.RequestID,
+		event.IPAddress,
+		event.CreatedAt,
+		event.PrevHash,
+		event.Hash,
+	).Scan(&event.Seq)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *auditRepository) Count(ctx context.Context, filter AuditFilter) (int64, error) {
+	where, args := buildAuditWhere(filter)
+
+	var total int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(seq) FROM audit_events WHERE `+where, args...).Scan(&total)
+	return total, err
+}
+
+// FindAll returns matching events, newest first.
+func (r *auditRepository) FindAll(ctx context.Context, limit, offset int, filter AuditFilter) ([]*model.AuditEvent, error) {
+	where, args := buildAuditWhere(filter)
+
+	args = append(args, limit, offset)
+	query := auditSelect + `WHERE ` + where + fmt.Sprintf(`
+		ORDER BY seq DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	return r.query(ctx, query, args...)
+}
+
+// FindAfter returns up to limit events following seq in chain order, for verifying the chain in batches.
+func (r *auditRepository) FindAfter(ctx context.Context, seq int64, limit int) ([]*model.AuditEvent, error) {
+	return r.query(ctx, auditSelect+`WHERE seq > $1 ORDER BY seq LIMIT $2`, seq, limit)
+}
+
+func (r *auditRepository) query(ctx context.Context, query string, args ...any) ([]*model.AuditEvent, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*model.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// buildAuditWhere turns an AuditFilter into a WHERE clause and its positional arguments.
+func buildAuditWhere(filter AuditFilter) (string, []any) {
+	conditions := []string{"TRUE"}
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.ActorID != nil {
+		add(`actor_id = ?`, *filter.ActorID)
+	}
+	if filter.Action != "" {
+		add(`action = ?`, filter.Action)
+	}
+	if filter.EntityType != "" {
+		add(`entity_type = ?`, filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		add(`entity_id = ?`, *filter.EntityID)
+	}
+	if filter.From != nil {
+		add(`created_at >= ?`, *filter.From)
+	}
+	if filter.To != nil {
+		add(`created_at < ?`, *filter.To)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
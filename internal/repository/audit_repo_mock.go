@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository adalah "Stuntman" untuk AuditRepository asli kita
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, event *model.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) Count(ctx context.Context, filter AuditFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditRepository) FindAll(ctx context.Context, limit, offset int, filter AuditFilter) ([]*model.AuditEvent, error) {
+	args := m.Called(ctx, limit, offset, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AuditEvent), args.Error(1)
+}
+
+func (m *MockAuditRepository) FindAfter(ctx context.Context, seq int64, limit int) ([]*model.AuditEvent, error) {
+	args := m.Called(ctx, seq, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AuditEvent), args.Error(1)
+}
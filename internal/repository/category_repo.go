@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// categoryHierarchyLockKey serialises reparenting, so two moves cannot each pass the cycle check
+// and together close a loop. It differs from the other advisory lock keys.
+const categoryHierarchyLockKey = 7_538_610_247
+
+// CategoryRepository defines the contract for category database operations.
+type CategoryRepository interface {
+	Create(ctx context.Context, category *model.Category) error
+	Count(ctx context.Context, search string) (int64, error)
+	FindPage(ctx context.Context, limit, offset int, search string) ([]*model.Category, error)
+	FindAll(ctx context.Context) ([]*model.Category, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Category, error)
+	FindAncestorIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	LockHierarchy(ctx context.Context) error
+	CountItemsByCategory(ctx context.Context) (map[uuid.UUID]int64, error)
+	Update(ctx context.Context, category *model.Category) error
+	Delete(ctx context.Context, id uuid.UUID, version int, strategy model.CategoryItemStrategy) error
+}
+
+type categoryRepository struct {
+	db PgxIface
+}
+
+// NewCategoryRepository creates and returns a new CategoryRepository instance.
+func NewCategoryRepository(db PgxIface) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+const categoryColumns = `id, name, COALESCE(description, ''), parent_id, version, created_at, updated_at`
+
+func scanCategory(row pgx.Row) (*model.Category, error) {
+	var c model.Category
+	err := row.Scan(&c.ID, &c.Name, &c.Description, &c.ParentID, &c.Version, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *model.Category) error {
+	query := `
+		INSERT INTO categories (id, name, description, parent_id)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		RETURNING version, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		category.ID,
+		category.Name,
+		category.Description,
+		category.ParentID,
+	).Scan(&category.Version, &category.CreatedAt, &category.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *categoryRepository) Count(ctx context.Context, search string) (int64, error) {
+	query := `SELECT COUNT(id) FROM categories WHERE deleted_at IS NULL AND name ILIKE '%' || $1 || '%'`
+	var total int64
+	err := r.db.QueryRow(ctx, query, search).Scan(&total)
+	return total, err
+}
+
+// FindPage returns one page of active categories as a flat list.
+func (r *categoryRepository) FindPage(ctx context.Context, limit, offset int, search string) ([]*model.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories
+		WHERE deleted_at IS NULL AND name ILIKE '%' || $1 || '%'
+		ORDER BY name ASC
+		LIMIT $2 OFFSET $3
+	`
+	return r.queryCategories(ctx, query, search, limit, offset)
+}
+
+// FindAll returns every active category; used to build the category tree.
+func (r *categoryRepository) FindAll(ctx context.Context) ([]*model.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories
+		WHERE deleted_at IS NULL
+		ORDER BY name ASC
+	`
+	return r.queryCategories(ctx, query)
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1 AND deleted_at IS NULL`
+	category, err := scanCategory(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return category, err
+}
+
+// FindAncestorIDs walks up the parent chain starting at id (inclusive).
+// UNION (not UNION ALL) guarantees termination even if a cycle already exists.
+func (r *categoryRepository) FindAncestorIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM categories WHERE id = $1
+			UNION
+			SELECT c.id, c.parent_id
+			FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT id FROM ancestors
+	`
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var ancestorID uuid.UUID
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, ancestorID)
+	}
+	return ids, rows.Err()
+}
+
+// LockHierarchy makes other reparents wait until the running transaction ends. Outside a
+// transaction it has no effect.
+func (r *categoryRepository) LockHierarchy(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, categoryHierarchyLockKey)
+	return err
+}
+
+// CountItemsByCategory returns the number of active items directly assigned to each category.
+func (r *categoryRepository) CountItemsByCategory(ctx context.Context) (map[uuid.UUID]int64, error) {
+	query := `
+		SELECT category_id, COUNT(id)
+		FROM items
+		WHERE deleted_at IS NULL AND category_id IS NOT NULL
+		GROUP BY category_id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int64)
+	for rows.Next() {
+		var categoryID uuid.UUID
+		var count int64
+		if err := rows.Scan(&categoryID, &count); err != nil {
+			return nil, err
+		}
+		counts[categoryID] = count
+	}
+	return counts, rows.Err()
+}
+
+// Update saves an active category that is still at category.Version, and bumps the version.
+func (r *categoryRepository) Update(ctx context.Context, category *model.Category) error {
+	query := `
+		UPDATE categories
+		SET name = $1, description = NULLIF($2, ''), parent_id = $3, version = version + 1, updated_at = NOW()
+		WHERE id = $4 AND deleted_at IS NULL AND version = $5
+		RETURNING version, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		category.Name,
+		category.Description,
+		category.ParentID,
+		category.ID,
+		category.Version,
+	).Scan(&category.Version, &category.UpdatedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return staleOrMissing(ctx, r.db, "categories", category.ID)
+	case isUniqueViolation(err):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// Delete soft-deletes a category in one transaction. Its child categories move up to
+// its parent, and its items are either moved to the parent or left uncategorised; every row
+// touched gets a new version. The category must still be at version. ErrDuplicate means a
+// child has the same name as one of the parent's categories, so it cannot move up.
+func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID, version int, strategy model.CategoryItemStrategy) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the category so concurrent edits can't reparent into it mid-delete.
+	var parentID *uuid.UUID
+	var current int
+	err = tx.QueryRow(ctx, `SELECT parent_id, version FROM categories WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&parentID, &current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if current != version {
+		return ErrVersionConflict
+	}
+
+	// 2. Re-home the items.
+	var itemTarget *uuid.UUID
+	if strategy == model.CategoryItemsMoveToParent {
+		itemTarget = parentID
+	}
+	if _, err := tx.Exec(ctx, `UPDATE items SET category_id = $1, version = version + 1, updated_at = NOW() WHERE category_id = $2`, itemTarget, id); err != nil {
+		return err
+	}
+
+	// 3. Soft delete the category itself first, so a child named like it can take its place.
+	if _, err := tx.Exec(ctx, `UPDATE categories SET deleted_at = NOW(), version = version + 1 WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	// 4. Children move up one level so the tree stays connected.
+	if _, err := tx.Exec(ctx, `UPDATE categories SET parent_id = $1, version = version + 1, updated_at = NOW() WHERE parent_id = $2`, parentID, id); err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *categoryRepository) queryCategories(ctx context.Context, query string, args ...any) ([]*model.Category, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*model.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryRepository adalah "Stuntman" untuk CategoryRepository asli kita
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *model.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Count(ctx context.Context, search string) (int64, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindPage(ctx context.Context, limit, offset int, search string) ([]*model.Category, error) {
+	args := m.Called(ctx, limit, offset, search)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindAll(ctx context.Context) ([]*model.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindAncestorIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).([]uuid.UUID), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryRepository) LockHierarchy(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) CountItemsByCategory(ctx context.Context) (map[uuid.UUID]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[uuid.UUID]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *model.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id uuid.UUID, version int, strategy model.CategoryItemStrategy) error {
+	args := m.Called(ctx, id, version, strategy)
+	return args.Error(0)
+}
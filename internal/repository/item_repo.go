@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// ItemSortColumns whitelists the columns items can be sorted by.
+var ItemSortColumns = map[string]string{
+	"name":  "i.name",
+	"sku":   "i.sku",
+	"price": "i.price",
+	"stock": "i.stock",
+}
+
+// ItemFilter narrows item listings. Nil pointers mean "no filter".
+// CategoryID matches the category and all of its descendants.
+type ItemFilter struct {
+	Search      string
+	CategoryID  *uuid.UUID
+	ShelfID     *uuid.UUID
+	WarehouseID *uuid.UUID
+	MinPrice    *decimal.Decimal
+	MaxPrice    *decimal.Decimal
+	MinStock    *int
+	MaxStock    *int
+	SortBy      string // key of ItemSortColumns, defaults to name
+	SortDesc    bool
+}
+
+// ItemRepository defines the contract for item database operations.
+type ItemRepository interface {
+	Create(ctx context.Context, item *model.Item) error
+	Count(ctx context.Context, filter ItemFilter) (int64, error)
+	FindAll(ctx context.Context, limit, offset int, filter ItemFilter) ([]*model.Item, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Item, error)
+	FindBySKU(ctx context.Context, sku string) (*model.Item, error)
+	Update(ctx context.Context, item *model.Item) error
+	SoftDelete(ctx context.Context, id uuid.UUID, version int) error
+}
+
+type itemRepository struct {
+	db PgxIface
+}
+
+// NewItemRepository creates and returns a new ItemRepository instance.
+func NewItemRepository(db PgxIface) ItemRepository {
+	return &itemRepository{db: db}
+}
+
+// itemSelect joins the display names of the item's category, shelf and warehouse.
+const itemSelect = `
+	SELECT i.id, i.sku, i.name, i.category_id, i.shelf_id, i.stock, i.price, i.version, i.created_at, i.updated_at,
+	       COALESCE(c.name, ''), COALESCE(s.name, ''), s.warehouse_id, COALESCE(w.name, '')
+	FROM items i
+	LEFT JOIN categories c ON c.id = i.category_id AND c.deleted_at IS NULL
+	LEFT JOIN shelves s ON s.id = i.shelf_id AND s.deleted_at IS NULL
+	LEFT JOIN warehouses w ON w.id = s.warehouse_id AND w.deleted_at IS NULL
+`
+
+func scanItem(row pgx.Row) (*model.Item, error) {
+	var i model.Item
+	err := row.Scan(
+		&i.ID, &i.SKU, &i.Name, &i.CategoryID, &i.ShelfID, &i.Stock, &i.Price, &i.Version, &i.CreatedAt, &i.UpdatedAt,
+		&i.CategoryName, &i.ShelfName, &i.WarehouseID, &i.WarehouseName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Create inserts a new item. Stock always starts at zero; it only moves through the ledger.
+func (r *itemRepository) Create(ctx context.Context, item *model.Item) error {
+	query := `
+		INSERT INTO items (id, sku, name, category_id, shelf_id, stock, price)
+		VALUES ($1, $2, $3, $4, $5, 0, $6)
+		RETURNING stock, version, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		item.ID,
+		item.SKU,
+		item.Name,
+		item.CategoryID,
+		item.ShelfID,
+		item.Price,
+	).Scan(&item.Stock, &item.Version, &item.CreatedAt, &item.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *itemRepository) Count(ctx context.Context, filter ItemFilter) (int64, error) {
+	where, args := buildItemWhere(ctx, filter)
+	query := `
+		SELECT COUNT(i.id)
+		FROM items i
+		LEFT JOIN shelves s ON s.id = i.shelf_id AND s.deleted_at IS NULL
+		WHERE ` + where
+
+	var total int64
+	err := r.db.QueryRow(ctx, query, args...).Scan(&total)
+	return total, err
+}
+
+func (r *itemRepository) FindAll(ctx context.Context, limit, offset int, filter ItemFilter) ([]*model.Item, error) {
+	where, args := buildItemWhere(ctx, filter)
+
+	sortColumn, ok := ItemSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = ItemSortColumns["name"]
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	args = append(args, limit, offset)
+	query := itemSelect + `WHERE ` + where + fmt.Sprintf(`
+		ORDER BY %s %s, i.id ASC
+		LIMIT $%d OFFSET $%d
+	`, sortColumn, direction, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// itemInScope limits itemSelect to items on a shelf in the caller's warehouses; $2 is scopedWarehouseIDs.
+const itemInScope = ` AND ($2::uuid[] IS NULL OR s.warehouse_id = ANY($2))`
+
+// FindByID retrieves an active item by its UUID.
+func (r *itemRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Item, error) {
+	item, err := scanItem(r.db.QueryRow(ctx, itemSelect+`WHERE i.id = $1 AND i.deleted_at IS NULL`+itemInScope, id, scopedWarehouseIDs(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return item, err
+}
+
+// FindBySKU retrieves an active item by its SKU (case-insensitive).
+func (r *itemRepository) FindBySKU(ctx context.Context, sku string) (*model.Item, error) {
+	item, err := scanItem(r.db.QueryRow(ctx, itemSelect+`WHERE UPPER(i.sku) = UPPER($1) AND i.deleted_at IS NULL`+itemInScope, sku, scopedWarehouseIDs(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return item, err
+}
+
+// Update modifies an item's catalogue data, provided the item is still at item.Version. Stock is
+// deliberately not part of this statement. Stock movements bump the version too, because the
+// version is the item's ETag and the stock is part of what GET returns.
+func (r *itemRepository) Update(ctx context.Context, item *model.Item) error {
+	query := `
+		UPDATE items
+		SET sku = $1, name = $2, category_id = $3, shelf_id = $4, price = $5, version = version + 1, updated_at = NOW()
+		WHERE id = $6 AND deleted_at IS NULL AND version = $7
+		RETURNING stock, version, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		item.SKU,
+		item.Name,
+		item.CategoryID,
+		item.ShelfID,
+		item.Price,
+		item.ID,
+		item.Version,
+	).Scan(&item.Stock, &item.Version, &item.UpdatedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return staleOrMissing(ctx, r.db, "items", item.ID)
+	case isUniqueViolation(err):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// SoftDelete marks an item as deleted by setting deleted_at, provided it is still at version.
+func (r *itemRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	query := `
+		UPDATE items SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND version = $2
+	`
+	tag, err := r.db.Exec(ctx, query, id, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return staleOrMissing(ctx, r.db, "items", id)
+	}
+	return nil
+}
+
+// buildItemWhere turns an ItemFilter into a WHERE clause (aliases: i = items, s = shelves)
+// and its positional arguments. Items outside the caller's warehouses are always left out.
+func buildItemWhere(ctx context.Context, filter ItemFilter) (string, []any) {
+	conditions := []string{"i.deleted_at IS NULL"}
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if ids := scopedWarehouseIDs(ctx); ids != nil {
+		add(`s.warehouse_id = ANY(?)`, ids)
+	}
+	if filter.Search != "" {
+		add(`(i.name ILIKE '%' || ? || '%' OR i.sku ILIKE '%' || ? || '%')`, filter.Search)
+	}
+	if filter.CategoryID != nil {
+		add(`i.category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+				UNION
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+			)
+			SELECT id FROM tree
+		)`, *filter.CategoryID)
+	}
+	if filter.ShelfID != nil {
+		add(`i.shelf_id = ?`, *filter.ShelfID)
+	}
+	if filter.WarehouseID != nil {
+		add(`s.warehouse_id = ?`, *filter.WarehouseID)
+	}
+	if filter.MinPrice != nil {
+		add(`i.price >= ?`, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		add(`i.price <= ?`, *filter.MaxPrice)
+	}
+	if filter.MinStock != nil {
+		add(`i.stock >= ?`, *filter.MinStock)
+	}
+	if filter.MaxStock != nil {
+		add(`i.stock <= ?`, *filter.MaxStock)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
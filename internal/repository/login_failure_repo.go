@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LoginFailureRepository tracks failed logins for emails without an account. Its lockout state
+// comes back as a *model.User carrying only the email and the lockout fields, so the login flow
+// treats unknown emails the same way as real accounts.
+type LoginFailureRepository interface {
+	Find(ctx context.Context, email string) (*model.User, error)
+	Record(ctx context.Context, email string, maxAttempts int, lockout time.Duration) (*model.User, error)
+}
+
+type loginFailureRepository struct {
+	db PgxIface
+}
+
+func NewLoginFailureRepository(db PgxIface) LoginFailureRepository {
+	return &loginFailureRepository{db: db}
+}
+
+// Find returns the lockout state of an email. An email that never failed has a clean state.
+func (r *loginFailureRepository) Find(ctx context.Context, email string) (*model.User, error) {
+	query := `
+		SELECT failed_login_attempts, last_failed_login_at, locked_until
+		FROM login_failures
+		WHERE email = LOWER($1)
+	`
+	user := &model.User{Email: email}
+	err := r.db.QueryRow(ctx, query, email).Scan(
+		&user.FailedLoginAttempts,
+		&user.LastFailedLoginAt,
+		&user.LockedUntil,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Record counts a failed login for email with the same rules as UserRepository.RecordFailedLogin
+// and returns the updated lockout state.
+func (r *loginFailureRepository) Record(ctx context.Context, email string, maxAttempts int, lockout time.Duration) (*model.User, error) {
+	query := `
+		INSERT INTO login_failures (email, failed_login_attempts, last_failed_login_at, locked_until)
+		VALUES (LOWER($1), 1, NOW(), CASE WHEN 1 >= $2 THEN NOW() + make_interval(secs => $3) END)
+		ON CONFLICT (email) DO UPDATE
+		SET failed_login_attempts = login_failures.failed_login_attempts + 1,
+		    last_failed_login_at = NOW(),
+		    locked_until = CASE
+		        WHEN login_failures.failed_login_attempts + 1 >= $2 THEN NOW() + make_interval(secs => $3)
+		        ELSE login_failures.locked_until
+		    END
+		RETURNING failed_login_attempts, last_failed_login_at, locked_until
+	`
+	user := &model.User{Email: email}
+	err := r.db.QueryRow(ctx, query, email, maxAttempts, lockout.Seconds()).Scan(
+		&user.FailedLoginAttempts,
+		&user.LastFailedLoginAt,
+		&user.LockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
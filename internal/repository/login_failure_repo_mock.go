@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLoginFailureRepository adalah "Stuntman" untuk LoginFailureRepository asli kita
+type MockLoginFailureRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginFailureRepository) Find(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockLoginFailureRepository) Record(ctx context.Context, email string, maxAttempts int, lockout time.Duration) (*model.User, error) {
+	args := m.Called(ctx, email, maxAttempts, lockout)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
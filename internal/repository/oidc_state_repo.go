@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type OIDCStateRepository interface {
+	Create(ctx context.Context, state *model.OIDCLoginState) error
+	Consume(ctx context.Context, stateHash string) (*model.OIDCLoginState, error)
+}
+
+type oidcStateRepository struct {
+	db PgxIface
+}
+
+func NewOIDCStateRepository(db PgxIface) OIDCStateRepository {
+	return &oidcStateRepository{db: db}
+}
+
+// Create stores a pending login and clears out expired ones, which nobody else removes.
+func (r *oidcStateRepository) Create(ctx context.Context, state *model.OIDCLoginState) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM oidc_login_states WHERE expires_at <= NOW()`); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO oidc_login_states (id, state_hash, browser_hash, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+	return r.db.QueryRow(ctx, query, state.ID, state.StateHash, state.BrowserHash, state.Nonce, state.CodeVerifier, state.ExpiresAt).
+		Scan(&state.CreatedAt)
+}
+
+// Consume deletes and returns the pending login with the given state hash, so it works once.
+// Unknown and expired states return ErrNotFound.
+func (r *oidcStateRepository) Consume(ctx context.Context, stateHash string) (*model.OIDCLoginState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > NOW()
+		RETURNING id, state_hash, browser_hash, nonce, code_verifier, expires_at, created_at
+	`
+
+	var state model.OIDCLoginState
+	err := r.db.QueryRow(ctx, query, stateHash).Scan(
+		&state.ID,
+		&state.StateHash,
+		&state.BrowserHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+		&state.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &state, nil
+}
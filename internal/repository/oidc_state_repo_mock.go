@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOIDCStateRepository adalah "Stuntman" untuk OIDCStateRepository asli kita
+type MockOIDCStateRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCStateRepository) Create(ctx context.Context, state *model.OIDCLoginState) error {
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
+func (m *MockOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*model.OIDCLoginState, error) {
+	args := m.Called(ctx, stateHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OIDCLoginState), args.Error(1)
+}
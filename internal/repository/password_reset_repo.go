@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrResetRecentlyIssued is returned by Create when the user already got a reset token within the cooldown.
+var ErrResetRecentlyIssued = errors.New("password reset token issued recently")
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *model.PasswordResetToken, cooldown time.Duration) error
+	Reset(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error)
+}
+
+type passwordResetRepository struct {
+	db PgxIface
+}
+
+func NewPasswordResetRepository(db PgxIface) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a new reset token. Older unused tokens of the same user stop working,
+// so only the most recent email can be used. If the user got a token less than cooldown ago,
+// nothing is stored and ErrResetRecentlyIssued is returned.
+func (r *passwordResetRepository) Create(ctx context.Context, token *model.PasswordResetToken, cooldown time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the user so two simultaneous requests can't both pass the cooldown check.
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, token.UserID); err != nil {
+		return err
+	}
+	var recent bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM password_reset_tokens
+			WHERE user_id = $1 AND created_at > NOW() - make_interval(secs => $2)
+		)
+	`, token.UserID, cooldown.Seconds()).Scan(&recent)
+	if err != nil {
+		return err
+	}
+	if recent {
+		return ErrResetRecentlyIssued
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, token.UserID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+	if err := tx.QueryRow(ctx, query, token.ID, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&token.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Reset uses the token with the given hash to set a new password, in one transaction: the token is
+// marked used, the failed login counter is cleared and every session of the user is revoked.
+// Unknown, used or expired tokens, and tokens of deleted users, return ErrNotFound.
+// It returns the ID of the user whose password was reset.
+func (r *passwordResetRepository) Reset(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var tokenID, userID uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT id, user_id
+		FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE
+	`, tokenHash).Scan(&tokenID, &userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	query := `
+		UPDATE users
+		SET password_hash = $2, failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, userID, passwordHash)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return uuid.Nil, ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = NOW() WHERE id = $1`, tokenID); err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := revokeUserTokens(ctx, tx, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit(ctx)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordResetRepository adalah "Stuntman" untuk PasswordResetRepository asli kita
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, token *model.PasswordResetToken, cooldown time.Duration) error {
+	args := m.Called(ctx, token, cooldown)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) Reset(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	args := m.Called(ctx, tokenHash, passwordHash)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
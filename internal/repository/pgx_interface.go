@@ -18,3 +18,10 @@ type PgxIface interface {
 	Ping(ctx context.Context) error
 	Close()
 }
+
+// rowQuerier is the read side shared by the pool (PgxIface) and a transaction (pgx.Tx),
+// so the same lookup helper can run inside or outside a transaction.
+type rowQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
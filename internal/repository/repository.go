@@ -1,13 +1,106 @@
 package repository
 
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrNotFound is returned by repositories when the requested row does not exist
+	// (or is soft-deleted and therefore hidden).
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when an insert or update violates a unique constraint.
+	ErrDuplicate = errors.New("record already exists")
+	// ErrVersionConflict is returned when a row was edited by someone else after the caller read it,
+	// so its version no longer matches the one the write was made against.
+	ErrVersionConflict = errors.New("record was modified concurrently")
+)
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation (23505).
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign_key_violation (23503).
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// staleOrMissing explains why a versioned write to table matched no row: the row is still there
+// (ErrVersionConflict) or it is gone (ErrNotFound).
+func staleOrMissing(ctx context.Context, db rowQuerier, table string, id uuid.UUID) error {
+	var exists bool
+	err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrVersionConflict
+	}
+	return ErrNotFound
+}
+
+// scopedWarehouseIDs returns the warehouses the request in ctx is limited to, or nil when it may
+// touch every warehouse. Queries test it with "($n::uuid[] IS NULL OR warehouse_id = ANY($n))";
+// a user without any assignment gets an empty slice and matches nothing.
+func scopedWarehouseIDs(ctx context.Context) []uuid.UUID {
+	scope := model.WarehouseScopeFrom(ctx)
+	if scope.All {
+		return nil
+	}
+	if scope.IDs == nil {
+		return []uuid.UUID{}
+	}
+	return scope.IDs
+}
+
 type Repository struct {
-	User    UserRepository
-	Session SessionRepository
+	User          UserRepository
+	Session       SessionRepository
+	LoginFailure  LoginFailureRepository
+	PasswordReset PasswordResetRepository
+	TwoFactor     TwoFactorRepository
+	APIKey        APIKeyRepository
+	OIDCState     OIDCStateRepository
+	Role          RoleRepository
+	Warehouse     WarehouseRepository
+	UserWarehouse UserWarehouseRepository
+	Shelf         ShelfRepository
+	Category      CategoryRepository
+	Item          ItemRepository
+	Stock         StockRepository
+	Sale          SaleRepository
+	Audit         AuditRepository
+	Tx            TxManager
 }
 
 func NewRepository(db PgxIface) *Repository {
+	// Every repository joins the transaction of a TxManager.WithinTx call through the context.
+	db = txAwareDB{db}
 	return &Repository{
-		User:    NewUserRepository(db),
-		Session: NewSessionRepository(db),
+		User:          NewUserRepository(db),
+		Session:       NewSessionRepository(db),
+		LoginFailure:  NewLoginFailureRepository(db),
+		PasswordReset: NewPasswordResetRepository(db),
+		TwoFactor:     NewTwoFactorRepository(db),
+		APIKey:        NewAPIKeyRepository(db),
+		OIDCState:     NewOIDCStateRepository(db),
+		Role:          NewRoleRepository(db),
+		Warehouse:     NewWarehouseRepository(db),
+		UserWarehouse: NewUserWarehouseRepository(db),
+		Shelf:         NewShelfRepository(db),
+		Category:      NewCategoryRepository(db),
+		Item:          NewItemRepository(db),
+		Stock:         NewStockRepository(db),
+		Sale:          NewSaleRepository(db),
+		Audit:         NewAuditRepository(db),
+		Tx:            NewTxManager(db),
 	}
 }
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrRoleInUse is returned when deleting a role that users still hold.
+var ErrRoleInUse = errors.New("role is assigned to users")
+
+type RoleRepository interface {
+	FindAll(ctx context.Context) ([]*model.Role, error)
+	FindByName(ctx context.Context, name model.UserRole) (*model.Role, error)
+	Create(ctx context.Context, role *model.Role) error
+	Update(ctx context.Context, role *model.Role) error
+	Delete(ctx context.Context, name model.UserRole) error
+}
+
+type roleRepository struct {
+	db PgxIface
+}
+
+func NewRoleRepository(db PgxIface) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// roleColumns selects a role with its permissions aggregated into one array.
+const roleColumns = `
+	r.name, r.description, r.is_system, r.created_at, r.updated_at,
+	COALESCE(ARRAY(SELECT rp.permission FROM role_permissions rp WHERE rp.role = r.name ORDER BY rp.permission), '{}')
+`
+
+func scanRole(row pgx.Row) (*model.Role, error) {
+	role := &model.Role{}
+	var permissions []string
+	if err := row.Scan(&role.Name, &role.Description, &role.IsSystem, &role.CreatedAt, &role.UpdatedAt, &permissions); err != nil {
+		return nil, err
+	}
+	role.Permissions = toPermissions(permissions)
+	return role, nil
+}
+
+func toPermissions(raw []string) model.Permissions {
+	permissions := make(model.Permissions, 0, len(raw))
+	for _, p := range raw {
+		permissions = append(permissions, model.Permission(p))
+	}
+	return permissions
+}
+
+// FindAll returns every role, system roles first.
+func (r *roleRepository) FindAll(ctx context.Context) ([]*model.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles r ORDER BY r.is_system DESC, r.name`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*model.Role
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name model.UserRole) (*model.Role, error) {
+	query := `SELECT ` + roleColumns + ` FROM roles r WHERE r.name = $1`
+	role, err := scanRole(r.db.QueryRow(ctx, query, name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// Create inserts a custom role with its permissions. ErrDuplicate means the name is taken.
+func (r *roleRepository) Create(ctx context.Context, role *model.Role) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO roles (name, description, is_system)
+		VALUES ($1, $2, FALSE)
+		RETURNING created_at, updated_at
+	`
+	if err := tx.QueryRow(ctx, query, role.Name, role.Description).Scan(&role.CreatedAt, &role.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+
+	if err := replacePermissions(ctx, tx, role); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Update changes a role's description and replaces its permissions.
+func (r *roleRepository) Update(ctx context.Context, role *model.Role) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE roles SET description = $2, updated_at = NOW()
+		WHERE name = $1
+		RETURNING is_system, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, role.Name, role.Description).Scan(&role.IsSystem, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM role_permissions WHERE role = $1`, role.Name); err != nil {
+		return err
+	}
+	if err := replacePermissions(ctx, tx, role); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Delete removes a custom role. System roles are never deleted and report ErrNotFound;
+// a role still held by a user, even a deleted one, returns ErrRoleInUse.
+func (r *roleRepository) Delete(ctx context.Context, name model.UserRole) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM roles WHERE name = $1 AND NOT is_system`, name)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrRoleInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func replacePermissions(ctx context.Context, tx pgx.Tx, role *model.Role) error {
+	for _, p := range role.Permissions {
+		if _, err := tx.Exec(ctx, `INSERT INTO role_permissions (role, permission) VALUES ($1, $2)`, role.Name, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRoleRepository adalah "Stuntman" untuk RoleRepository asli kita
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]*model.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name model.UserRole) (*model.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRoleRepository) Create(ctx context.Context, role *model.Role) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Update(ctx context.Context, role *model.Role) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Delete(ctx context.Context, name model.UserRole) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SaleFilter narrows sale listings. Nil pointers mean "no filter". From is inclusive, To is exclusive.
+type SaleFilter struct {
+	UserID *uuid.UUID
+	From   *time.Time
+	To     *time.Time
+}
+
+// SalePriceFunc receives the cart's items, locked and keyed by ID, and must fill in the unit
+// prices, subtotals and total of sale. Items missing from locked do not exist (or are deleted).
+// Returning an error aborts the checkout and rolls the transaction back.
+type SalePriceFunc func(sale *model.Sale, locked map[uuid.UUID]*model.Item) error
+
+// SaleCheckFunc inspects a sale (with its lines) locked inside a void or return transaction.
+// Returning an error aborts the operation and rolls the transaction back.
+type SaleCheckFunc func(sale *model.Sale) error
+
+// SaleRepository defines the contract for sale database operations.
+type SaleRepository interface {
+	Checkout(ctx context.Context, sale *model.Sale, price SalePriceFunc) error
+	Void(ctx context.Context, saleID, userID uuid.UUID, reason string, check SaleCheckFunc) error
+	CreateReturn(ctx context.Context, ret *model.SaleReturn, build SaleCheckFunc) error
+	Count(ctx context.Context, filter SaleFilter) (int64, error)
+	FindAll(ctx context.Context, limit, offset int, filter SaleFilter) ([]*model.Sale, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Sale, error)
+}
+
+type saleRepository struct {
+	db PgxIface
+}
+
+// NewSaleRepository creates and returns a new SaleRepository instance.
+func NewSaleRepository(db PgxIface) SaleRepository {
+	return &saleRepository{db: db}
+}
+
+// Checkout locks the cart's items, prices the sale through price, then writes the sale, its lines,
+// the decremented stock and one OUT ledger row per line, all in one transaction.
+func (r *saleRepository) Checkout(ctx context.Context, sale *model.Sale, price SalePriceFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock every item in the cart. Rows are locked in id order so two checkouts
+	//    sharing items always queue up instead of deadlocking. Items outside the caller's
+	//    warehouses are left out and therefore reported as missing.
+	itemIDs := make([]uuid.UUID, 0, len(sale.Items))
+	for _, line := range sale.Items {
+		itemIDs = append(itemIDs, line.ItemID)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT i.id, i.sku, i.name, i.stock, i.price
+		FROM items i
+		LEFT JOIN shelves s ON s.id = i.shelf_id AND s.deleted_at IS NULL
+		WHERE i.id = ANY($1) AND i.deleted_at IS NULL
+		  AND ($2::uuid[] IS NULL OR s.warehouse_id = ANY($2))
+		ORDER BY i.id
+		FOR UPDATE OF i
+	`, itemIDs, scopedWarehouseIDs(ctx))
+	if err != nil {
+		return err
+	}
+	locked := make(map[uuid.UUID]*model.Item, len(itemIDs))
+	for rows.Next() {
+		var i model.Item
+		if err := rows.Scan(&i.ID, &i.SKU, &i.Name, &i.Stock, &i.Price); err != nil {
+			rows.Close()
+			return err
+		}
+		locked[i.ID] = &i
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// 2. Price the sale against the locked rows.
+	if err := price(sale, locked); err != nil {
+		return err
+	}
+
+	// 3. Write the sale header.
+	err = tx.QueryRow(ctx,
+		`INSERT INTO sales (id, user_id, total_amount) VALUES ($1, $2, $3) RETURNING status, created_at`,
+		sale.ID, sale.UserID, sale.TotalAmount,
+	).Scan(&sale.Status, &sale.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// 4. Write each line, take it off the shelf and record it in the ledger.
+	for i := range sale.Items {
+		line := &sale.Items[i]
+		item := locked[line.ItemID]
+
+		line.SaleID = sale.ID
+		err = tx.QueryRow(ctx, `
+			INSERT INTO sale_items (id, sale_id, item_id, quantity, unit_price, subtotal)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at
+		`, line.ID, line.SaleID, line.ItemID, line.Quantity, line.UnitPrice, line.Subtotal).Scan(&line.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		item.Stock -= line.Quantity
+		if _, err := tx.Exec(ctx, `UPDATE items SET stock = $1, version = version + 1, updated_at = NOW() WHERE id = $2`, item.Stock, item.ID); err != nil {
+			return err
+		}
+
+		err = insertStockLog(ctx, tx, &model.StockLog{
+			BaseSimple:   model.BaseSimple{ID: uuid.New()},
+			ItemID:       item.ID,
+			UserID:       sale.UserID,
+			MovementType: model.MovementOut,
+			Quantity:     line.Quantity,
+			BalanceAfter: item.Stock,
+			ReferenceID:  &sale.ID,
+			Description:  "Sale",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *saleRepository) Count(ctx context.Context, filter SaleFilter) (int64, error) {
+	where, args := buildSaleWhere(ctx, filter)
+
+	var total int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(s.id) FROM sales s WHERE `+where, args...).Scan(&total)
+	return total, err
+}
+
+// FindAll returns sale headers (without lines), newest first.
+func (r *saleRepository) FindAll(ctx context.Context, limit, offset int, filter SaleFilter) ([]*model.Sale, error) {
+	where, args := buildSaleWhere(ctx, filter)
+
+	args = append(args, limit, offset)
+	query := saleSelect + `WHERE ` + where + fmt.Sprintf(`
+		ORDER BY s.created_at DESC, s.id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []*model.Sale
+	for rows.Next() {
+		sale, err := scanSale(rows)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, sale)
+	}
+	return sales, rows.Err()
+}
+
+// FindByID retrieves a sale together with its lines.
+func (r *saleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Sale, error) {
+	return findSale(ctx, r.db, id, "")
+}
+
+// Void marks a completed sale as voided and puts every unreturned unit back on stock with an
+// IN ledger row referencing the sale. check runs against the locked sale before anything is written.
+func (r *saleRepository) Void(ctx context.Context, saleID, userID uuid.UUID, reason string, check SaleCheckFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the sale so a concurrent return or second void waits for us.
+	sale, err := findSale(ctx, tx, saleID, "FOR UPDATE OF s")
+	if err != nil {
+		return err
+	}
+	if err := check(sale); err != nil {
+		return err
+	}
+
+	// 2. Restock whatever is still out with the customer.
+	restock := make(map[uuid.UUID]int, len(sale.Items))
+	for _, line := range sale.Items {
+		if outstanding := line.Quantity - line.ReturnedQuantity; outstanding > 0 {
+			restock[line.ItemID] += outstanding
+		}
+	}
+	if err := restockItems(ctx, tx, restock, userID, saleID, "Sale voided"); err != nil {
+		return err
+	}
+
+	// 3. Flag the sale.
+	_, err = tx.Exec(ctx, `
+		UPDATE sales
+		SET status = $1, voided_at = NOW(), voided_by = $2, void_reason = NULLIF($3, '')
+		WHERE id = $4
+	`, model.SaleVoided, userID, reason, saleID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateReturn records goods brought back against a sale. build receives the locked sale and must
+// fill in ret.Items (with ItemID) and ret.RefundAmount; the lines' returned quantities, the stock
+// and one IN ledger row per item are then written in the same transaction.
+func (r *saleRepository) CreateReturn(ctx context.Context, ret *model.SaleReturn, build SaleCheckFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the sale so concurrent returns of the same line are serialised.
+	sale, err := findSale(ctx, tx, ret.SaleID, "FOR UPDATE OF s")
+	if err != nil {
+		return err
+	}
+	if err := build(sale); err != nil {
+		return err
+	}
+
+	// 2. Write the return and bump each line's returned quantity.
+	err = tx.QueryRow(ctx, `
+		INSERT INTO sale_returns (id, sale_id, user_id, reason, refund_amount)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING created_at
+	`, ret.ID, ret.SaleID, ret.UserID, ret.Reason, ret.RefundAmount).Scan(&ret.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	restock := make(map[uuid.UUID]int, len(ret.Items))
+	for i := range ret.Items {
+		line := &ret.Items[i]
+		line.ReturnID = ret.ID
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO sale_return_items (id, return_id, sale_item_id, quantity, refund_amount)
+			VALUES ($1, $2, $3, $4, $5)
+		`, line.ID, line.ReturnID, line.SaleItemID, line.Quantity, line.RefundAmount)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE sale_items SET returned_quantity = returned_quantity + $1 WHERE id = $2`, line.Quantity, line.SaleItemID)
+		if err != nil {
+			return err
+		}
+		restock[line.ItemID] += line.Quantity
+	}
+
+	// 3. Put the goods back on stock.
+	if err := restockItems(ctx, tx, restock, ret.UserID, ret.SaleID, "Customer return"); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// findSale loads a sale header and its lines. lock is appended to the header query
+// (e.g. "FOR UPDATE OF s") when called inside a transaction. Sales outside the caller's
+// warehouses are reported as ErrNotFound.
+func findSale(ctx context.Context, q rowQuerier, id uuid.UUID, lock string) (*model.Sale, error) {
+	where, args := `WHERE s.id = $1`, []any{id}
+	if ids := scopedWarehouseIDs(ctx); ids != nil {
+		args = append(args, ids)
+		where += ` AND ` + strings.ReplaceAll(saleInWarehouses, "?", "$2")
+	}
+
+	sale, err := scanSale(q.QueryRow(ctx, saleSelect+where+` `+lock, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	// Deleted items stay visible here: a receipt must keep showing what was sold.
+	rows, err := q.Query(ctx, `
+		SELECT si.id, si.sale_id, si.item_id, si.quantity, si.returned_quantity, si.unit_price, si.subtotal, si.created_at,
+		       i.sku, i.name
+		FROM sale_items si
+		JOIN items i ON i.id = si.item_id
+		WHERE si.sale_id = $1
+		ORDER BY i.name ASC, si.id ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line model.SaleItem
+		if err := rows.Scan(
+			&line.ID, &line.SaleID, &line.ItemID, &line.Quantity, &line.ReturnedQuantity, &line.UnitPrice, &line.Subtotal, &line.CreatedAt,
+			&line.SKU, &line.Name,
+		); err != nil {
+			return nil, err
+		}
+		sale.Items = append(sale.Items, line)
+	}
+	return sale, rows.Err()
+}
+
+// restockItems adds quantities back to items and appends one IN ledger row per item referencing
+// the sale. Items are locked in id order, like checkout, so the two never deadlock each other.
+// Soft-deleted items are restocked too: the goods are physically back either way.
+func restockItems(ctx context.Context, tx pgx.Tx, quantities map[uuid.UUID]int, userID, saleID uuid.UUID, description string) error {
+	if len(quantities) == 0 {
+		return nil
+	}
+
+	itemIDs := make([]uuid.UUID, 0, len(quantities))
+	for id := range quantities {
+		itemIDs = append(itemIDs, id)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id, stock FROM items WHERE id = ANY($1) ORDER BY id FOR UPDATE`, itemIDs)
+	if err != nil {
+		return err
+	}
+	stock := make(map[uuid.UUID]int, len(itemIDs))
+	var ordered []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		var current int
+		if err := rows.Scan(&id, &current); err != nil {
+			rows.Close()
+			return err
+		}
+		stock[id] = current
+		ordered = append(ordered, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ordered {
+		balance := stock[id] + quantities[id]
+		if _, err := tx.Exec(ctx, `UPDATE items SET stock = $1, version = version + 1, updated_at = NOW() WHERE id = $2`, balance, id); err != nil {
+			return err
+		}
+
+		err := insertStockLog(ctx, tx, &model.StockLog{
+			BaseSimple:   model.BaseSimple{ID: uuid.New()},
+			ItemID:       id,
+			UserID:       userID,
+			MovementType: model.MovementIn,
+			Quantity:     quantities[id],
+			BalanceAfter: balance,
+			ReferenceID:  &saleID,
+			Description:  description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saleSelect joins the cashier's name onto the sale header.
+const saleSelect = `
+	SELECT s.id, s.user_id, s.total_amount, s.status, s.voided_at, s.voided_by, COALESCE(s.void_reason, ''),
+	       s.created_at, COALESCE(u.name, '')
+	FROM sales s
+	LEFT JOIN users u ON u.id = s.user_id
+`
+
+// saleInWarehouses matches sales with at least one line for an item shelved in the warehouses
+// bound to "?" (alias: s = sales).
+const saleInWarehouses = `EXISTS (
+	SELECT 1 FROM sale_items si
+	JOIN items i ON i.id = si.item_id
+	JOIN shelves sh ON sh.id = i.shelf_id
+	WHERE si.sale_id = s.id AND sh.warehouse_id = ANY(?)
+)`
+
+func scanSale(row pgx.Row) (*model.Sale, error) {
+	var s model.Sale
+	if err := row.Scan(
+		&s.ID, &s.UserID, &s.TotalAmount, &s.Status, &s.VoidedAt, &s.VoidedBy, &s.VoidReason,
+		&s.CreatedAt, &s.CashierName,
+	); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// buildSaleWhere turns a SaleFilter into a WHERE clause (alias: s = sales) and its positional arguments.
+// Sales outside the caller's warehouses are always left out.
+func buildSaleWhere(ctx context.Context, filter SaleFilter) (string, []any) {
+	conditions := []string{"TRUE"}
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if ids := scopedWarehouseIDs(ctx); ids != nil {
+		add(saleInWarehouses, ids)
+	}
+	if filter.UserID != nil {
+		add(`s.user_id = ?`, *filter.UserID)
+	}
+	if filter.From != nil {
+		add(`s.created_at >= ?`, *filter.From)
+	}
+	if filter.To != nil {
+		add(`s.created_at < ?`, *filter.To)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSaleRepository adalah "Stuntman" untuk SaleRepository asli kita
+type MockSaleRepository struct {
+	mock.Mock
+}
+
+// Checkout plays the locked rows: the first return value is the map of "locked" items fed to
+// price, just like the real transaction would. A non-nil error is returned before price runs.
+func (m *MockSaleRepository) Checkout(ctx context.Context, sale *model.Sale, price SalePriceFunc) error {
+	args := m.Called(ctx, sale)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+
+	locked, _ := args.Get(0).(map[uuid.UUID]*model.Item)
+	return price(sale, locked)
+}
+
+// Void feeds the first return value (the "locked" sale) to check.
+func (m *MockSaleRepository) Void(ctx context.Context, saleID, userID uuid.UUID, reason string, check SaleCheckFunc) error {
+	args := m.Called(ctx, saleID, userID, reason)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	return check(args.Get(0).(*model.Sale))
+}
+
+// CreateReturn feeds the first return value (the "locked" sale) to build.
+func (m *MockSaleRepository) CreateReturn(ctx context.Context, ret *model.SaleReturn, build SaleCheckFunc) error {
+	args := m.Called(ctx, ret)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	return build(args.Get(0).(*model.Sale))
+}
+
+func (m *MockSaleRepository) Count(ctx context.Context, filter SaleFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSaleRepository) FindAll(ctx context.Context, limit, offset int, filter SaleFilter) ([]*model.Sale, error) {
+	args := m.Called(ctx, limit, offset, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Sale), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSaleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Sale, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Sale), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
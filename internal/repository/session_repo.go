@@ -2,15 +2,30 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"inventory-system/internal/model"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
+// ErrTokenReused is returned by Rotate when an already exchanged refresh token is presented again.
+// By then the whole token family has been revoked.
+var ErrTokenReused = errors.New("refresh token already used")
+
+// RotateFunc receives the locked refresh token being exchanged and returns the session and
+// refresh token that replace it. Returning an error aborts the rotation.
+type RotateFunc func(current *model.RefreshToken) (*model.Session, *model.RefreshToken, error)
+
 type SessionRepository interface {
-	Create(ctx context.Context, session *model.Session) error
-	Revoke(ctx context.Context, sessionID uuid.UUID) error
-	GetValid(ctx context.Context, sessionID uuid.UUID) (*model.Session, error)
+	Issue(ctx context.Context, login *model.LoginSession, session *model.Session, refresh *model.RefreshToken) error
+	Rotate(ctx context.Context, refreshHash string, client model.ClientInfo, rotate RotateFunc) error
+	Revoke(ctx context.Context, tokenHash string) error
+	GetValid(ctx context.Context, tokenHash string) (*model.Session, error)
+	Touch(ctx context.Context, loginID uuid.UUID) error
+	FindActiveLogins(ctx context.Context, userID uuid.UUID) ([]*model.LoginSession, error)
+	RevokeLogin(ctx context.Context, userID, loginID uuid.UUID) error
+	RevokeAllLogins(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 type sessionRepository struct {
@@ -21,51 +36,331 @@ func NewSessionRepository(db PgxIface) SessionRepository {
 	return &sessionRepository{db: db}
 }
 
-func (r *sessionRepository) Create(ctx context.Context, session *model.Session) error {
+// Issue stores a new login together with its first session and refresh token.
+func (r *sessionRepository) Issue(ctx context.Context, login *model.LoginSession, session *model.Session, refresh *model.RefreshToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO sessions (id, user_id, role, expired_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO login_sessions (id, user_id, ip_address, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING last_seen_at, created_at
 	`
-	_, err := r.db.Exec(ctx, query,
-		session.ID,
-		session.UserID,
-		session.Role,
-		session.ExpiredAt,
-	)
-	return err
+	err = tx.QueryRow(ctx, query,
+		login.ID,
+		login.UserID,
+		login.IPAddress,
+		login.UserAgent,
+		login.ExpiresAt,
+	).Scan(&login.LastSeenAt, &login.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertSession(ctx, tx, session); err != nil {
+		return err
+	}
+	if err := insertRefreshToken(ctx, tx, refresh); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *sessionRepository) Revoke(ctx context.Context, sessionID uuid.UUID) error {
+// Rotate exchanges the refresh token with the given hash for the session and refresh token built by rotate.
+// The old token is locked and marked used, so concurrent exchanges of the same token cannot both win.
+// Presenting a token that was already used revokes every session and refresh token of its family
+// and returns ErrTokenReused. Unknown or revoked tokens return ErrNotFound.
+func (r *sessionRepository) Rotate(ctx context.Context, refreshHash string, client model.ClientInfo, rotate RotateFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	current := &model.RefreshToken{}
+	err = tx.QueryRow(ctx, `
+		SELECT id, family_id, user_id, expired_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`, refreshHash).Scan(
+		&current.ID,
+		&current.FamilyID,
+		&current.UserID,
+		&current.ExpiredAt,
+		&current.UsedAt,
+		&current.RevokedAt,
+		&current.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if current.RevokedAt != nil {
+		return ErrNotFound
+	}
+
+	// 🛡️ GUARD: Token bekas dipakai lagi = kemungkinan dicuri, matikan satu keluarga
+	if current.UsedAt != nil {
+		if err := revokeFamily(ctx, tx, current.FamilyID); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return ErrTokenReused
+	}
+
+	session, refresh, err := rotate(current)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1`, current.ID); err != nil {
+		return err
+	}
+	if err := insertSession(ctx, tx, session); err != nil {
+		return err
+	}
+	if err := insertRefreshToken(ctx, tx, refresh); err != nil {
+		return err
+	}
+
 	query := `
-		UPDATE sessions
-		SET revoked_at = NOW()
-		WHERE id = $1 AND revoked_at IS NULL
+		UPDATE login_sessions
+		SET expires_at = $2, last_seen_at = NOW(), ip_address = $3, user_agent = COALESCE(NULLIF($4, ''), user_agent)
+		WHERE id = $1
 	`
-	_, err := r.db.Exec(ctx, query, sessionID)
-	return err
+	if _, err := tx.Exec(ctx, query, current.FamilyID, refresh.ExpiredAt, client.IPAddress, client.UserAgent); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *sessionRepository) GetValid(ctx context.Context, sessionID uuid.UUID) (*model.Session, error) {
+// Revoke logs the session with the given token hash out, together with every other session and
+// refresh token of its family. An unknown token is not an error.
+func (r *sessionRepository) Revoke(ctx context.Context, tokenHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var familyID uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT family_id FROM sessions WHERE token_hash = $1`, tokenHash).Scan(&familyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if err := revokeFamily(ctx, tx, familyID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetValid looks up a live session by the hash of its bearer token.
+func (r *sessionRepository) GetValid(ctx context.Context, tokenHash string) (*model.Session, error) {
 	query := `
-		SELECT id, user_id, role, expired_at, revoked_at, created_at
+		SELECT id, user_id, family_id, role, expired_at, revoked_at, created_at,
+		       COALESCE(ARRAY(SELECT rp.permission FROM role_permissions rp WHERE rp.role = sessions.role), '{}'),
+		       COALESCE(ARRAY(SELECT uw.warehouse_id FROM user_warehouses uw WHERE uw.user_id = sessions.user_id), '{}')
 		FROM sessions
-		WHERE id = $1
+		WHERE token_hash = $1
 		  AND expired_at > NOW()
 		  AND revoked_at IS NULL
+		  AND EXISTS (SELECT 1 FROM users u WHERE u.id = sessions.user_id AND u.deleted_at IS NULL)
 	`
 
 	session := &model.Session{}
-	err := r.db.QueryRow(ctx, query, sessionID).Scan(
+	var permissions []string
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
 		&session.ID,
 		&session.UserID,
+		&session.FamilyID,
 		&session.Role,
 		&session.ExpiredAt,
 		&session.RevokedAt,
 		&session.CreatedAt,
+		&permissions,
+		&session.WarehouseIDs,
 	)
 
 	if err != nil {
 		return nil, err
 	}
+	session.Permissions = toPermissions(permissions)
 	return session, nil
 }
+
+// Touch records activity on a login. Writes are throttled to one per minute per login.
+func (r *sessionRepository) Touch(ctx context.Context, loginID uuid.UUID) error {
+	query := `
+		UPDATE login_sessions
+		SET last_seen_at = NOW()
+		WHERE id = $1 AND last_seen_at < NOW() - INTERVAL '1 minute'
+	`
+	_, err := r.db.Exec(ctx, query, loginID)
+	return err
+}
+
+// FindActiveLogins lists a user's logins that are neither revoked nor expired, most recently used first.
+func (r *sessionRepository) FindActiveLogins(ctx context.Context, userID uuid.UUID) ([]*model.LoginSession, error) {
+	query := `
+		SELECT id, user_id, ip_address, user_agent, expires_at, last_seen_at, revoked_at, created_at
+		FROM login_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logins []*model.LoginSession
+	for rows.Next() {
+		var l model.LoginSession
+		if err := rows.Scan(&l.ID, &l.UserID, &l.IPAddress, &l.UserAgent, &l.ExpiresAt, &l.LastSeenAt, &l.RevokedAt, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logins = append(logins, &l)
+	}
+	return logins, rows.Err()
+}
+
+// RevokeLogin revokes one of the user's active logins. A login of another user is reported as ErrNotFound.
+func (r *sessionRepository) RevokeLogin(ctx context.Context, userID, loginID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM login_sessions WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL)`
+	if err := tx.QueryRow(ctx, query, loginID, userID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	if err := revokeFamily(ctx, tx, loginID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeAllLogins revokes every login of a user and returns how many were still active. API keys are a
+// separate credential and keep working.
+func (r *sessionRepository) RevokeAllLogins(ctx context.Context, userID uuid.UUID) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	revoked, err := revokeUserLoginsExcept(ctx, tx, userID, uuid.Nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, tx.Commit(ctx)
+}
+
+func insertSession(ctx context.Context, tx pgx.Tx, session *model.Session) error {
+	query := `
+		INSERT INTO sessions (id, token_hash, user_id, family_id, role, expired_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.Exec(ctx, query,
+		session.ID,
+		session.TokenHash,
+		session.UserID,
+		session.FamilyID,
+		session.Role,
+		session.ExpiredAt,
+	)
+	return err
+}
+
+func insertRefreshToken(ctx context.Context, tx pgx.Tx, refresh *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, token_hash, family_id, user_id, expired_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := tx.Exec(ctx, query,
+		refresh.ID,
+		refresh.TokenHash,
+		refresh.FamilyID,
+		refresh.UserID,
+		refresh.ExpiredAt,
+	)
+	return err
+}
+
+// revokeFamily revokes a login together with every live session and refresh token issued from it.
+func revokeFamily(ctx context.Context, tx pgx.Tx, familyID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `UPDATE login_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, familyID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE sessions SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, familyID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, familyID)
+	return err
+}
+
+// revokeUserTokens revokes every login, session, refresh token and API key of a user, for a password
+// reset or account deletion. API keys go too: one minted from a stolen login would otherwise outlive
+// the change meant to shut the intruder out. It returns the number of logins that were still active.
+func revokeUserTokens(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (int64, error) {
+	revoked, err := revokeUserLoginsExcept(ctx, tx, userID, uuid.Nil)
+	if err != nil {
+		return 0, err
+	}
+	return revoked, revokeUserAPIKeys(ctx, tx, userID)
+}
+
+// revokeUserAPIKeys revokes every API key of a user.
+func revokeUserAPIKeys(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+// revokeUserLoginsExcept revokes the logins of a user, with their sessions and refresh tokens, except
+// keepLoginID; uuid.Nil keeps nothing. API keys are left alone. It returns the number of logins that
+// were still active.
+func revokeUserLoginsExcept(ctx context.Context, tx pgx.Tx, userID, keepLoginID uuid.UUID) (int64, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE login_sessions SET revoked_at = NOW()
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`, userID, keepLoginID)
+	if err != nil {
+		return 0, err
+	}
+	// Expired logins are closed too, without being counted.
+	if _, err := tx.Exec(ctx, `UPDATE login_sessions SET revoked_at = NOW() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL`, userID, keepLoginID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL`, userID, keepLoginID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL`, userID, keepLoginID); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
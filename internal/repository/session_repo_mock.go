@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSessionRepository adalah "Stuntman" untuk SessionRepository asli kita
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Issue(ctx context.Context, login *model.LoginSession, session *model.Session, refresh *model.RefreshToken) error {
+	args := m.Called(ctx, login, session, refresh)
+	return args.Error(0)
+}
+
+// Rotate plays the locked row: the first return value is the refresh token being exchanged, which is
+// fed to rotate just like the real transaction would. A non-nil error is returned before rotate runs.
+func (m *MockSessionRepository) Rotate(ctx context.Context, refreshHash string, client model.ClientInfo, rotate RotateFunc) error {
+	args := m.Called(ctx, refreshHash, client)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+
+	_, _, err := rotate(args.Get(0).(*model.RefreshToken))
+	return err
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, tokenHash string) error {
+	args := m.Called(ctx, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetValid(ctx context.Context, tokenHash string) (*model.Session, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Session), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(ctx context.Context, loginID uuid.UUID) error {
+	args := m.Called(ctx, loginID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindActiveLogins(ctx context.Context, userID uuid.UUID) ([]*model.LoginSession, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.LoginSession), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeLogin(ctx context.Context, userID, loginID uuid.UUID) error {
+	args := m.Called(ctx, userID, loginID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllLogins(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
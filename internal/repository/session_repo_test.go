@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// touchesAPIKeys melaporkan apakah ada statement yang mengubah tabel api_keys.
+func touchesAPIKeys(steps []string) bool {
+	for _, step := range steps {
+		if strings.Contains(step, "UPDATE api_keys") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRevokeAllLogins_KeepsAPIKeys(t *testing.T) {
+	db := &fakeDB{}
+
+	_, err := NewSessionRepository(db).RevokeAllLogins(context.Background(), uuid.New())
+
+	// "Logout di semua device" gak boleh mematikan scanner & script yang pakai API key
+	require.NoError(t, err)
+	assert.Equal(t, "COMMIT", db.steps[len(db.steps)-1])
+	assert.False(t, touchesAPIKeys(db.steps))
+}
+
+func TestRevokeUserTokens_AlsoRevokesAPIKeys(t *testing.T) {
+	db := &fakeDB{}
+
+	// Dipakai reset password & hapus user: API key dari login yang dicuri harus ikut mati
+	_, err := revokeUserTokens(context.Background(), &fakeTx{db: db}, uuid.New())
+
+	require.NoError(t, err)
+	assert.True(t, touchesAPIKeys(db.steps))
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ShelfFilter narrows shelf listings. A nil WarehouseID means every warehouse.
+type ShelfFilter struct {
+	WarehouseID *uuid.UUID
+	Search      string
+}
+
+// ShelfRepository defines the contract for shelf database operations.
+type ShelfRepository interface {
+	Create(ctx context.Context, shelf *model.Shelf) error
+	Count(ctx context.Context, filter ShelfFilter) (int64, error)
+	FindAll(ctx context.Context, limit, offset int, filter ShelfFilter) ([]*model.Shelf, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Shelf, error)
+	Update(ctx context.Context, shelf *model.Shelf) error
+	SoftDelete(ctx context.Context, id uuid.UUID, version int) error
+	HasStock(ctx context.Context, id uuid.UUID) (bool, error)
+	FindContents(ctx context.Context, id uuid.UUID) ([]*model.ShelfContent, error)
+}
+
+type shelfRepository struct {
+	db PgxIface
+}
+
+// NewShelfRepository creates and returns a new ShelfRepository instance.
+func NewShelfRepository(db PgxIface) ShelfRepository {
+	return &shelfRepository{db: db}
+}
+
+func (r *shelfRepository) Create(ctx context.Context, shelf *model.Shelf) error {
+	query := `
+		INSERT INTO shelves (id, warehouse_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING version, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, shelf.ID, shelf.WarehouseID, shelf.Name).Scan(&shelf.Version, &shelf.CreatedAt, &shelf.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *shelfRepository) Count(ctx context.Context, filter ShelfFilter) (int64, error) {
+	query := `
+		SELECT COUNT(s.id)
+		FROM shelves s
+		JOIN warehouses w ON w.id = s.warehouse_id
+		WHERE s.deleted_at IS NULL AND w.deleted_at IS NULL
+		  AND ($1::uuid IS NULL OR s.warehouse_id = $1)
+		  AND s.name ILIKE '%' || $2 || '%'
+		  AND ($3::uuid[] IS NULL OR s.warehouse_id = ANY($3))
+	`
+	var total int64
+	err := r.db.QueryRow(ctx, query, filter.WarehouseID, filter.Search, scopedWarehouseIDs(ctx)).Scan(&total)
+	return total, err
+}
+
+func (r *shelfRepository) FindAll(ctx context.Context, limit, offset int, filter ShelfFilter) ([]*model.Shelf, error) {
+	query := `
+		SELECT s.id, s.warehouse_id, w.name, s.name, s.version, s.created_at, s.updated_at
+		FROM shelves s
+		JOIN warehouses w ON w.id = s.warehouse_id
+		WHERE s.deleted_at IS NULL AND w.deleted_at IS NULL
+		  AND ($1::uuid IS NULL OR s.warehouse_id = $1)
+		  AND s.name ILIKE '%' || $2 || '%'
+		  AND ($3::uuid[] IS NULL OR s.warehouse_id = ANY($3))
+		ORDER BY w.name ASC, s.name ASC
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.db.Query(ctx, query, filter.WarehouseID, filter.Search, scopedWarehouseIDs(ctx), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shelves []*model.Shelf
+	for rows.Next() {
+		var s model.Shelf
+		if err := rows.Scan(&s.ID, &s.WarehouseID, &s.WarehouseName, &s.Name, &s.Version, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		shelves = append(shelves, &s)
+	}
+	return shelves, rows.Err()
+}
+
+// FindByID retrieves an active shelf that belongs to an active warehouse in the caller's scope.
+func (r *shelfRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Shelf, error) {
+	query := `
+		SELECT s.id, s.warehouse_id, w.name, s.name, s.version, s.created_at, s.updated_at
+		FROM shelves s
+		JOIN warehouses w ON w.id = s.warehouse_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL
+		  AND ($2::uuid[] IS NULL OR s.warehouse_id = ANY($2))
+	`
+	var s model.Shelf
+	err := r.db.QueryRow(ctx, query, id, scopedWarehouseIDs(ctx)).Scan(&s.ID, &s.WarehouseID, &s.WarehouseName, &s.Name, &s.Version, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Update renames an active shelf that is still at shelf.Version, and bumps the version.
+func (r *shelfRepository) Update(ctx context.Context, shelf *model.Shelf) error {
+	query := `
+		UPDATE shelves
+		SET name = $1, version = version + 1, updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL AND version = $3
+		RETURNING version, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, shelf.Name, shelf.ID, shelf.Version).Scan(&shelf.Version, &shelf.UpdatedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return staleOrMissing(ctx, r.db, "shelves", shelf.ID)
+	case isUniqueViolation(err):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// SoftDelete marks a shelf as deleted and unassigns any items still pointing at it,
+// mirroring the ON DELETE SET NULL behavior of items.shelf_id. The shelf must still be at version;
+// the unassigned items count as edited and get a new version too.
+func (r *shelfRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	query := `
+		WITH deleted AS (
+			UPDATE shelves SET deleted_at = NOW(), version = version + 1
+			WHERE id = $1 AND deleted_at IS NULL AND version = $2
+			RETURNING id
+		), unassigned AS (
+			UPDATE items SET shelf_id = NULL, version = version + 1, updated_at = NOW()
+			WHERE shelf_id IN (SELECT id FROM deleted)
+		)
+		SELECT COUNT(*) FROM deleted
+	`
+	var deleted int
+	if err := r.db.QueryRow(ctx, query, id, version).Scan(&deleted); err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return staleOrMissing(ctx, r.db, "shelves", id)
+	}
+	return nil
+}
+
+// HasStock reports whether any active item on the shelf has stock left.
+func (r *shelfRepository) HasStock(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM items
+			WHERE shelf_id = $1 AND deleted_at IS NULL AND stock > 0
+		)
+	`
+	var exists bool
+	err := r.db.QueryRow(ctx, query, id).Scan(&exists)
+	return exists, err
+}
+
+// FindContents lists the active items currently placed on a shelf with stock on hand.
+func (r *shelfRepository) FindContents(ctx context.Context, id uuid.UUID) ([]*model.ShelfContent, error) {
+	query := `
+		SELECT id, sku, name, stock
+		FROM items
+		WHERE shelf_id = $1 AND deleted_at IS NULL AND stock > 0
+		ORDER BY name ASC
+	`
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contents []*model.ShelfContent
+	for rows.Next() {
+		var c model.ShelfContent
+		if err := rows.Scan(&c.ItemID, &c.SKU, &c.Name, &c.Quantity); err != nil {
+			return nil, err
+		}
+		contents = append(contents, &c)
+	}
+	return contents, rows.Err()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShelfRepository adalah "Stuntman" untuk ShelfRepository asli kita
+type MockShelfRepository struct {
+	mock.Mock
+}
+
+func (m *MockShelfRepository) Create(ctx context.Context, shelf *model.Shelf) error {
+	args := m.Called(ctx, shelf)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) Count(ctx context.Context, filter ShelfFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockShelfRepository) FindAll(ctx context.Context, limit, offset int, filter ShelfFilter) ([]*model.Shelf, error) {
+	args := m.Called(ctx, limit, offset, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Shelf), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockShelfRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Shelf, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Shelf), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockShelfRepository) Update(ctx context.Context, shelf *model.Shelf) error {
+	args := m.Called(ctx, shelf)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockShelfRepository) HasStock(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockShelfRepository) FindContents(ctx context.Context, id uuid.UUID) ([]*model.ShelfContent, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.ShelfContent), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
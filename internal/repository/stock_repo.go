@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// StockLogFilter narrows an item's movement history. Nil/empty values mean "no filter".
+// From is inclusive, To is exclusive.
+type StockLogFilter struct {
+	MovementType model.MovementType
+	From         *time.Time
+	To           *time.Time
+}
+
+// StockApplyFunc receives the locked item's current stock and returns the new stock.
+// Returning an error aborts the movement and rolls the transaction back.
+type StockApplyFunc func(current int) (int, error)
+
+// StockRepository defines the contract for stock ledger operations.
+type StockRepository interface {
+	Apply(ctx context.Context, entry *model.StockLog, apply StockApplyFunc) error
+	CountByItem(ctx context.Context, itemID uuid.UUID, filter StockLogFilter) (int64, error)
+	FindByItem(ctx context.Context, itemID uuid.UUID, limit, offset int, filter StockLogFilter) ([]*model.StockLog, error)
+}
+
+type stockRepository struct {
+	db PgxIface
+}
+
+// NewStockRepository creates and returns a new StockRepository instance.
+func NewStockRepository(db PgxIface) StockRepository {
+	return &stockRepository{db: db}
+}
+
+// Apply locks the item row, lets apply compute the new stock, writes it back and appends
+// the ledger entry, all in one transaction. entry.BalanceAfter and entry.CreatedAt are filled in.
+func (r *stockRepository) Apply(ctx context.Context, entry *model.StockLog, apply StockApplyFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the item so concurrent movements are serialised. Items outside the caller's
+	//    warehouses are treated as missing.
+	var current int
+	err = tx.QueryRow(ctx, `
+		SELECT i.stock
+		FROM items i
+		LEFT JOIN shelves s ON s.id = i.shelf_id AND s.deleted_at IS NULL
+		WHERE i.id = $1 AND i.deleted_at IS NULL
+		  AND ($2::uuid[] IS NULL OR s.warehouse_id = ANY($2))
+		FOR UPDATE OF i
+	`, entry.ItemID, scopedWarehouseIDs(ctx)).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	// 2. Compute and store the new balance.
+	next, err := apply(current)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE items SET stock = $1, version = version + 1, updated_at = NOW() WHERE id = $2`, next, entry.ItemID); err != nil {
+		return err
+	}
+	entry.BalanceAfter = next
+
+	// 3. Append the ledger row.
+	if err := insertStockLog(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// insertStockLog appends a ledger row inside an existing transaction.
+func insertStockLog(ctx context.Context, tx pgx.Tx, entry *model.StockLog) error {
+	query := `
+		INSERT INTO stock_logs (id, item_id, user_id, movement_type, quantity, balance_after, reference_id, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		RETURNING created_at
+	`
+	return tx.QueryRow(ctx, query,
+		entry.ID,
+		entry.ItemID,
+		entry.UserID,
+		entry.MovementType,
+		entry.Quantity,
+		entry.BalanceAfter,
+		entry.ReferenceID,
+		entry.Description,
+	).Scan(&entry.CreatedAt)
+}
+
+func (r *stockRepository) CountByItem(ctx context.Context, itemID uuid.UUID, filter StockLogFilter) (int64, error) {
+	where, args := buildStockLogWhere(itemID, filter)
+
+	var total int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(id) FROM stock_logs WHERE `+where, args...).Scan(&total)
+	return total, err
+}
+
+// FindByItem returns an item's movements, newest first.
+func (r *stockRepository) FindByItem(ctx context.Context, itemID uuid.UUID, limit, offset int, filter StockLogFilter) ([]*model.StockLog, error) {
+	where, args := buildStockLogWhere(itemID, filter)
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT id, item_id, user_id, movement_type, quantity, balance_after, reference_id, COALESCE(description, ''), created_at
+		FROM stock_logs
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*model.StockLog
+	for rows.Next() {
+		var l model.StockLog
+		if err := rows.Scan(
+			&l.ID, &l.ItemID, &l.UserID, &l.MovementType, &l.Quantity, &l.BalanceAfter,
+			&l.ReferenceID, &l.Description, &l.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, &l)
+	}
+	return logs, rows.Err()
+}
+
+// buildStockLogWhere turns a StockLogFilter into a WHERE clause and its positional arguments.
+func buildStockLogWhere(itemID uuid.UUID, filter StockLogFilter) (string, []any) {
+	conditions := []string{"item_id = $1"}
+	args := []any{itemID}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.MovementType != "" {
+		add(`movement_type = ?`, filter.MovementType)
+	}
+	if filter.From != nil {
+		add(`created_at >= ?`, *filter.From)
+	}
+	if filter.To != nil {
+		add(`created_at < ?`, *filter.To)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
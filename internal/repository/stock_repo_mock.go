@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStockRepository adalah "Stuntman" untuk StockRepository asli kita
+type MockStockRepository struct {
+	mock.Mock
+}
+
+// Apply plays the locked row: the first return value is the item's current stock, which is fed
+// to apply just like the real transaction would. A non-nil error is returned before apply runs.
+func (m *MockStockRepository) Apply(ctx context.Context, entry *model.StockLog, apply StockApplyFunc) error {
+	args := m.Called(ctx, entry)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+
+	next, err := apply(args.Int(0))
+	if err != nil {
+		return err
+	}
+	entry.BalanceAfter = next
+	return nil
+}
+
+func (m *MockStockRepository) CountByItem(ctx context.Context, itemID uuid.UUID, filter StockLogFilter) (int64, error) {
+	args := m.Called(ctx, itemID, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStockRepository) FindByItem(ctx context.Context, itemID uuid.UUID, limit, offset int, filter StockLogFilter) ([]*model.StockLog, error) {
+	args := m.Called(ctx, itemID, limit, offset, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.StockLog), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TwoFactorRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error)
+	StartEnrollment(ctx context.Context, userID uuid.UUID, secret string) error
+	Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID uuid.UUID) error
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+
+	CreateChallenge(ctx context.Context, challenge *model.LoginChallenge) error
+	FindChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*model.LoginChallenge, error)
+	FailChallenge(ctx context.Context, id uuid.UUID) error
+	CompleteChallenge(ctx context.Context, id uuid.UUID) error
+}
+
+type twoFactorRepository struct {
+	db PgxIface
+}
+
+func NewTwoFactorRepository(db PgxIface) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// Get returns the user's enrollment, confirmed or not. ErrNotFound means 2FA was never set up.
+func (r *twoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error) {
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`
+	tf := &model.TwoFactor{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&tf.UserID, &tf.Secret, &tf.EnabledAt, &tf.LastUsedStep, &tf.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return tf, nil
+}
+
+// StartEnrollment stores a new, unconfirmed secret, replacing an earlier unconfirmed one.
+// It returns ErrDuplicate when 2FA is already enabled.
+func (r *twoFactorRepository) StartEnrollment(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := `
+		INSERT INTO user_two_factor (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_two_factor.enabled_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+// Enable confirms an enrollment with the step of the code that proved it, and stores the first
+// recovery codes. ErrNotFound means there is no unconfirmed enrollment.
+func (r *twoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE user_two_factor
+		SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NULL
+	`
+	tag, err := tx.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Disable removes the enrollment and its recovery codes. ErrNotFound means 2FA was not set up.
+func (r *twoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseStep records that the code of a time step was used. A step at or before the last used one
+// returns ErrNotFound, so an intercepted code cannot be replayed.
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_two_factor
+		SET last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+	`
+	tag, err := r.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UseRecoveryCode spends one of the user's recovery codes. Unknown or spent codes return ErrNotFound.
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := `UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes discards every recovery code of the user and stores new ones.
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *twoFactorRepository) CreateChallenge(ctx context.Context, challenge *model.LoginChallenge) error {
+	query := `
+		INSERT INTO login_challenges (id, user_id, token_hash, enrollment_required, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	return r.db.QueryRow(ctx, query,
+		challenge.ID,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.EnrollmentRequired,
+		challenge.ExpiresAt,
+	).Scan(&challenge.CreatedAt)
+}
+
+// FindChallenge looks up an unused, unexpired challenge with fewer than maxAttempts failed codes.
+func (r *twoFactorRepository) FindChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*model.LoginChallenge, error) {
+	query := `
+		SELECT id, user_id, enrollment_required, attempts, expires_at, used_at, created_at
+		FROM login_challenges
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() AND attempts < $2
+	`
+	c := &model.LoginChallenge{TokenHash: tokenHash}
+	err := r.db.QueryRow(ctx, query, tokenHash, maxAttempts).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.EnrollmentRequired,
+		&c.Attempts,
+		&c.ExpiresAt,
+		&c.UsedAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// FailChallenge counts a wrong code against a challenge.
+func (r *twoFactorRepository) FailChallenge(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE login_challenges SET attempts = attempts + 1 WHERE id = $1`, id)
+	return err
+}
+
+// CompleteChallenge marks a challenge used. ErrNotFound means it was already used by a concurrent request.
+func (r *twoFactorRepository) CompleteChallenge(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `UPDATE login_challenges SET used_at = NOW() WHERE id = $1 AND used_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		query := `INSERT INTO recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(ctx, query, uuid.New(), userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTwoFactorRepository adalah "Stuntman" untuk TwoFactorRepository asli kita
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.TwoFactor), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) StartEnrollment(ctx context.Context, userID uuid.UUID, secret string) error {
+	args := m.Called(ctx, userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	args := m.Called(ctx, userID, step, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	args := m.Called(ctx, userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *model.LoginChallenge) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) FindChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*model.LoginChallenge, error) {
+	args := m.Called(ctx, tokenHash, maxAttempts)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.LoginChallenge), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) FailChallenge(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) CompleteChallenge(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
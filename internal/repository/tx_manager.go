@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxTxAttempts is how many times WithinTx runs a transaction that keeps losing to concurrent ones.
+const maxTxAttempts = 3
+
+// txRetryBackoff is the base wait before a retry; it grows with each attempt and is jittered so the
+// transactions that collided do not collide again.
+const txRetryBackoff = 20 * time.Millisecond
+
+// TxManager runs several repository calls as one unit of work.
+type TxManager interface {
+	// WithinTx runs fn in a transaction and commits it when fn returns nil. Every repository call
+	// made with the ctx passed to fn joins that transaction, including a repository's own
+	// transactions, which become savepoints.
+	//
+	// Called inside another WithinTx, it opens a savepoint instead: an error rolls back only fn's
+	// work and is returned to the outer function to handle.
+	//
+	// A transaction that fails on a serialization failure or deadlock is rolled back and run again
+	// from the start, so fn must not have effects outside the database. The transaction belongs
+	// to a single connection: fn must not use ctx from several goroutines at once.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txManager struct {
+	db    PgxIface
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewTxManager(db PgxIface) TxManager {
+	return &txManager{db: db, sleep: sleepContext}
+}
+
+type txKey struct{}
+
+// txFrom returns the transaction running in ctx, if any.
+func txFrom(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := txFrom(ctx); ok {
+		// Retrying a savepoint is pointless: the failure aborted the whole transaction.
+		return runTx(ctx, tx, fn)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, m.db, fn)
+		if err == nil || !isRetryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		backoff := time.Duration(attempt) * txRetryBackoff
+		backoff += rand.N(backoff)
+		if sleepErr := m.sleep(ctx, backoff); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// runTx begins a transaction, or a savepoint when parent is itself a transaction, and runs fn in it.
+func runTx(ctx context.Context, parent conn, fn func(ctx context.Context) error) error {
+	tx, err := parent.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// Rolling back after a commit is a no-op.
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// isRetryable reports whether err is a PostgreSQL serialization_failure (40001) or deadlock_detected
+// (40P01), the errors a transaction can simply be run again after.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// conn is what the pool (PgxIface) and a transaction (pgx.Tx) have in common.
+type conn interface {
+	rowQuerier
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// txAwareDB sends every statement to the transaction in ctx when there is one, and to the pool
+// otherwise. Repositories are given one instead of the pool, which is how they join WithinTx.
+type txAwareDB struct {
+	PgxIface
+}
+
+func (db txAwareDB) conn(ctx context.Context) conn {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return db.PgxIface
+}
+
+func (db txAwareDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return db.conn(ctx).Query(ctx, sql, args...)
+}
+
+func (db txAwareDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return db.conn(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (db txAwareDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return db.conn(ctx).Exec(ctx, sql, args...)
+}
+
+// Begin opens a savepoint inside the transaction in ctx, or a new transaction.
+func (db txAwareDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return db.conn(ctx).Begin(ctx)
+}
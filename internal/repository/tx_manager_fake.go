@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// FakeTxManager adalah "Stuntman" untuk TxManager asli kita: fn langsung dijalankan tanpa database.
+// Calls menghitung berapa kali WithinTx dipanggil, Err (kalau diisi) dikembalikan tanpa menjalankan fn,
+// seolah-olah transaksinya gagal dibuka.
+type FakeTxManager struct {
+	Calls int
+	Err   error
+}
+
+func (f *FakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.Calls++
+	if f.Err != nil {
+		return f.Err
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB mencatat semua yang terjadi di "database" sebagai daftar langkah.
+type fakeDB struct {
+	PgxIface
+	steps []string
+}
+
+func (db *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	db.steps = append(db.steps, "BEGIN")
+	return &fakeTx{db: db}, nil
+}
+
+func (db *fakeDB) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	db.steps = append(db.steps, "pool: "+sql)
+	return pgconn.CommandTag{}, nil
+}
+
+// fakeTx meniru pgx.Tx: transaksi di level atas, savepoint kalau nested.
+type fakeTx struct {
+	pgx.Tx
+	db     *fakeDB
+	nested bool
+	closed bool
+}
+
+func (tx *fakeTx) Begin(context.Context) (pgx.Tx, error) {
+	tx.db.steps = append(tx.db.steps, "SAVEPOINT")
+	return &fakeTx{db: tx.db, nested: true}, nil
+}
+
+func (tx *fakeTx) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	tx.db.steps = append(tx.db.steps, "tx: "+sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.closed = true
+	if tx.nested {
+		tx.db.steps = append(tx.db.steps, "RELEASE SAVEPOINT")
+	} else {
+		tx.db.steps = append(tx.db.steps, "COMMIT")
+	}
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	if tx.closed {
+		return pgx.ErrTxClosed
+	}
+	tx.closed = true
+	if tx.nested {
+		tx.db.steps = append(tx.db.steps, "ROLLBACK TO SAVEPOINT")
+	} else {
+		tx.db.steps = append(tx.db.steps, "ROLLBACK")
+	}
+	return nil
+}
+
+func newTestTxManager() (*txManager, *fakeDB, *[]time.Duration) {
+	db := &fakeDB{}
+	var waits []time.Duration
+	m := &txManager{db: txAwareDB{db}, sleep: func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}}
+	return m, db, &waits
+}
+
+func TestWithinTx_RepositoriesJoinTheTransaction(t *testing.T) {
+	m, db, _ := newTestTxManager()
+	repoDB := txAwareDB{db}
+
+	_, _ = repoDB.Exec(context.Background(), "outside")
+	err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+		_, err := repoDB.Exec(ctx, "inside")
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pool: outside", "BEGIN", "tx: inside", "COMMIT"}, db.steps)
+}
+
+func TestWithinTx_RollsBackOnError(t *testing.T) {
+	m, db, _ := newTestTxManager()
+	boom := errors.New("boom")
+
+	err := m.WithinTx(context.Background(), func(context.Context) error { return boom })
+
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, db.steps)
+}
+
+func TestWithinTx_NestedUsesSavepoints(t *testing.T) {
+	m, db, _ := newTestTxManager()
+	repoDB := txAwareDB{db}
+	boom := errors.New("boom")
+
+	err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, m.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := repoDB.Exec(ctx, "kept")
+			return err
+		}))
+
+		// Error di dalam cuma membatalkan savepoint-nya; transaksi luar jalan terus
+		err := m.WithinTx(ctx, func(ctx context.Context) error {
+			_, _ = repoDB.Exec(ctx, "undone")
+			return boom
+		})
+		assert.ErrorIs(t, err, boom)
+
+		// Transaksi internal repository juga jadi savepoint
+		tx, err := repoDB.Begin(ctx)
+		require.NoError(t, err)
+		return tx.Commit(ctx)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"BEGIN",
+		"SAVEPOINT", "tx: kept", "RELEASE SAVEPOINT",
+		"SAVEPOINT", "tx: undone", "ROLLBACK TO SAVEPOINT",
+		"SAVEPOINT", "RELEASE SAVEPOINT",
+		"COMMIT",
+	}, db.steps)
+}
+
+func TestWithinTx_RetriesSerializationFailuresAndDeadlocks(t *testing.T) {
+	t.Run("succeeds on a later attempt", func(t *testing.T) {
+		m, db, waits := newTestTxManager()
+		failures := []error{&pgconn.PgError{Code: "40001"}, &pgconn.PgError{Code: "40P01"}}
+
+		attempts := 0
+		err := m.WithinTx(context.Background(), func(context.Context) error {
+			attempts++
+			if attempts <= len(failures) {
+				return failures[attempts-1]
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK", "BEGIN", "COMMIT"}, db.steps)
+		require.Len(t, *waits, 2)
+		assert.GreaterOrEqual(t, (*waits)[1], 2*txRetryBackoff)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		m, _, _ := newTestTxManager()
+
+		attempts := 0
+		err := m.WithinTx(context.Background(), func(context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "40001"}
+		})
+
+		assert.True(t, isRetryable(err))
+		assert.Equal(t, maxTxAttempts, attempts)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		m, _, waits := newTestTxManager()
+
+		attempts := 0
+		err := m.WithinTx(context.Background(), func(context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "23505"}
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+		assert.Empty(t, *waits)
+	})
+
+	t.Run("savepoints are not retried", func(t *testing.T) {
+		m, _, _ := newTestTxManager()
+
+		inner := 0
+		_ = m.WithinTx(context.Background(), func(ctx context.Context) error {
+			if inner > 0 {
+				return nil
+			}
+			return m.WithinTx(ctx, func(context.Context) error {
+				inner++
+				return &pgconn.PgError{Code: "40P01"}
+			})
+		})
+
+		// Yang diulang transaksi luar, bukan savepoint-nya
+		assert.Equal(t, 1, inner)
+	})
+}
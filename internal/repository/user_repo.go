@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"inventory-system/internal/model"
 
@@ -10,15 +11,27 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// UserFilter narrows the user list. Soft-deleted users are hidden unless IncludeDeleted is set.
+type UserFilter struct {
+	Search         string
+	IncludeDeleted bool
+}
+
 // UserRepository defines the contract for user database operations.
 type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	Create(ctx context.Context, user *model.User) error
-	Count(ctx context.Context, search string) (int64, error)
-	FindAll(ctx context.Context, limit, offset int, search string) ([]*model.User, error)
+	Count(ctx context.Context, filter UserFilter) (int64, error)
+	FindAll(ctx context.Context, limit, offset int, filter UserFilter) ([]*model.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, keepLoginID uuid.UUID) (int64, error)
+	SoftDelete(ctx context.Context, id uuid.UUID, version int) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	RecordFailedLogin(ctx context.Context, id uuid.UUID, maxAttempts int, lockout time.Duration) (*model.User, error)
+	ResetFailedLogins(ctx context.Context, id uuid.UUID) error
+	FindLockouts(ctx context.Context) ([]*model.User, error)
+	ClearLockout(ctx context.Context, id uuid.UUID) error
 }
 
 // userRepository is the concrete implementation of UserRepository.
@@ -31,13 +44,14 @@ func NewUserRepository(db PgxIface) UserRepository {
 	return &userRepository{db: db}
 }
 
-// FindByEmail searches for an active user by their email address.
+// FindByEmail searches for an active user by their email address, ignoring case.
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	// The query strictly ignores soft-deleted users (deleted_at IS NULL)
 	query := `
-		SELECT id, name, email, password_hash, role, created_at, updated_at, deleted_at
+		SELECT id, name, email, password_hash, role, failed_login_attempts, last_failed_login_at, locked_until,
+		       version, created_at, updated_at, deleted_at
 		FROM users
-		WHERE email = $1 AND deleted_at IS NULL
+		WHERE email = LOWER($1) AND deleted_at IS NULL
 	`
 
 	var user model.User
@@ -49,6 +63,10 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.
 		&user.Email,
 		&user.PasswordHash,
 		&user.Role,
+		&user.FailedLoginAttempts,
+		&user.LastFailedLoginAt,
+		&user.LockedUntil,
+		&user.Version,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
@@ -57,7 +75,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.
 	if err != nil {
 		// If no matching row is found, return a clear error
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+			return nil, ErrNotFound
 		}
 		// Return any other database errors (e.g., connection lost)
 		return nil, err
@@ -70,33 +88,37 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 	query := `
 		INSERT INTO users (id, name, email, password_hash, role)
 		VALUES ($1, $2, $3, $4, $5)
+		RETURNING version
 	`
-	_, err := r.db.Exec(ctx, query,
+	err := r.db.QueryRow(ctx, query,
 		user.ID,
 		user.Name,
 		user.Email,
 		user.PasswordHash,
 		user.Role,
-	)
+	).Scan(&user.Version)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
 	return err
 }
 
-func (r *userRepository) Count(ctx context.Context, search string) (int64, error) {
-	query := `SELECT COUNT(id) FROM users WHERE name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%'`
+func (r *userRepository) Count(ctx context.Context, filter UserFilter) (int64, error) {
+	query := `SELECT COUNT(id) FROM users WHERE ` + buildUserWhere(filter)
 	var total int64
-	err := r.db.QueryRow(ctx, query, search).Scan(&total)
+	err := r.db.QueryRow(ctx, query, filter.Search).Scan(&total)
 	return total, err
 }
 
-func (r *userRepository) FindAll(ctx context.Context, limit, offset int, search string) ([]*model.User, error) {
+func (r *userRepository) FindAll(ctx context.Context, limit, offset int, filter UserFilter) ([]*model.User, error) {
 	query := `
-		SELECT id, name, email, role
+		SELECT id, name, email, role, version, created_at, updated_at, deleted_at
 		FROM users
-		WHERE name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%'
+		WHERE ` + buildUserWhere(filter) + `
 		ORDER BY name ASC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.Query(ctx, query, search, limit, offset)
+	rows, err := r.db.Query(ctx, query, filter.Search, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +127,7 @@ func (r *userRepository) FindAll(ctx context.Context, limit, offset int, search
 	var users []*model.User
 	for rows.Next() {
 		var u model.User
-		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role); err != nil {
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.Version, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt); err != nil {
 			return nil, err
 		}
 		users = append(users, &u)
@@ -113,27 +135,217 @@ func (r *userRepository) FindAll(ctx context.Context, limit, offset int, search
 	return users, rows.Err()
 }
 
-// FindByID retrieves a user by their UUID.
+// FindByID retrieves an active user by their UUID, including their failed login state so
+// password checks outside the login form can honour the lockout.
 func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
-	query := `SELECT id, name, email, role FROM users WHERE id = $1`
+	query := `
+		SELECT id, name, email, password_hash, role, failed_login_attempts, last_failed_login_at, locked_until,
+		       version, created_at, updated_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	`
 	var user model.User
-	err := r.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Name, &user.Email, &user.Role)
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.FailedLoginAttempts,
+		&user.LastFailedLoginAt,
+		&user.LockedUntil,
+		&user.Version,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Update modifies an existing user's data (name and role) in the database.
+// Update modifies an active user's data (name and role) in the database, provided the user is still
+// at user.Version. On success user.Version holds the new version.
 func (r *userRepository) Update(ctx context.Context, user *model.User) error {
-	query := `UPDATE users SET name = $1, role = $2 WHERE id = $3`
-	_, err := r.db.Exec(ctx, query, user.Name, user.Role, user.ID)
+	query := `
+		UPDATE users
+		SET name = $1, role = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL AND version = $4
+		RETURNING version, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, user.Name, user.Role, user.ID, user.Version).Scan(&user.Version, &user.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return staleOrMissing(ctx, r.db, "users", user.ID)
+	}
 	return err
 }
 
-// Delete removes a user from the database by their UUID.
-func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
+// UpdatePassword stores a new password hash and, in the same transaction, revokes every API key and
+// every login of the user except keepLoginID. It returns the number of logins revoked.
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, keepLoginID uuid.UUID) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := tx.Exec(ctx, query, id, passwordHash)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, ErrNotFound
+	}
+
+	revoked, err := revokeUserLoginsExcept(ctx, tx, id, keepLoginID)
+	if err != nil {
+		return 0, err
+	}
+	if err := revokeUserAPIKeys(ctx, tx, id); err != nil {
+		return 0, err
+	}
+
+	return revoked, tx.Commit(ctx)
+}
+
+// SoftDelete marks a user as deleted and revokes all of their sessions, refresh tokens and API keys in one
+// transaction, so a deleted user is logged out everywhere immediately. The user must still be at version.
+func (r *userRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE users SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND version = $2
+	`
+	tag, err := tx.Exec(ctx, query, id, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return staleOrMissing(ctx, tx, "users", id)
+	}
+
+	if _, err := revokeUserTokens(ctx, tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Restore clears deleted_at on a soft-deleted user. Revoked sessions and API keys stay revoked.
+func (r *userRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordFailedLogin counts a failed login in one atomic update. Reaching maxAttempts locks the account
+// for the lockout duration; every further failure after the lock expires locks it again until a
+// successful login or an admin clears the counter. It returns the updated lockout state.
+func (r *userRepository) RecordFailedLogin(ctx context.Context, id uuid.UUID, maxAttempts int, lockout time.Duration) (*model.User, error) {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = failed_login_attempts + 1,
+		    last_failed_login_at = NOW(),
+		    locked_until = CASE
+		        WHEN failed_login_attempts + 1 >= $2 THEN NOW() + make_interval(secs => $3)
+		        ELSE locked_until
+		    END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, failed_login_attempts, last_failed_login_at, locked_until
+	`
+	user := &model.User{}
+	err := r.db.QueryRow(ctx, query, id, maxAttempts, lockout.Seconds()).Scan(
+		&user.ID,
+		&user.FailedLoginAttempts,
+		&user.LastFailedLoginAt,
+		&user.LockedUntil,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResetFailedLogins clears the failed login counter after a successful login.
+func (r *userRepository) ResetFailedLogins(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = $1 AND failed_login_attempts > 0
+	`
 	_, err := r.db.Exec(ctx, query, id)
 	return err
 }
+
+// FindLockouts lists active users that are locked out or have failed logins since their last success,
+// most recent failure first.
+func (r *userRepository) FindLockouts(ctx context.Context) ([]*model.User, error) {
+	query := `
+		SELECT id, name, email, role, failed_login_attempts, last_failed_login_at, locked_until
+		FROM users
+		WHERE deleted_at IS NULL AND failed_login_attempts > 0
+		ORDER BY last_failed_login_at DESC NULLS LAST
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*model.User
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.FailedLoginAttempts, &u.LastFailedLoginAt, &u.LockedUntil); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
+// ClearLockout unlocks an active user and resets their failed login counter.
+func (r *userRepository) ClearLockout(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// buildUserWhere returns the WHERE clause for a user filter. The search term is always $1.
+func buildUserWhere(filter UserFilter) string {
+	where := `(name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%')`
+	if !filter.IncludeDeleted {
+		where += ` AND deleted_at IS NULL`
+	}
+	return where
+}
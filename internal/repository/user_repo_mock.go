@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"inventory-system/internal/model"
 
@@ -30,15 +31,15 @@ func (m *MockUserRepository) Create(ctx context.Context, user *model.User) error
 }
 
 // 3. Tiruan untuk Count (Penting: Return int64!)
-func (m *MockUserRepository) Count(ctx context.Context, search string) (int64, error) {
-	args := m.Called(ctx, search)
+func (m *MockUserRepository) Count(ctx context.Context, filter UserFilter) (int64, error) {
+	args := m.Called(ctx, filter)
 	// Kita cast jadi int64 biar Golang gak ngamuk
 	return args.Get(0).(int64), args.Error(1)
 }
 
 // 4. Tiruan untuk FindAll (Penting: Return []*model.User)
-func (m *MockUserRepository) FindAll(ctx context.Context, limit, offset int, search string) ([]*model.User, error) {
-	args := m.Called(ctx, limit, offset, search)
+func (m *MockUserRepository) FindAll(ctx context.Context, limit, offset int, filter UserFilter) ([]*model.User, error) {
+	args := m.Called(ctx, limit, offset, filter)
 	if args.Get(0) != nil {
 		return args.Get(0).([]*model.User), args.Error(1)
 	}
@@ -60,8 +61,50 @@ func (m *MockUserRepository) Update(ctx context.Context, user *model.User) error
 	return args.Error(0)
 }
 
-// 7. Tiruan untuk Delete
-func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+// 6b. Tiruan untuk UpdatePassword
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, keepLoginID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, id, passwordHash, keepLoginID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// 7. Tiruan untuk SoftDelete
+func (m *MockUserRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+// 8. Tiruan untuk Restore
+func (m *MockUserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// 9. Tiruan untuk RecordFailedLogin
+func (m *MockUserRepository) RecordFailedLogin(ctx context.Context, id uuid.UUID, maxAttempts int, lockout time.Duration) (*model.User, error) {
+	args := m.Called(ctx, id, maxAttempts, lockout)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// 10. Tiruan untuk ResetFailedLogins
+func (m *MockUserRepository) ResetFailedLogins(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// 11. Tiruan untuk FindLockouts
+func (m *MockUserRepository) FindLockouts(ctx context.Context) ([]*model.User, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// 12. Tiruan untuk ClearLockout
+func (m *MockUserRepository) ClearLockout(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
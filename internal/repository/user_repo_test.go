@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// columnDB meniru satu baris tabel: QueryRow cuma mengisi kolom yang benar-benar ada di SELECT,
+// jadi kolom yang lupa di-select ketahuan di sini, bukan baru di database beneran.
+type columnDB struct {
+	PgxIface
+	row map[string]any
+}
+
+func (db *columnDB) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	return &columnRow{columns: selectedColumns(sql), row: db.row}
+}
+
+// selectedColumns mengambil daftar kolom antara SELECT dan FROM.
+func selectedColumns(sql string) []string {
+	upper := strings.ToUpper(sql)
+	start := strings.Index(upper, "SELECT") + len("SELECT")
+	end := strings.Index(upper, "FROM")
+	var columns []string
+	for _, col := range strings.Split(sql[start:end], ",") {
+		columns = append(columns, strings.TrimSpace(col))
+	}
+	return columns
+}
+
+type columnRow struct {
+	columns []string
+	row     map[string]any
+}
+
+func (r *columnRow) Scan(dest ...any) error {
+	if len(dest) != len(r.columns) {
+		return fmt.Errorf("scan: %d columns selected, %d destinations", len(r.columns), len(dest))
+	}
+	for i, col := range r.columns {
+		val, ok := r.row[col]
+		if !ok {
+			return fmt.Errorf("scan: unknown column %q", col)
+		}
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(val))
+	}
+	return nil
+}
+
+func TestUserRepository_FindByIDLoadsLockoutState(t *testing.T) {
+	userID := uuid.New()
+	lastFailed := time.Now().Add(-time.Minute)
+	lockedUntil := time.Now().Add(15 * time.Minute)
+	db := &columnDB{row: map[string]any{
+		"id":                    userID,
+		"name":                  "Staff Satu",
+		"email":                 "staff@example.com",
+		"password_hash":         "hash",
+		"role":                  model.RoleStaff,
+		"failed_login_attempts": 5,
+		"last_failed_login_at":  &lastFailed,
+		"locked_until":          &lockedUntil,
+		"version":               2,
+		"created_at":            time.Now(),
+		"updated_at":            time.Now(),
+		"deleted_at":            (*time.Time)(nil),
+	}}
+
+	// ChangePassword bergantung ke data ini buat menolak akun yang lagi dikunci
+	user, err := NewUserRepository(db).FindByID(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, user.FailedLoginAttempts)
+	assert.Equal(t, &lastFailed, user.LastFailedLoginAt)
+	assert.Equal(t, &lockedUntil, user.LockedUntil)
+}
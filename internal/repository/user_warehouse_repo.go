@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// UserWarehouseRepository manages which warehouses a user is assigned to.
+type UserWarehouseRepository interface {
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.Warehouse, error)
+	Replace(ctx context.Context, userID uuid.UUID, warehouseIDs []uuid.UUID) error
+}
+
+type userWarehouseRepository struct {
+	db PgxIface
+}
+
+// NewUserWarehouseRepository creates and returns a new UserWarehouseRepository instance.
+func NewUserWarehouseRepository(db PgxIface) UserWarehouseRepository {
+	return &userWarehouseRepository{db: db}
+}
+
+// FindByUser lists the active warehouses a user is assigned to, by name.
+func (r *userWarehouseRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.Warehouse, error) {
+	query := `
+		SELECT w.id, w.name, COALESCE(w.location, ''), w.version, w.created_at, w.updated_at
+		FROM user_warehouses uw
+		JOIN warehouses w ON w.id = uw.warehouse_id
+		WHERE uw.user_id = $1 AND w.deleted_at IS NULL
+		ORDER BY w.name ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warehouses []*model.Warehouse
+	for rows.Next() {
+		var w model.Warehouse
+		if err := rows.Scan(&w.ID, &w.Name, &w.Location, &w.Version, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, &w)
+	}
+	return warehouses, rows.Err()
+}
+
+// Replace swaps a user's assignments for warehouseIDs in one transaction. An empty list removes them all.
+func (r *userWarehouseRepository) Replace(ctx context.Context, userID uuid.UUID, warehouseIDs []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_warehouses WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_warehouses (user_id, warehouse_id)
+		SELECT $1, unnest($2::uuid[])
+	`, userID, warehouseIDs)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
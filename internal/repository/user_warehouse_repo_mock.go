@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserWarehouseRepository adalah "Stuntman" untuk UserWarehouseRepository asli kita
+type MockUserWarehouseRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserWarehouseRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*model.Warehouse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Warehouse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserWarehouseRepository) Replace(ctx context.Context, userID uuid.UUID, warehouseIDs []uuid.UUID) error {
+	args := m.Called(ctx, userID, warehouseIDs)
+	return args.Error(0)
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WarehouseRepository defines the contract for warehouse database operations.
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *model.Warehouse) error
+	Count(ctx context.Context, search string) (int64, error)
+	FindAll(ctx context.Context, limit, offset int, search string) ([]*model.Warehouse, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Warehouse, error)
+	Update(ctx context.Context, warehouse *model.Warehouse) error
+	SoftDelete(ctx context.Context, id uuid.UUID, version int) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	HasStockedShelves(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type warehouseRepository struct {
+	db PgxIface
+}
+
+// NewWarehouseRepository creates and returns a new WarehouseRepository instance.
+func NewWarehouseRepository(db PgxIface) WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *model.Warehouse) error {
+	query := `
+		INSERT INTO warehouses (id, name, location)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING version, created_at, updated_at
+	`
+	return r.db.QueryRow(ctx, query,
+		warehouse.ID,
+		warehouse.Name,
+		warehouse.Location,
+	).Scan(&warehouse.Version, &warehouse.CreatedAt, &warehouse.UpdatedAt)
+}
+
+func (r *warehouseRepository) Count(ctx context.Context, search string) (int64, error) {
+	query := `
+		SELECT COUNT(id) FROM warehouses
+		WHERE deleted_at IS NULL
+		  AND (name ILIKE '%' || $1 || '%' OR location ILIKE '%' || $1 || '%')
+		  AND ($2::uuid[] IS NULL OR id = ANY($2))
+	`
+	var total int64
+	err := r.db.QueryRow(ctx, query, search, scopedWarehouseIDs(ctx)).Scan(&total)
+	return total, err
+}
+
+func (r *warehouseRepository) FindAll(ctx context.Context, limit, offset int, search string) ([]*model.Warehouse, error) {
+	query := `
+		SELECT id, name, COALESCE(location, ''), version, created_at, updated_at
+		FROM warehouses
+		WHERE deleted_at IS NULL
+		  AND (name ILIKE '%' || $1 || '%' OR location ILIKE '%' || $1 || '%')
+		  AND ($2::uuid[] IS NULL OR id = ANY($2))
+		ORDER BY name ASC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(ctx, query, search, scopedWarehouseIDs(ctx), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warehouses []*model.Warehouse
+	for rows.Next() {
+		var w model.Warehouse
+		if err := rows.Scan(&w.ID, &w.Name, &w.Location, &w.Version, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, &w)
+	}
+	return warehouses, rows.Err()
+}
+
+// FindByID retrieves an active (non soft-deleted) warehouse in the caller's scope by its UUID.
+func (r *warehouseRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Warehouse, error) {
+	query := `
+		SELECT id, name, COALESCE(location, ''), version, created_at, updated_at
+		FROM warehouses
+		WHERE id = $1 AND deleted_at IS NULL
+		  AND ($2::uuid[] IS NULL OR id = ANY($2))
+	`
+	var w model.Warehouse
+	err := r.db.QueryRow(ctx, query, id, scopedWarehouseIDs(ctx)).Scan(&w.ID, &w.Name, &w.Location, &w.Version, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Update modifies an active warehouse's name and location, provided it is still at warehouse.Version.
+// On success warehouse.Version holds the new version.
+func (r *warehouseRepository) Update(ctx context.Context, warehouse *model.Warehouse) error {
+	query := `
+		UPDATE warehouses
+		SET name = $1, location = NULLIF($2, ''), version = version + 1, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL AND version = $4
+		RETURNING version, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, warehouse.Name, warehouse.Location, warehouse.ID, warehouse.Version).
+		Scan(&warehouse.Version, &warehouse.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return staleOrMissing(ctx, r.db, "warehouses", warehouse.ID)
+	}
+	return err
+}
+
+// SoftDelete marks a warehouse as deleted by setting deleted_at, provided it is still at version.
+func (r *warehouseRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	query := `
+		UPDATE warehouses SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND version = $2
+	`
+	tag, err := r.db.Exec(ctx, query, id, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return staleOrMissing(ctx, r.db, "warehouses", id)
+	}
+	return nil
+}
+
+// Restore clears deleted_at on a soft-deleted warehouse in the caller's scope.
+func (r *warehouseRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE warehouses SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		  AND ($2::uuid[] IS NULL OR id = ANY($2))
+	`
+	tag, err := r.db.Exec(ctx, query, id, scopedWarehouseIDs(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// HasStockedShelves reports whether any active shelf in the warehouse holds items with stock.
+// It locks those shelves and their items first, so inside a transaction no stock can be placed on
+// them, nor items moved onto them, until the transaction ends.
+func (r *warehouseRepository) HasStockedShelves(ctx context.Context, id uuid.UUID) (bool, error) {
+	// Exec reads every row, so every row is locked; an EXISTS would stop at the first one.
+	if _, err := r.db.Exec(ctx, `SELECT 1 FROM shelves WHERE warehouse_id = $1 AND deleted_at IS NULL FOR UPDATE`, id); err != nil {
+		return false, err
+	}
+	lockItems := `
+		SELECT 1 FROM items
+		WHERE deleted_at IS NULL
+		  AND shelf_id IN (SELECT id FROM shelves WHERE warehouse_id = $1 AND deleted_at IS NULL)
+		FOR UPDATE
+	`
+	if _, err := r.db.Exec(ctx, lockItems, id); err != nil {
+		return false, err
+	}
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM items i
+			JOIN shelves s ON s.id = i.shelf_id
+			WHERE s.warehouse_id = $1
+			  AND s.deleted_at IS NULL
+			  AND i.deleted_at IS NULL
+			  AND i.stock > 0
+		)
+	`
+	var exists bool
+	err := r.db.QueryRow(ctx, query, id).Scan(&exists)
+	return exists, err
+}
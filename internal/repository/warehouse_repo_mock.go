@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWarehouseRepository adalah "Stuntman" untuk WarehouseRepository asli kita
+type MockWarehouseRepository struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseRepository) Create(ctx context.Context, warehouse *model.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) Count(ctx context.Context, search string) (int64, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) FindAll(ctx context.Context, limit, offset int, search string) ([]*model.Warehouse, error) {
+	args := m.Called(ctx, limit, offset, search)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*model.Warehouse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWarehouseRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Warehouse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*model.Warehouse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWarehouseRepository) Update(ctx context.Context, warehouse *model.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) SoftDelete(ctx context.Context, id uuid.UUID, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) HasStockedShelves(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// execRecorder mencatat SQL & argumen Exec terakhir; tidak ada baris yang kena.
+type execRecorder struct {
+	PgxIface
+	sql  string
+	args []any
+}
+
+func (db *execRecorder) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	db.sql, db.args = sql, args
+	return pgconn.NewCommandTag("UPDATE 0"), nil
+}
+
+func TestWarehouseRestore_StaysInScope(t *testing.T) {
+	db := &execRecorder{}
+	assigned := []uuid.UUID{uuid.New()}
+	ctx := model.WithWarehouseScope(context.Background(), model.WarehouseScope{IDs: assigned})
+
+	// Gudang di luar scope gak boleh bisa di-restore, sama seperti gak bisa dibaca
+	err := NewWarehouseRepository(db).Restore(ctx, uuid.New())
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, db.sql, "id = ANY($2)")
+	assert.Equal(t, assigned, db.args[1])
+}
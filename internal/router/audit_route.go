@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditRoutes sets up the read-only audit trail endpoints. Only roles with audit:read, by default
+// just super admins, may see them.
+func AuditRoutes(r chi.Router, auditHandler handler.AuditHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(authMiddleware)
+		r.Use(customMiddleware.RequirePermission(model.PermAuditRead))
+
+		r.Get("/", auditHandler.GetAuditEvents)
+		r.Get("/verify", auditHandler.VerifyAuditChain)
+	})
+}
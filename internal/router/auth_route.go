@@ -2,15 +2,40 @@ package router
 
 import (
 	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
 // RegisterAuthRoutes sets up the routing endpoints for authentication.
-func AuthRoutes(r chi.Router, authHandler handler.AuthHandler, authMiddleware func(http.Handler) http.Handler) {
+// loginRateLimit throttles login, single sign-on and password reset attempts per client IP;
+// refreshRateLimit keeps its own count for token refreshes so busy clients don't use up the login budget.
+func AuthRoutes(r chi.Router, authHandler handler.AuthHandler, authMiddleware, loginRateLimit, refreshRateLimit func(http.Handler) http.Handler) {
 	r.Route("/auth", func(r chi.Router) {
-		r.Post("/login", authHandler.Login)
-		r.With(authMiddleware).Post("/logout", authHandler.Logout)
+		r.With(loginRateLimit).Post("/login", authHandler.Login)
+		r.With(loginRateLimit).Post("/login/2fa", authHandler.CompleteTwoFactorLogin)
+		r.With(loginRateLimit).Post("/login/2fa/setup", authHandler.SetupTwoFactorForLogin)
+		r.With(refreshRateLimit).Post("/refresh", authHandler.Refresh)
+
+		// Single sign-on through the OpenID provider: public, and rate limited like login.
+		r.With(loginRateLimit).Get("/oidc/login", authHandler.StartOIDCLogin)
+		r.With(loginRateLimit).Post("/oidc/callback", authHandler.CompleteOIDCLogin)
+
+		// Forgotten password: public, and rate limited like login.
+		r.With(loginRateLimit).Post("/password/forgot", authHandler.ForgotPassword)
+		r.With(loginRateLimit).Post("/password/reset", authHandler.ResetPassword)
+
+		r.With(authMiddleware, customMiddleware.RequireSession).Post("/logout", authHandler.Logout)
+
+		// Session management: every user manages their own logins.
+		r.Route("/sessions", func(r chi.Router) {
+			r.Use(authMiddleware)
+			r.Use(customMiddleware.RequireSession)
+
+			r.Get("/", authHandler.GetSessions)
+			r.Delete("/", authHandler.RevokeAllSessions)
+			r.Delete("/{id}", authHandler.RevokeSession)
+		})
 	})
 }
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CategoryRoutes sets up the routing endpoints for product categories.
+func CategoryRoutes(r chi.Router, categoryHandler handler.CategoryHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/categories", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Get("/", categoryHandler.GetCategories)
+		r.Get("/tree", categoryHandler.GetCategoryTree)
+		r.Get("/{id}", categoryHandler.GetCategory)
+
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequirePermission(model.PermCategoriesWrite))
+
+			r.Post("/", categoryHandler.CreateCategory)
+			r.Put("/{id}", categoryHandler.UpdateCategory)
+			r.Delete("/{id}", categoryHandler.DeleteCategory)
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ItemRoutes sets up the routing endpoints for the item catalogue and its stock ledger.
+// Browsing is open to every authenticated user; changes need the matching permission.
+func ItemRoutes(r chi.Router, itemHandler handler.ItemHandler, stockHandler handler.StockHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/items", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Get("/", itemHandler.GetItems)
+		r.Get("/sku/{sku}", itemHandler.GetItemBySKU)
+		r.Get("/{id}", itemHandler.GetItem)
+		r.Get("/{id}/movements", stockHandler.GetMovements)
+
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequirePermission(model.PermItemsWrite))
+
+			r.Post("/", itemHandler.CreateItem)
+			r.Put("/{id}", itemHandler.UpdateItem)
+			r.Delete("/{id}", itemHandler.DeleteItem)
+		})
+
+		r.With(customMiddleware.RequirePermission(model.PermStockMove)).Post("/{id}/stock/in", stockHandler.StockIn)
+		r.With(customMiddleware.RequirePermission(model.PermStockMove)).Post("/{id}/stock/out", stockHandler.StockOut)
+		r.With(customMiddleware.RequirePermission(model.PermStockAdjust)).Post("/{id}/stock/adjust", stockHandler.AdjustStock)
+	})
+}
@@ -0,0 +1,45 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MeRoutes sets up the self-service endpoints. Every authenticated user, whatever their role,
+// manages their own profile, two-factor authentication and API keys here.
+// loginRateLimit also guards the password change and 2FA, which check the password or a code.
+func MeRoutes(r chi.Router, userHandler handler.UserHandler, authHandler handler.AuthHandler, apiKeyHandler handler.APIKeyHandler, authMiddleware, loginRateLimit func(http.Handler) http.Handler) {
+	r.Route("/me", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Get("/", userHandler.GetMe)
+		r.Put("/", userHandler.UpdateMe)
+
+		// Credentials can only be managed by someone who logged in, never through an API key.
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequireSession)
+
+			r.With(loginRateLimit).Post("/password", userHandler.ChangeMyPassword)
+
+			r.Route("/2fa", func(r chi.Router) {
+				r.Use(loginRateLimit)
+
+				r.Get("/", authHandler.GetTwoFactorStatus)
+				r.Post("/setup", authHandler.SetupTwoFactor)
+				r.Post("/enable", authHandler.EnableTwoFactor)
+				r.Post("/disable", authHandler.DisableTwoFactor)
+				r.Post("/recovery-codes", authHandler.RegenerateRecoveryCodes)
+			})
+
+			r.Route("/api-keys", func(r chi.Router) {
+				r.Get("/", apiKeyHandler.GetAPIKeys)
+				r.Post("/", apiKeyHandler.CreateAPIKey)
+				r.Delete("/{id}", apiKeyHandler.RevokeAPIKey)
+			})
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RoleRoutes sets up the routing endpoints for roles and their permissions.
+func RoleRoutes(r chi.Router, roleHandler handler.RoleHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/roles", func(r chi.Router) {
+		// 1. Every role endpoint requires a valid session.
+		r.Use(authMiddleware)
+
+		// 2. Anyone who assigns roles to users may see what each role grants.
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequirePermission(model.PermRolesManage, model.PermUsersManage))
+
+			r.Get("/", roleHandler.GetRoles)
+			r.Get("/permissions", roleHandler.GetPermissions)
+		})
+
+		// 3. Defining roles needs the roles:manage permission.
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequirePermission(model.PermRolesManage))
+
+			r.Post("/", roleHandler.CreateRole)
+			r.Put("/{name}", roleHandler.UpdateRole)
+			r.Delete("/{name}", roleHandler.DeleteRole)
+		})
+	})
+}
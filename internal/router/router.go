@@ -1,7 +1,10 @@
 package router
 
 import (
+	"time"
+
 	_ "inventory-system/docs"
+	"inventory-system/internal/config"
 	"inventory-system/internal/handler"
 	customMiddleware "inventory-system/internal/middleware"
 	"inventory-system/internal/repository"
@@ -12,16 +15,23 @@ import (
 )
 
 // Setup initializes the main chi router, attaches middlewares, and registers all sub-routes.
-func SetupRoute(handlers *handler.Handler, repos *repository.Repository) *chi.Mux {
+func SetupRoute(handlers *handler.Handler, repos *repository.Repository, cfg config.Config) (*chi.Mux, error) {
+	trustedProxies, err := cfg.App.TrustedProxyPrefixes()
+	if err != nil {
+		return nil, err
+	}
+
 	r := chi.NewRouter()
 
 	// Standard Global Middlewares
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(customMiddleware.RealIP(trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
-	authMiddleware := customMiddleware.Authenticate(repos.Session)
+	authMiddleware := customMiddleware.Authenticate(repos.Session, repos.APIKey)
+	loginRateLimit := customMiddleware.RateLimit(cfg.Auth.LoginRateLimit, time.Minute)
+	refreshRateLimit := customMiddleware.RateLimit(cfg.Auth.LoginRateLimit, time.Minute)
 	// Swagger endpoint
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
@@ -31,10 +41,18 @@ func SetupRoute(handlers *handler.Handler, repos *repository.Repository) *chi.Mu
 	r.Route("/api/v1", func(r chi.Router) {
 
 		// Register module routes here
-		AuthRoutes(r, handlers.Auth, authMiddleware)
+		AuthRoutes(r, handlers.Auth, authMiddleware, loginRateLimit, refreshRateLimit)
 		UserRoutes(r, handlers.User, authMiddleware)
+		MeRoutes(r, handlers.User, handlers.Auth, handlers.APIKey, authMiddleware, loginRateLimit)
+		WarehouseRoutes(r, handlers.Warehouse, handlers.Shelf, authMiddleware)
+		ShelfRoutes(r, handlers.Shelf, authMiddleware)
+		CategoryRoutes(r, handlers.Category, authMiddleware)
+		ItemRoutes(r, handlers.Item, handlers.Stock, authMiddleware)
+		SaleRoutes(r, handlers.Sale, authMiddleware)
+		RoleRoutes(r, handlers.Role, authMiddleware)
+		AuditRoutes(r, handlers.Audit, authMiddleware)
 
 	})
 
-	return r
+	return r, nil
 }
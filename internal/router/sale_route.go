@@ -0,0 +1,32 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SaleRoutes sets up the point-of-sale endpoints. Checking out and processing returns need
+// sales:create, voiding needs sales:void. Whether a user sees everyone's past sales or only their
+// own is decided by the service from the sales:view_all permission.
+func SaleRoutes(r chi.Router, saleHandler handler.SaleHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/sales", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Get("/", saleHandler.GetSales)
+		r.Get("/{id}", saleHandler.GetSale)
+
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequirePermission(model.PermSalesCreate))
+
+			r.Post("/", saleHandler.Checkout)
+			r.Post("/{id}/returns", saleHandler.CreateReturn)
+		})
+
+		r.With(customMiddleware.RequirePermission(model.PermSalesVoid)).Post("/{id}/void", saleHandler.VoidSale)
+	})
+}
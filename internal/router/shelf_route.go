@@ -0,0 +1,21 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ShelfRoutes sets up the flat, read-only shelf lookup endpoints.
+// Shelf mutations live under /warehouses/{id}/shelves (see WarehouseRoutes).
+func ShelfRoutes(r chi.Router, shelfHandler handler.ShelfHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/shelves", func(r chi.Router) {
+		r.Use(authMiddleware)
+
+		r.Get("/", shelfHandler.GetShelves)
+		r.Get("/{id}", shelfHandler.GetShelf)
+		r.Get("/{id}/items", shelfHandler.GetShelfContents)
+	})
+}
@@ -13,20 +13,29 @@ import (
 // UserRoutes sets up the routing endpoints for user management operations.
 func UserRoutes(r chi.Router, userHandler handler.UserHandler, authMiddleware func(http.Handler) http.Handler) {
 	r.Route("/users", func(r chi.Router) {
-		// 1. PRIMARY GATE: Authentication (Check if user is logged in via valid UUID token)
+		// 1. PRIMARY GATE: Authentication (Check if user is logged in via a valid access token)
 		r.Use(authMiddleware)
 
-		// 2. SECONDARY GATE: Authorization (Check if user role is super_admin or admin)
-		// Variadic function allows us to pass multiple allowed roles easily.
-		r.Use(customMiddleware.RequireRole(
-			string(model.RoleSuperAdmin),
-			string(model.RoleAdmin),
-		))
+		// 2. SECONDARY GATE: Authorization (Check if the user's role grants users:manage).
+		// Which target users the caller may touch is decided by the service's authorizer.
+		r.Use(customMiddleware.RequirePermission(model.PermUsersManage))
 
 		// 3. ENDPOINTS: Only accessible if BOTH gates above are passed.
 		r.Post("/", userHandler.CreateUser)
 		r.Get("/", userHandler.GetUsers)
+		r.Get("/{id}", userHandler.GetUser)
 		r.Put("/{id}", userHandler.UpdateUser)
 		r.Delete("/{id}", userHandler.DeleteUser)
+		r.Delete("/{id}/sessions", userHandler.RevokeUserSessions)
+		r.Get("/lockouts", userHandler.GetLockouts)
+		r.Delete("/{id}/lockout", userHandler.ClearLockout)
+		r.Delete("/{id}/2fa", userHandler.ResetTwoFactor)
+		r.Get("/{id}/api-keys", userHandler.GetUserAPIKeys)
+		r.Delete("/{id}/api-keys/{keyID}", userHandler.RevokeUserAPIKey)
+		r.Get("/{id}/warehouses", userHandler.GetUserWarehouses)
+		r.Put("/{id}/warehouses", userHandler.SetUserWarehouses)
+
+		// 4. Restoring a deleted user needs its own permission.
+		r.With(customMiddleware.RequirePermission(model.PermUsersRestore)).Post("/{id}/restore", userHandler.RestoreUser)
 	})
 }
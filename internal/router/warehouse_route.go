@@ -0,0 +1,46 @@
+package router
+
+import (
+	"net/http"
+
+	"inventory-system/internal/handler"
+	customMiddleware "inventory-system/internal/middleware"
+	"inventory-system/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// WarehouseRoutes sets up the routing endpoints for warehouse management.
+func WarehouseRoutes(r chi.Router, warehouseHandler handler.WarehouseHandler, shelfHandler handler.ShelfHandler, authMiddleware func(http.Handler) http.Handler) {
+	r.Route("/warehouses", func(r chi.Router) {
+		// 1. Every warehouse endpoint requires a valid session.
+		r.Use(authMiddleware)
+
+		// 2. Read-only endpoints are available to all authenticated roles.
+		r.Get("/", warehouseHandler.GetWarehouses)
+		r.Get("/{id}", warehouseHandler.GetWarehouse)
+
+		// 3. Mutations need the warehouses:write permission.
+		r.Group(func(r chi.Router) {
+			r.Use(customMiddleware.RequirePermission(model.PermWarehousesWrite))
+
+			r.Post("/", warehouseHandler.CreateWarehouse)
+			r.Put("/{id}", warehouseHandler.UpdateWarehouse)
+			r.Delete("/{id}", warehouseHandler.DeleteWarehouse)
+			r.Post("/{id}/restore", warehouseHandler.RestoreWarehouse)
+		})
+
+		// 4. Shelves (bin locations) nested under their warehouse.
+		r.Route("/{id}/shelves", func(r chi.Router) {
+			r.Get("/", shelfHandler.GetWarehouseShelves)
+
+			r.Group(func(r chi.Router) {
+				r.Use(customMiddleware.RequirePermission(model.PermWarehousesWrite))
+
+				r.Post("/", shelfHandler.CreateShelf)
+				r.Put("/{shelfID}", shelfHandler.UpdateShelf)
+				r.Delete("/{shelfID}", shelfHandler.DeleteShelf)
+			})
+		})
+	})
+}
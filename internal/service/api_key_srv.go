@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// apiKeyPrefixLength is how much of a key is kept in clear text to tell keys apart in listings.
+const apiKeyPrefixLength = 12
+
+var (
+	ErrAPIKeyNotFound = apperror.NotFound("API_KEY_NOT_FOUND", "api key not found")
+
+	ErrAPIKeyScopeConflict = apperror.Validation("API_KEY_SCOPE_CONFLICT", "limit an api key by role or by permissions, not both").
+				WithDetails([]apperror.FieldError{{Field: "permissions", Message: "cannot be combined with role"}})
+	ErrInvalidAPIKeyExpiry = apperror.Validation("INVALID_API_KEY_EXPIRY", "expires_at must be in the future").
+				WithDetails([]apperror.FieldError{{Field: "expires_at", Message: "must be in the future"}})
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, actor model.Actor, req request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]response.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type apiKeyService struct {
+	repo   *repository.Repository
+	authz  Authorizer
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+func NewAPIKeyService(repo *repository.Repository, logger *zap.Logger) APIKeyService {
+	return &apiKeyService{repo: repo, authz: NewAuthorizer(repo, logger), logger: logger, now: time.Now}
+}
+
+// CreateAPIKey mints a key acting as the caller. The plain key is returned once; only its hash is stored.
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, actor model.Actor, req request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.now()) {
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+
+	permissions, err := s.keyPermissions(ctx, actor, req)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, _, err := utils.GenerateToken()
+	if err != nil {
+		s.logger.Error("Failed to generate api key", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	plain := model.APIKeyPrefix + secret
+
+	key := &model.APIKey{
+		BaseSimple:  model.BaseSimple{ID: uuid.New()},
+		UserID:      actor.UserID,
+		Name:        strings.TrimSpace(req.Name),
+		KeyPrefix:   plain[:apiKeyPrefixLength],
+		KeyHash:     utils.HashToken(plain),
+		Permissions: permissions,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if err := s.repo.APIKey.Create(ctx, key); err != nil {
+		s.logger.Error("Failed to insert api key to DB", zap.String("user_id", actor.UserID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("API key created", zap.String("user_id", actor.UserID.String()), zap.String("api_key_id", key.ID.String()))
+
+	return &response.CreatedAPIKeyResponse{APIKeyResponse: response.ToAPIKeyResponse(key), Key: plain}, nil
+}
+
+// GetAPIKeys lists the caller's keys that have not been revoked.
+func (s *apiKeyService) GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]response.APIKeyResponse, error) {
+	keys, err := s.repo.APIKey.FindByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to fetch api keys", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := make([]response.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, response.ToAPIKeyResponse(k))
+	}
+	return res, nil
+}
+
+// RevokeAPIKey disables one of the caller's keys immediately.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.APIKey.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		s.logger.Error("Database error while revoking api key", zap.String("api_key_id", id.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("API key revoked", zap.String("user_id", userID.String()), zap.String("api_key_id", id.String()))
+	return nil
+}
+
+// keyPermissions resolves what the key is limited to. nil means "everything the owner's role has".
+func (s *apiKeyService) keyPermissions(ctx context.Context, actor model.Actor, req request.CreateAPIKeyRequest) (model.Permissions, error) {
+	switch {
+	case req.Role != "" && len(req.Permissions) > 0:
+		return nil, ErrAPIKeyScopeConflict
+	case req.Role != "":
+		role, err := s.repo.Role.FindByName(ctx, model.UserRole(req.Role))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrInvalidUserRole
+			}
+			s.logger.Error("Database error while fetching role", zap.String("role", req.Role), zap.Error(err))
+			return nil, apperror.Internal(err)
+		}
+		// A key limited to a role keeps that role's permissions as they are today.
+		if err := s.authz.CanGrant(actor, role.Permissions); err != nil {
+			return nil, err
+		}
+		return append(model.Permissions{}, role.Permissions...), nil
+	case len(req.Permissions) > 0:
+		permissions, err := parsePermissions(req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authz.CanGrant(actor, permissions); err != nil {
+			return nil, err
+		}
+		return permissions, nil
+	}
+	return nil, nil
+}
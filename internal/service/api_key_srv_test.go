@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCreateAPIKey_Rejects(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		actor   model.Actor
+		req     request.CreateAPIKeyRequest
+		wantErr error
+	}{
+		{"role and permissions together", adminActor(), request.CreateAPIKeyRequest{Name: "scanner", Role: "staff", Permissions: []string{"stock:move"}}, ErrAPIKeyScopeConflict},
+		{"expiry in the past", adminActor(), request.CreateAPIKeyRequest{Name: "scanner", ExpiresAt: &yesterday}, ErrInvalidAPIKeyExpiry},
+		{"more than the owner has", adminActor(), request.CreateAPIKeyRequest{Name: "scanner", Permissions: []string{"roles:manage"}}, ErrRoleOutranksYou},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPIKeyRepo := new(repository.MockAPIKeyRepository)
+			apiKeyService := NewAPIKeyService(&repository.Repository{APIKey: mockAPIKeyRepo}, zap.NewNop()).(*apiKeyService)
+			apiKeyService.now = func() time.Time { return now }
+
+			res, err := apiKeyService.CreateAPIKey(context.Background(), tc.actor, tc.req)
+
+			assert.Nil(t, res)
+			assert.ErrorIs(t, err, tc.wantErr)
+			mockAPIKeyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateAPIKey_StoresOnlyTheHash(t *testing.T) {
+	mockAPIKeyRepo := new(repository.MockAPIKeyRepository)
+	mockRoleRepo := new(repository.MockRoleRepository)
+	apiKeyService := NewAPIKeyService(&repository.Repository{APIKey: mockAPIKeyRepo, Role: mockRoleRepo}, zap.NewNop())
+
+	// Key dibatasi ke role staff: permission-nya di-copy dari role itu
+	mockRoleRepo.On("FindByName", mock.Anything, model.RoleStaff).Return(&model.Role{
+		Name:        model.RoleStaff,
+		Permissions: model.Permissions{model.PermStockMove, model.PermSalesCreate},
+	}, nil)
+
+	var stored *model.APIKey
+	mockAPIKeyRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.APIKey")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*model.APIKey)
+	}).Return(nil)
+
+	actor := adminActor()
+	res, err := apiKeyService.CreateAPIKey(context.Background(), actor, request.CreateAPIKeyRequest{Name: " Gudang A scanner ", Role: "staff"})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(res.Key, model.APIKeyPrefix))
+	assert.Equal(t, utils.HashToken(res.Key), stored.KeyHash)
+	assert.Equal(t, res.Key[:apiKeyPrefixLength], res.KeyPrefix)
+	assert.Equal(t, "Gudang A scanner", res.Name)
+	assert.Equal(t, actor.UserID, stored.UserID)
+	assert.Equal(t, []string{"stock:move", "sales:create"}, res.Permissions)
+	mockAPIKeyRepo.AssertExpectations(t)
+}
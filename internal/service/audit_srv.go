@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidAuditEntity = apperror.Validation("INVALID_AUDIT_ENTITY", "invalid entity_type. Must be user, item, warehouse or stock")
+
+// auditVerifyBatch is how many events VerifyAuditChain reads at a time.
+const auditVerifyBatch = 500
+
+type AuditService interface {
+	GetAuditEvents(ctx context.Context, req request.AuditListQuery) (*response.PaginatedResponse[response.AuditEventResponse], error)
+	VerifyAuditChain(ctx context.Context) (*response.AuditVerificationResponse, error)
+}
+
+type auditService struct {
+	repo   *repository.Repository
+	logger *zap.Logger
+}
+
+func NewAuditService(repo *repository.Repository, logger *zap.Logger) AuditService {
+	return &auditService{repo: repo, logger: logger}
+}
+
+// GetAuditEvents returns the audit trail, newest first.
+func (s *auditService) GetAuditEvents(ctx context.Context, req request.AuditListQuery) (*response.PaginatedResponse[response.AuditEventResponse], error) {
+	reqID := middleware.GetReqID(ctx)
+	req.Normalize()
+
+	filter := repository.AuditFilter{
+		ActorID:    req.ActorID,
+		Action:     model.AuditAction(strings.ToLower(strings.TrimSpace(req.Action))),
+		EntityType: model.AuditEntity(strings.ToLower(strings.TrimSpace(req.EntityType))),
+		EntityID:   req.EntityID,
+		From:       req.From,
+		To:         req.To,
+	}
+	switch filter.EntityType {
+	case "", model.AuditEntityUser, model.AuditEntityItem, model.AuditEntityWarehouse, model.AuditEntityStock:
+	default:
+		return nil, ErrInvalidAuditEntity
+	}
+	if req.From != nil && req.To != nil && req.From.After(*req.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	totalItems, err := s.repo.Audit.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to count audit events", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	events, err := s.repo.Audit.FindAll(ctx, req.Limit, req.Offset(), filter)
+	if err != nil {
+		s.logger.Error("Failed to fetch audit events", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	eventResponses := make([]response.AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		eventResponses = append(eventResponses, response.ToAuditEventResponse(e))
+	}
+
+	result := response.NewPaginatedResponse(eventResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// VerifyAuditChain walks the whole trail in order and stops at the first event whose hash does not
+// match its content, or that does not point at the event before it. Either means a row was edited,
+// removed or inserted outside the application.
+func (s *auditService) VerifyAuditChain(ctx context.Context) (*response.AuditVerificationResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+	result := &response.AuditVerificationResponse{Valid: true}
+
+	var lastSeq int64
+	for {
+		events, err := s.repo.Audit.FindAfter(ctx, lastSeq, auditVerifyBatch)
+		if err != nil {
+			s.logger.Error("Failed to fetch audit events", zap.String("request_id", reqID), zap.Error(err))
+			return nil, apperror.Internal(err)
+		}
+
+		for _, e := range events {
+			if e.PrevHash != result.LastHash || e.ComputeHash() != e.Hash {
+				seq := e.Seq
+				result.Valid = false
+				result.BrokenAtSeq = &seq
+				s.logger.Error("Audit trail has been tampered with",
+					zap.String("request_id", reqID),
+					zap.Int64("seq", seq),
+					zap.String("event_id", e.ID.String()),
+				)
+				return result, nil
+			}
+			result.LastHash = e.Hash
+			result.Checked++
+			lastSeq = e.Seq
+		}
+
+		if len(events) < auditVerifyBatch {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuditDiff(t *testing.T) {
+	before := response.WarehouseResponse{ID: uuid.New(), Name: "Gudang Bandung", Location: "Jl. Asia Afrika", CreatedAt: time.Now()}
+	after := before
+	after.Location = "Jl. Braga"
+	after.UpdatedAt = time.Now()
+
+	t.Run("update only keeps changed fields", func(t *testing.T) {
+		changes, err := auditDiff(before, after)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"location": {"old": "Jl. Asia Afrika", "new": "Jl. Braga"}}`, string(changes))
+	})
+
+	t.Run("create only has new values", func(t *testing.T) {
+		changes, err := auditDiff(nil, &after)
+
+		require.NoError(t, err)
+		var diff map[string]map[string]any
+		require.NoError(t, json.Unmarshal(changes, &diff))
+		assert.Equal(t, map[string]any{"new": "Jl. Braga"}, diff["location"])
+		assert.NotContains(t, diff, "updated_at")
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		// Cuma updated_at yang beda: gak perlu dicatat
+		changes, err := auditDiff(before, response.WarehouseResponse{
+			ID: before.ID, Name: before.Name, Location: before.Location, UpdatedAt: time.Now(),
+		})
+
+		require.NoError(t, err)
+		assert.Nil(t, changes)
+	})
+}
+
+func TestAuditorRecord_TakesOriginFromContext(t *testing.T) {
+	mockAuditRepo := new(repository.MockAuditRepository)
+	auditor := NewAuditor(&repository.Repository{Audit: mockAuditRepo}, zap.NewNop())
+
+	var event *model.AuditEvent
+	mockAuditRepo.On("Append", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+		Return(nil).Once()
+
+	actorID, keyID, itemID := uuid.New(), uuid.New(), uuid.New()
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-42")
+	ctx = model.WithAuditOrigin(ctx, model.AuditOrigin{ActorID: actorID, APIKeyID: &keyID, IPAddress: "203.0.113.7"})
+
+	err := auditor.Record(ctx, model.AuditItemPriceChange, model.AuditEntityItem, itemID,
+		map[string]string{"price": "7500.00"}, map[string]string{"price": "8000.00"})
+
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, &actorID, event.ActorID)
+	assert.Equal(t, &keyID, event.APIKeyID)
+	assert.Equal(t, "203.0.113.7", event.IPAddress)
+	assert.Equal(t, "req-42", event.RequestID)
+	assert.Equal(t, itemID, event.EntityID)
+	assert.JSONEq(t, `{"price": {"old": "7500.00", "new": "8000.00"}}`, string(event.Changes))
+
+	// Update yang gak ngubah apa-apa gak masuk audit trail
+	err = auditor.Record(ctx, model.AuditItemUpdate, model.AuditEntityItem, itemID,
+		map[string]string{"price": "8000.00"}, map[string]string{"price": "8000.00"})
+	assert.NoError(t, err)
+	mockAuditRepo.AssertNumberOfCalls(t, "Append", 1)
+}
+
+// auditChain builds n correctly linked events, as the repository would store them.
+func auditChain(n int) []*model.AuditEvent {
+	events := make([]*model.AuditEvent, 0, n)
+	prevHash := ""
+	for i := range n {
+		e := &model.AuditEvent{
+			ID:         uuid.New(),
+			Seq:        int64(i + 1),
+			Action:     model.AuditWarehouseCreate,
+			EntityType: model.AuditEntityWarehouse,
+			EntityID:   uuid.New(),
+			Changes:    json.RawMessage(`{"name":{"new":"Gudang"}}`),
+			CreatedAt:  time.Date(2026, 3, 1, 8, 0, i, 0, time.UTC),
+			PrevHash:   prevHash,
+		}
+		e.Hash = e.ComputeHash()
+		prevHash = e.Hash
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	t.Run("intact chain", func(t *testing.T) {
+		mockAuditRepo := new(repository.MockAuditRepository)
+		events := auditChain(3)
+		mockAuditRepo.On("FindAfter", mock.Anything, int64(0), auditVerifyBatch).Return(events, nil)
+
+		res, err := NewAuditService(&repository.Repository{Audit: mockAuditRepo}, zap.NewNop()).VerifyAuditChain(context.Background())
+
+		require.NoError(t, err)
+		assert.True(t, res.Valid)
+		assert.Equal(t, int64(3), res.Checked)
+		assert.Nil(t, res.BrokenAtSeq)
+		assert.Equal(t, events[2].Hash, res.LastHash)
+	})
+
+	t.Run("edited event", func(t *testing.T) {
+		mockAuditRepo := new(repository.MockAuditRepository)
+		events := auditChain(3)
+		events[1].Changes = json.RawMessage(`{"name":{"new":"Gudang Palsu"}}`)
+		mockAuditRepo.On("FindAfter", mock.Anything, int64(0), auditVerifyBatch).Return(events, nil)
+
+		res, err := NewAuditService(&repository.Repository{Audit: mockAuditRepo}, zap.NewNop()).VerifyAuditChain(context.Background())
+
+		require.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Equal(t, int64(1), res.Checked)
+		require.NotNil(t, res.BrokenAtSeq)
+		assert.Equal(t, int64(2), *res.BrokenAtSeq)
+	})
+
+	t.Run("removed event", func(t *testing.T) {
+		// Hash event ke-3 masih valid, tapi gak nyambung lagi ke event pertama
+		mockAuditRepo := new(repository.MockAuditRepository)
+		events := auditChain(3)
+		mockAuditRepo.On("FindAfter", mock.Anything, int64(0), auditVerifyBatch).
+			Return([]*model.AuditEvent{events[0], events[2]}, nil)
+
+		res, err := NewAuditService(&repository.Repository{Audit: mockAuditRepo}, zap.NewNop()).VerifyAuditChain(context.Background())
+
+		require.NoError(t, err)
+		assert.False(t, res.Valid)
+		require.NotNil(t, res.BrokenAtSeq)
+		assert.Equal(t, int64(3), *res.BrokenAtSeq)
+	})
+}
+
+func TestGetAuditEvents_RejectsBadFilters(t *testing.T) {
+	// Repository kosong: validasi harus gagal sebelum menyentuh DB.
+	auditService := NewAuditService(&repository.Repository{}, zap.NewNop())
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	_, err := auditService.GetAuditEvents(context.Background(), request.AuditListQuery{EntityType: "shelf"})
+	assert.ErrorIs(t, err, ErrInvalidAuditEntity)
+
+	_, err = auditService.GetAuditEvents(context.Background(), request.AuditListQuery{From: &from, To: &to})
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
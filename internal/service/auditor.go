@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Auditor appends administrative changes to the audit trail. Services call Record once a change is
+// saved, with the entity as it was before and after; who made the change, and from where, is taken
+// from the request context.
+type Auditor interface {
+	// Record stores the difference between before and after, each a response DTO or nil for a
+	// created or deleted entity. An update that changed nothing is not recorded. Callers make the
+	// change and call Record in one TxManager.WithinTx and return its error from there, so a change
+	// whose event cannot be stored is rolled back. The error is already an *apperror.Error.
+	Record(ctx context.Context, action model.AuditAction, entity model.AuditEntity, entityID uuid.UUID, before, after any) error
+}
+
+type auditor struct {
+	repo   *repository.Repository
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+func NewAuditor(repo *repository.Repository, logger *zap.Logger) Auditor {
+	return &auditor{repo: repo, logger: logger, now: time.Now}
+}
+
+// auditIgnoredFields change on every write and would only add noise to the diff.
+var auditIgnoredFields = []string{"version", "created_at", "updated_at"}
+
+func (a *auditor) Record(ctx context.Context, action model.AuditAction, entity model.AuditEntity, entityID uuid.UUID, before, after any) error {
+	reqID := middleware.GetReqID(ctx)
+	fields := []zap.Field{
+		zap.String("request_id", reqID),
+		zap.String("action", string(action)),
+		zap.String("entity_id", entityID.String()),
+	}
+
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		a.logger.Error("Cannot encode audit changes", append(fields, zap.Error(err))...)
+		return apperror.Internal(err)
+	}
+	if changes == nil {
+		return nil
+	}
+
+	event := &model.AuditEvent{
+		ID:         uuid.New(),
+		Action:     action,
+		EntityType: entity,
+		EntityID:   entityID,
+		Changes:    changes,
+		RequestID:  reqID,
+		CreatedAt:  a.now().UTC().Truncate(time.Microsecond),
+	}
+	if origin, ok := model.AuditOriginFrom(ctx); ok {
+		event.ActorID = &origin.ActorID
+		event.APIKeyID = origin.APIKeyID
+		event.IPAddress = origin.IPAddress
+	}
+
+	if err := a.repo.Audit.Append(ctx, event); err != nil {
+		a.logger.Error("Failed to save audit event", append(fields, zap.Error(err))...)
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// auditDiff returns {"field": {"old": ..., "new": ...}} for every top-level JSON field that differs
+// between before and after. A created entity only has "new" values, a deleted one only "old" values.
+// It returns nil when nothing changed.
+func auditDiff(before, after any) (json.RawMessage, error) {
+	oldFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]map[string]any)
+	for name, oldValue := range oldFields {
+		newValue, ok := newFields[name]
+		switch {
+		case newFields == nil:
+			diff[name] = map[string]any{"old": oldValue}
+		case !ok:
+			diff[name] = map[string]any{"old": oldValue, "new": nil}
+		case !reflect.DeepEqual(oldValue, newValue):
+			diff[name] = map[string]any{"old": oldValue, "new": newValue}
+		}
+	}
+	for name, newValue := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			if oldFields == nil {
+				diff[name] = map[string]any{"new": newValue}
+			} else {
+				diff[name] = map[string]any{"old": nil, "new": newValue}
+			}
+		}
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	// Map keys are sorted, so the same change always encodes, and hashes, the same way.
+	return json.Marshal(diff)
+}
+
+// auditFields flattens v into its top-level JSON fields, keeping numbers exact.
+func auditFields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	fields := make(map[string]any)
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	for _, name := range auditIgnoredFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
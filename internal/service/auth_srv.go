@@ -3,12 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"sync"
 	"time"
 
+	"inventory-system/internal/config"
 	"inventory-system/internal/dto/request"
 	"inventory-system/internal/dto/response"
 	"inventory-system/internal/model"
 	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/mailer"
+	"inventory-system/pkg/oidc"
 	"inventory-system/pkg/utils"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -16,30 +23,90 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	ErrInvalidCredentials  = apperror.Unauthorized("INVALID_CREDENTIALS", "invalid email or password")
+	ErrInvalidSessionToken = apperror.Unauthorized("INVALID_SESSION_TOKEN", "invalid session token")
+	ErrInvalidRefreshToken = apperror.Unauthorized("INVALID_REFRESH_TOKEN", "refresh token is invalid or expired")
+	ErrRefreshTokenReused  = apperror.Unauthorized("REFRESH_TOKEN_REUSED", "refresh token was already used; please log in again")
+	ErrSessionNotFound     = apperror.NotFound("SESSION_NOT_FOUND", "session not found")
+	ErrAccountLocked       = apperror.TooManyRequests("ACCOUNT_LOCKED", "account is temporarily locked after too many failed logins")
+	ErrLoginThrottled      = apperror.TooManyRequests("LOGIN_THROTTLED", "too many failed logins; wait before trying again")
+	ErrInvalidResetToken   = apperror.Validation("INVALID_RESET_TOKEN", "reset token is invalid or expired").
+				WithDetails([]apperror.FieldError{{Field: "token", Message: "is invalid or expired"}})
+)
+
+// mailTimeout bounds how long sending one email may take once the request has returned.
+const mailTimeout = 30 * time.Second
+
+// maxLoginDelay caps the progressive delay between failed logins of one account.
+const maxLoginDelay = 30 * time.Second
+
+// dummyPasswordHash is compared against when the email has no account, so a login for an unknown
+// email costs as much bcrypt work as a wrong password.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := utils.HashPassword(uuid.NewString())
+	if err != nil {
+		panic(fmt.Sprintf("hash dummy password: %v", err))
+	}
+	return hash
+})
+
 // AuthService defines the business logic contract for authentication.
 type AuthService interface {
-	Login(ctx context.Context, req request.LoginRequest) (*response.AuthResponse, error)
+	// Login returns either tokens or, when a second factor is needed, a challenge.
+	Login(ctx context.Context, req request.LoginRequest, client model.ClientInfo) (*response.AuthResponse, *response.TwoFactorChallengeResponse, error)
+	CompleteTwoFactorLogin(ctx context.Context, req request.TwoFactorLoginRequest, client model.ClientInfo) (*response.AuthResponse, error)
+	SetupTwoFactorForLogin(ctx context.Context, req request.LoginChallengeRequest) (*response.TwoFactorSetupResponse, error)
+	Refresh(ctx context.Context, req request.RefreshRequest, client model.ClientInfo) (*response.AuthResponse, error)
 	Logout(ctx context.Context, tokenString string) error
+	GetSessions(ctx context.Context, userID, currentLoginID uuid.UUID) ([]response.LoginSessionResponse, error)
+	RevokeSession(ctx context.Context, userID, loginID uuid.UUID) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Single sign-on through the configured OpenID provider.
+	StartOIDCLogin(ctx context.Context) (*response.OIDCLoginResponse, string, error)
+	CompleteOIDCLogin(ctx context.Context, req request.OIDCCallbackRequest, browserBinding string, client model.ClientInfo) (*response.AuthResponse, *response.TwoFactorChallengeResponse, error)
+	ForgotPassword(ctx context.Context, req request.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req request.ResetPasswordRequest) error
+
+	// Two-factor self-service for the authenticated user.
+	GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*response.TwoFactorStatusResponse, error)
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*response.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, req request.TwoFactorCodeRequest) (*response.RecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, req request.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req request.TwoFactorCodeRequest) (*response.RecoveryCodesResponse, error)
 }
 
 // authService is the concrete implementation of AuthService.
 type authService struct {
 	repo   *repository.Repository
+	cfg    config.AuthConfig
+	mailer mailer.Mailer
+	oidc   *oidc.Provider // nil while single sign-on is off
+	audit  Auditor
 	logger *zap.Logger
+	now    func() time.Time
+	async  func(func()) // runs work that must not delay the response; tests run it inline
 }
 
 // NewAuthService creates and returns a new instance of AuthService.
-func NewAuthService(repo *repository.Repository, logger *zap.Logger) AuthService {
+func NewAuthService(repo *repository.Repository, cfg config.AuthConfig, mail mailer.Mailer, logger *zap.Logger) AuthService {
 	return &authService{
 		repo:   repo,
+		cfg:    cfg,
+		mailer: mail,
+		oidc:   newOIDCProvider(cfg),
+		audit:  NewAuditor(repo, logger),
 		logger: logger,
+		now:    time.Now,
+		async:  func(f func()) { go f() },
 	}
 }
 
 // Login handles the core authentication workflow.
-// It checks if the user exists, verifies the password, generates a JWT,
-// and returns the sanitized user data.
-func (s *authService) Login(ctx context.Context, req request.LoginRequest) (*response.AuthResponse, error) {
+// It checks if the user exists and verifies the password. Users with 2FA enabled, or whose role
+// requires it, get a challenge to finish with CompleteTwoFactorLogin; everyone else gets tokens.
+func (s *authService) Login(ctx context.Context, req request.LoginRequest, client model.ClientInfo) (*response.AuthResponse, *response.TwoFactorChallengeResponse, error) {
 	// Extract the Request ID from the context for distributed tracing in logs.
 	reqID := middleware.GetReqID(ctx)
 
@@ -48,67 +115,409 @@ func (s *authService) Login(ctx context.Context, req request.LoginRequest) (*res
 	// 1. Check if a user with the provided email exists in the database.
 	user, err := s.repo.User.FindByEmail(ctx, req.Email)
 	if err != nil {
-		// Log the warning but return a generic error message to prevent email enumeration attacks.
-		s.logger.Warn("Login failed: user not found", zap.String("request_id", reqID), zap.String("email", req.Email))
-		return nil, errors.New("invalid email or password")
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+			return nil, nil, apperror.Internal(err)
+		}
+		// An unknown email goes through the same lockout, throttling and bcrypt work as a real
+		// account (with an ID of uuid.Nil), so the response never reveals whether it exists.
+		s.logger.Warn("Login attempt for unknown email", zap.String("request_id", reqID), zap.String("email", req.Email))
+		user, err = s.repo.LoginFailure.Find(ctx, req.Email)
+		if err != nil {
+			s.logger.Error("Database error while fetching login failures", zap.String("request_id", reqID), zap.Error(err))
+			return nil, nil, apperror.Internal(err)
+		}
+	}
+
+	// 🛡️ GUARD: Akun yang lagi dikunci atau baru gagal login harus nunggu dulu, password-nya belum dicek sama sekali
+	now := s.now()
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		s.logger.Warn("Login rejected: account locked",
+			zap.String("request_id", reqID),
+			zap.String("email", req.Email),
+			zap.Time("locked_until", *user.LockedUntil),
+		)
+		return nil, nil, ErrAccountLocked.WithRetryAfter(user.LockedUntil.Sub(now))
+	}
+	if user.FailedLoginAttempts > 0 && user.LastFailedLoginAt != nil {
+		retryAt := user.LastFailedLoginAt.Add(loginDelay(user.FailedLoginAttempts))
+		if now.Before(retryAt) {
+			s.logger.Warn("Login rejected: throttled after failed attempts",
+				zap.String("request_id", reqID),
+				zap.String("email", req.Email),
+				zap.Int("failed_attempts", user.FailedLoginAttempts),
+			)
+			return nil, nil, ErrLoginThrottled.WithRetryAfter(retryAt.Sub(now))
+		}
 	}
 
 	// 2. Verify if the provided plaintext password matches the hashed password in the database.
-	isValid := utils.CheckPasswordHash(req.Password, user.PasswordHash)
+	var isValid bool
+	if user.ID == uuid.Nil {
+		utils.CheckPasswordHash(req.Password, dummyPasswordHash())
+	} else {
+		isValid = utils.CheckPasswordHash(req.Password, user.PasswordHash)
+	}
 	if !isValid {
 		s.logger.Warn("Login failed: invalid password", zap.String("request_id", reqID), zap.String("email", req.Email))
-		return nil, errors.New("invalid email or password")
+		return nil, nil, s.recordFailedLogin(ctx, reqID, user)
 	}
 
-	// 3. Generate a Stateful UUID Session for the authenticated user.
-	sessionID := uuid.New()
-	expiredAt := time.Now().Add(24 * time.Hour)
+	if user.FailedLoginAttempts > 0 {
+		// Best effort: a stale counter only slows the user's next failed login down.
+		if err := s.repo.User.ResetFailedLogins(ctx, user.ID); err != nil {
+			s.logger.Error("Failed to reset failed login counter", zap.String("request_id", reqID), zap.Error(err))
+		}
+	}
+
+	// 3. A correct password is not enough when a second factor is on or mandatory.
+	challenge, err := s.twoFactorChallenge(ctx, reqID, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	res, err := s.issueLogin(ctx, reqID, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, nil, nil
+}
 
-	newSession := &model.Session{
-		BaseSimple: model.BaseSimple{ID: sessionID},
+// issueLogin starts a new token family for an authenticated user.
+func (s *authService) issueLogin(ctx context.Context, reqID string, user *model.User, client model.ClientInfo) (*response.AuthResponse, error) {
+	// Start a new token family: a short-lived access session plus a rotating refresh token.
+	// The login row remembers where the user signed in from, for the session list.
+	login := &model.LoginSession{
+		BaseSimple: model.BaseSimple{ID: uuid.New()},
 		UserID:     user.ID,
-		Role:       user.Role,
-		ExpiredAt:  expiredAt,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+	}
+	tokens, err := s.newTokenPair(user.ID, user.Role, login.ID)
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate tokens", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
 	}
+	login.ExpiresAt = tokens.refresh.ExpiredAt
 
-	err = s.repo.Session.Create(ctx, newSession)
+	err = s.repo.Session.Issue(ctx, login, tokens.session, tokens.refresh)
 	if err != nil {
 		s.logger.Error("System Error: Failed to save session to DB",
 			zap.String("request_id", reqID),
 			zap.Error(err),
 		)
-		return nil, errors.New("failed to generate authentication token")
+		return nil, apperror.Internal(err)
 	}
 
-	// 4. Map the database User model to the safe UserResponse DTO.
+	// Map the database User model to the safe UserResponse DTO.
 	// This ensures sensitive data like PasswordHash and DeletedAt are not exposed to the client.
-	userResponse := response.ToUserResponse(user)
+	return authResponse(user, tokens), nil
+}
 
-	// 5. Construct and return the final AuthResponse containing the token and user data.
-	return &response.AuthResponse{
-		AccessToken: sessionID.String(),
-		User:        userResponse,
-	}, nil
+// Refresh exchanges a refresh token for a new access session and a new refresh token.
+// Each refresh token works once; replaying a used one revokes the whole family.
+func (s *authService) Refresh(ctx context.Context, req request.RefreshRequest, client model.ClientInfo) (*response.AuthResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	var (
+		user   *model.User
+		tokens *tokenPair
+	)
+	err := s.repo.Session.Rotate(ctx, utils.HashToken(req.RefreshToken), client, func(current *model.RefreshToken) (*model.Session, *model.RefreshToken, error) {
+		if !s.now().Before(current.ExpiredAt) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+
+		// Re-read the user so a role change or deletion takes effect on the next refresh.
+		u, err := s.repo.User.FindByID(ctx, current.UserID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil, ErrInvalidRefreshToken
+			}
+			return nil, nil, err
+		}
+
+		user = u
+		tokens, err = s.newTokenPair(u.ID, u.Role, current.FamilyID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tokens.session, tokens.refresh, nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound), errors.Is(err, ErrInvalidRefreshToken):
+			s.logger.Warn("Refresh failed: invalid or expired token", zap.String("request_id", reqID))
+			return nil, ErrInvalidRefreshToken
+		case errors.Is(err, repository.ErrTokenReused):
+			s.logger.Warn("Refresh token reuse detected, token family revoked", zap.String("request_id", reqID))
+			return nil, ErrRefreshTokenReused
+		}
+		s.logger.Error("System Error: Failed to rotate refresh token", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Session refreshed", zap.String("request_id", reqID), zap.String("user_id", user.ID.String()))
+	return authResponse(user, tokens), nil
 }
 
 func (s *authService) Logout(ctx context.Context, tokenString string) error {
 	reqID := middleware.GetReqID(ctx)
 	s.logger.Info("Attempting logout", zap.String("request_id", reqID))
 
-	// 1. Validasi string token jadi UUID
-	sessionID, err := uuid.Parse(tokenString)
-	if err != nil {
-		s.logger.Warn("Invalid token format for logout", zap.String("request_id", reqID))
-		return errors.New("invalid session token")
+	if tokenString == "" {
+		s.logger.Warn("Empty token for logout", zap.String("request_id", reqID))
+		return ErrInvalidSessionToken
 	}
 
-	// 2. Panggil Repo buat update revoked_at
-	err = s.repo.Session.Revoke(ctx, sessionID)
+	// Panggil Repo buat update revoked_at (sekalian refresh token satu keluarga).
+	// Token cuma disimpan dalam bentuk hash, jadi cari pakai hash-nya.
+	err := s.repo.Session.Revoke(ctx, utils.HashToken(tokenString))
 	if err != nil {
 		s.logger.Error("System Error: Failed to revoke session", zap.Error(err), zap.String("request_id", reqID))
-		return errors.New("failed to process logout")
+		return apperror.Internal(err)
 	}
 
-	s.logger.Info("Logout successful", zap.String("request_id", reqID), zap.String("session_id", sessionID.String()))
+	s.logger.Info("Logout successful", zap.String("request_id", reqID))
 	return nil
 }
+
+// GetSessions lists the user's active logins, flagging the one the request was made with.
+func (s *authService) GetSessions(ctx context.Context, userID, currentLoginID uuid.UUID) ([]response.LoginSessionResponse, error) {
+	logins, err := s.repo.Session.FindActiveLogins(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to fetch sessions", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	sessionResponses := make([]response.LoginSessionResponse, 0, len(logins))
+	for _, l := range logins {
+		sessionResponses = append(sessionResponses, response.ToLoginSessionResponse(l, l.ID == currentLoginID))
+	}
+	return sessionResponses, nil
+}
+
+// RevokeSession signs the user out of one of their own logins.
+func (s *authService) RevokeSession(ctx context.Context, userID, loginID uuid.UUID) error {
+	if err := s.repo.Session.RevokeLogin(ctx, userID, loginID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		s.logger.Error("Failed to revoke session", zap.String("session_id", loginID.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Session revoked", zap.String("user_id", userID.String()), zap.String("session_id", loginID.String()))
+	return nil
+}
+
+// RevokeAllSessions signs the user out everywhere, including the current login. Their API keys
+// keep working; scripts and scanners are not logins.
+func (s *authService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	revoked, err := s.repo.Session.RevokeAllLogins(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to revoke all sessions", zap.String("user_id", userID.String()), zap.Error(err))
+		return 0, apperror.Internal(err)
+	}
+
+	s.logger.Info("All sessions revoked", zap.String("user_id", userID.String()), zap.Int64("revoked", revoked))
+	return revoked, nil
+}
+
+// ForgotPassword emails a single-use reset link to the user with the given email.
+// It succeeds whether or not the email is registered, so the response cannot be used to find accounts;
+// the token is created and mailed in the background for the same reason. A user gets at most one
+// email per PasswordResetCooldown.
+func (s *authService) ForgotPassword(ctx context.Context, req request.ForgotPasswordRequest) error {
+	reqID := middleware.GetReqID(ctx)
+
+	user, err := s.repo.User.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Warn("Password reset requested for unknown email", zap.String("request_id", reqID), zap.String("email", req.Email))
+			return nil
+		}
+		s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Password reset requested", zap.String("request_id", reqID), zap.String("user_id", user.ID.String()))
+
+	bgCtx := context.WithoutCancel(ctx)
+	s.async(func() {
+		ctx, cancel := context.WithTimeout(bgCtx, mailTimeout)
+		defer cancel()
+
+		err := s.sendResetMail(ctx, user)
+		if errors.Is(err, repository.ErrResetRecentlyIssued) {
+			// The last email is still fresh; another one would only flood the inbox.
+			s.logger.Info("Password reset skipped: token issued recently", zap.String("request_id", reqID), zap.String("user_id", user.ID.String()))
+			return
+		}
+		if err != nil {
+			s.logger.Error("Failed to send password reset email", zap.String("request_id", reqID), zap.String("user_id", user.ID.String()), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// sendResetMail issues a new reset token for user and emails it.
+func (s *authService) sendResetMail(ctx context.Context, user *model.User) error {
+	plain, hash, err := utils.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	token := &model.PasswordResetToken{
+		BaseSimple: model.BaseSimple{ID: uuid.New()},
+		UserID:     user.ID,
+		TokenHash:  hash,
+		ExpiresAt:  s.now().Add(s.cfg.PasswordResetTTL),
+	}
+	if err := s.repo.PasswordReset.Create(ctx, token, s.cfg.PasswordResetCooldown); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Inventory System password",
+		Body:    s.resetMailBody(user.Name, plain),
+	})
+}
+
+func (s *authService) resetMailBody(name, token string) string {
+	action := "Use this reset token: " + token
+	if s.cfg.PasswordResetURL != "" {
+		link, err := url.Parse(s.cfg.PasswordResetURL)
+		if err == nil {
+			q := link.Query()
+			q.Set("token", token)
+			link.RawQuery = q.Encode()
+			action = "Open this link to choose a new password: " + link.String()
+		}
+	}
+
+	return fmt.Sprintf("Hi %s,\n\n"+
+		"Someone asked to reset the password of your Inventory System account.\n"+
+		"%s\n\n"+
+		"It works once and expires in %s. If you did not ask for this, ignore this email; your password stays the same.\n",
+		name, action, s.cfg.PasswordResetTTL)
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword and signs the user out everywhere.
+func (s *authService) ResetPassword(ctx context.Context, req request.ResetPasswordRequest) error {
+	reqID := middleware.GetReqID(ctx)
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.String("request_id", reqID), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	userID, err := s.repo.PasswordReset.Reset(ctx, utils.HashToken(req.Token), hashedPassword)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Warn("Password reset failed: invalid or expired token", zap.String("request_id", reqID))
+			return ErrInvalidResetToken
+		}
+		s.logger.Error("System Error: Failed to reset password", zap.String("request_id", reqID), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Password reset, all sessions revoked", zap.String("request_id", reqID), zap.String("user_id", userID.String()))
+	return nil
+}
+
+// recordFailedLogin counts a wrong password against the account, or against the email when it has
+// no account, and returns the error for the client. The failure that crosses the threshold locks it.
+func (s *authService) recordFailedLogin(ctx context.Context, reqID string, user *model.User) error {
+	var state *model.User
+	var err error
+	if user.ID == uuid.Nil {
+		state, err = s.repo.LoginFailure.Record(ctx, user.Email, s.cfg.MaxFailedLogins, s.cfg.LockoutDuration)
+	} else {
+		state, err = s.repo.User.RecordFailedLogin(ctx, user.ID, s.cfg.MaxFailedLogins, s.cfg.LockoutDuration)
+	}
+	if err != nil {
+		s.logger.Error("Failed to record failed login", zap.String("request_id", reqID), zap.Error(err))
+		return ErrInvalidCredentials
+	}
+
+	if state.LockedUntil != nil && s.now().Before(*state.LockedUntil) {
+		s.logger.Warn("Account locked after repeated failed logins",
+			zap.String("request_id", reqID),
+			zap.String("email", user.Email),
+			zap.Int("failed_attempts", state.FailedLoginAttempts),
+			zap.Time("locked_until", *state.LockedUntil),
+		)
+		return ErrAccountLocked.WithRetryAfter(state.LockedUntil.Sub(s.now()))
+	}
+	return ErrInvalidCredentials
+}
+
+// loginDelay is how long an account has to wait after its n-th consecutive failed login:
+// 1s, 2s, 4s, ... up to maxLoginDelay.
+func loginDelay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	if failures > 6 {
+		return maxLoginDelay
+	}
+	return min(time.Second<<(failures-1), maxLoginDelay)
+}
+
+// tokenPair is a freshly issued session and refresh token, with the plain tokens handed to the client.
+// Only the hashes inside session and refresh are stored.
+type tokenPair struct {
+	session      *model.Session
+	refresh      *model.RefreshToken
+	accessToken  string
+	refreshToken string
+}
+
+// newTokenPair builds a session and refresh token for one family, with lifetimes from the config.
+func (s *authService) newTokenPair(userID uuid.UUID, role model.UserRole, familyID uuid.UUID) (*tokenPair, error) {
+	accessToken, accessHash, err := utils.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, refreshHash, err := utils.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	return &tokenPair{
+		session: &model.Session{
+			BaseSimple: model.BaseSimple{ID: uuid.New()},
+			TokenHash:  accessHash,
+			UserID:     userID,
+			FamilyID:   familyID,
+			Role:       role,
+			ExpiredAt:  now.Add(s.cfg.AccessTokenTTL),
+		},
+		refresh: &model.RefreshToken{
+			BaseSimple: model.BaseSimple{ID: uuid.New()},
+			TokenHash:  refreshHash,
+			FamilyID:   familyID,
+			UserID:     userID,
+			ExpiredAt:  now.Add(s.cfg.RefreshTokenTTL),
+		},
+		accessToken:  accessToken,
+		refreshToken: refreshToken,
+	}, nil
+}
+
+func authResponse(user *model.User, tokens *tokenPair) *response.AuthResponse {
+	return &response.AuthResponse{
+		AccessToken:      tokens.accessToken,
+		AccessExpiresAt:  tokens.session.ExpiredAt,
+		RefreshToken:     tokens.refreshToken,
+		RefreshExpiresAt: tokens.refresh.ExpiredAt,
+		User:             response.ToUserResponse(user),
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/internal/config"
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/mailer"
+	"inventory-system/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestAuthService(repos *repository.Repository, now time.Time) *authService {
+	cfg := config.AuthConfig{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		MaxFailedLogins: 5,
+		LockoutDuration: 15 * time.Minute,
+
+		PasswordResetTTL:      30 * time.Minute,
+		PasswordResetCooldown: 2 * time.Minute,
+		PasswordResetURL:      "https://inventory.example.com/reset-password",
+	}
+	s := NewAuthService(repos, cfg, &fakeMailer{}, zap.NewNop()).(*authService)
+	s.now = func() time.Time { return now }
+	s.async = func(f func()) { f() }
+	return s
+}
+
+// fakeMailer records every message instead of sending it.
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestRefresh_RotatesWithinFamily(t *testing.T) {
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockUserRepo := new(repository.MockUserRepository)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	authService := newTestAuthService(&repository.Repository{Session: mockSessionRepo, User: mockUserRepo}, now)
+
+	// Role-nya sudah diganti sejak login; refresh harus pakai role terbaru
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Role: model.RoleAdmin}
+	current := &model.RefreshToken{
+		BaseSimple: model.BaseSimple{ID: uuid.New()},
+		FamilyID:   uuid.New(),
+		UserID:     user.ID,
+		ExpiredAt:  now.Add(time.Hour),
+	}
+	client := model.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "POS Terminal 2"}
+	mockSessionRepo.On("Rotate", mock.Anything, utils.HashToken("old-refresh-token"), client).Return(current, nil)
+	mockUserRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	res, err := authService.Refresh(context.Background(), request.RefreshRequest{RefreshToken: "old-refresh-token"}, client)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "old-refresh-token", res.RefreshToken)
+	assert.NotEqual(t, res.AccessToken, res.RefreshToken)
+	assert.Equal(t, now.Add(15*time.Minute), res.AccessExpiresAt)
+	assert.Equal(t, now.Add(7*24*time.Hour), res.RefreshExpiresAt) // sliding: umur refresh token mulai dari sekarang
+	assert.Equal(t, string(model.RoleAdmin), res.User.Role)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestRefresh_RejectsInvalidTokens(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	refreshID := uuid.New()
+
+	tests := []struct {
+		name    string
+		current *model.RefreshToken
+		repoErr error
+		wantErr error
+	}{
+		{"unknown or revoked", nil, repository.ErrNotFound, ErrInvalidRefreshToken},
+		{"replayed token", nil, repository.ErrTokenReused, ErrRefreshTokenReused},
+		{"expired", &model.RefreshToken{BaseSimple: model.BaseSimple{ID: refreshID}, ExpiredAt: now}, nil, ErrInvalidRefreshToken},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSessionRepo := new(repository.MockSessionRepository)
+			authService := newTestAuthService(&repository.Repository{Session: mockSessionRepo}, now)
+			mockSessionRepo.On("Rotate", mock.Anything, utils.HashToken("refresh-token"), model.ClientInfo{}).Return(tc.current, tc.repoErr)
+
+			res, err := authService.Refresh(context.Background(), request.RefreshRequest{RefreshToken: "refresh-token"}, model.ClientInfo{})
+
+			assert.Nil(t, res)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestGetSessions_FlagsCurrentLogin(t *testing.T) {
+	mockSessionRepo := new(repository.MockSessionRepository)
+	authService := newTestAuthService(&repository.Repository{Session: mockSessionRepo}, time.Now())
+
+	userID := uuid.New()
+	laptop := &model.LoginSession{BaseSimple: model.BaseSimple{ID: uuid.New()}, UserID: userID, UserAgent: "Laptop"}
+	till := &model.LoginSession{BaseSimple: model.BaseSimple{ID: uuid.New()}, UserID: userID, UserAgent: "Kasir 1"}
+	mockSessionRepo.On("FindActiveLogins", mock.Anything, userID).Return([]*model.LoginSession{laptop, till}, nil)
+
+	res, err := authService.GetSessions(context.Background(), userID, till.ID)
+
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.False(t, res[0].Current)
+	assert.True(t, res[1].Current)
+}
+
+func TestRevokeSession_OtherUsersLoginIsNotFound(t *testing.T) {
+	mockSessionRepo := new(repository.MockSessionRepository)
+	authService := newTestAuthService(&repository.Repository{Session: mockSessionRepo}, time.Now())
+
+	userID, loginID := uuid.New(), uuid.New()
+	mockSessionRepo.On("RevokeLogin", mock.Anything, userID, loginID).Return(repository.ErrNotFound)
+
+	err := authService.RevokeSession(context.Background(), userID, loginID)
+
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func TestLogin_StoresOnlyTokenHashes(t *testing.T) {
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	authService := newTestAuthService(&repository.Repository{Session: mockSessionRepo, User: mockUserRepo, TwoFactor: mockTwoFactorRepo}, time.Now())
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "staff@gmail.com", PasswordHash: hash, Role: model.RoleStaff}
+	mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(nil, repository.ErrNotFound)
+
+	var stored *model.Session
+	var storedRefresh *model.RefreshToken
+	mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(2).(*model.Session)
+			storedRefresh = args.Get(3).(*model.RefreshToken)
+		}).Return(nil)
+
+	res, challenge, err := authService.Login(context.Background(), request.LoginRequest{Email: user.Email, Password: "password123"}, model.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+	assert.Equal(t, utils.HashToken(res.AccessToken), stored.TokenHash)
+	assert.Equal(t, utils.HashToken(res.RefreshToken), storedRefresh.TokenHash)
+	assert.NotEqual(t, stored.ID.String(), res.AccessToken) // token bukan lagi primary key
+}
+
+func TestLogin_LockoutAndThrottling(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	at := func(d time.Duration) *time.Time { ts := now.Add(d); return &ts }
+
+	tests := []struct {
+		name           string
+		attempts       int
+		lastFailed     *time.Time
+		lockedUntil    *time.Time
+		password       string
+		recorded       *model.User // state returned by RecordFailedLogin; nil if it must not be called
+		wantErr        error
+		wantRetryAfter int
+	}{
+		{"locked account rejects even the right password", 5, at(-time.Minute), at(10 * time.Minute), "password123", nil, ErrAccountLocked, 600},
+		{"recent failure must wait progressively", 3, at(-2 * time.Second), nil, "password123", nil, ErrLoginThrottled, 2},
+		{"wrong password counts a failure", 1, at(-time.Minute), nil, "wrong", &model.User{FailedLoginAttempts: 2}, ErrInvalidCredentials, 0},
+		{"failure reaching the threshold locks", 4, at(-time.Minute), nil, "wrong", &model.User{FailedLoginAttempts: 5, LockedUntil: at(15 * time.Minute)}, ErrAccountLocked, 900},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockSessionRepo := new(repository.MockSessionRepository)
+			authService := newTestAuthService(&repository.Repository{User: mockUserRepo, Session: mockSessionRepo}, now)
+
+			user := &model.User{
+				BaseModel:           model.BaseModel{ID: uuid.New()},
+				Email:               "kasir@gmail.com",
+				PasswordHash:        hash,
+				FailedLoginAttempts: tc.attempts,
+				LastFailedLoginAt:   tc.lastFailed,
+				LockedUntil:         tc.lockedUntil,
+			}
+			mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+			if tc.recorded != nil {
+				mockUserRepo.On("RecordFailedLogin", mock.Anything, user.ID, 5, 15*time.Minute).Return(tc.recorded, nil)
+			}
+
+			res, challenge, err := authService.Login(context.Background(), request.LoginRequest{Email: user.Email, Password: tc.password}, model.ClientInfo{})
+
+			assert.Nil(t, res)
+			assert.Nil(t, challenge)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantRetryAfter > 0 {
+				assert.Equal(t, apperror.RetryAfter{Seconds: tc.wantRetryAfter}, apperror.As(err).Details)
+			}
+			mockUserRepo.AssertExpectations(t)
+			mockSessionRepo.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLogin_UnknownEmailIsThrottledLikeAnAccount(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { ts := now.Add(d); return &ts }
+	const email = "nobody@gmail.com"
+
+	tests := []struct {
+		name           string
+		state          *model.User // state returned by LoginFailure.Find
+		recorded       *model.User // state returned by LoginFailure.Record; nil if it must not be called
+		wantErr        error
+		wantRetryAfter int
+	}{
+		{"first failure looks like a wrong password", &model.User{Email: email}, &model.User{Email: email, FailedLoginAttempts: 1}, ErrInvalidCredentials, 0},
+		{"recent failure must wait progressively", &model.User{Email: email, FailedLoginAttempts: 3, LastFailedLoginAt: at(-2 * time.Second)}, nil, ErrLoginThrottled, 2},
+		{"failure reaching the threshold locks", &model.User{Email: email, FailedLoginAttempts: 4, LastFailedLoginAt: at(-time.Minute)}, &model.User{Email: email, FailedLoginAttempts: 5, LockedUntil: at(15 * time.Minute)}, ErrAccountLocked, 900},
+		{"locked email is rejected before any password check", &model.User{Email: email, FailedLoginAttempts: 5, LockedUntil: at(10 * time.Minute)}, nil, ErrAccountLocked, 600},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockFailureRepo := new(repository.MockLoginFailureRepository)
+			mockSessionRepo := new(repository.MockSessionRepository)
+			authService := newTestAuthService(&repository.Repository{User: mockUserRepo, LoginFailure: mockFailureRepo, Session: mockSessionRepo}, now)
+
+			mockUserRepo.On("FindByEmail", mock.Anything, email).Return(nil, repository.ErrNotFound)
+			mockFailureRepo.On("Find", mock.Anything, email).Return(tc.state, nil)
+			if tc.recorded != nil {
+				mockFailureRepo.On("Record", mock.Anything, email, 5, 15*time.Minute).Return(tc.recorded, nil)
+			}
+
+			res, challenge, err := authService.Login(context.Background(), request.LoginRequest{Email: email, Password: "password123"}, model.ClientInfo{})
+
+			assert.Nil(t, res)
+			assert.Nil(t, challenge)
+			// Email yang nggak terdaftar harus dapet jawaban yang sama persis kayak akun asli
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantRetryAfter > 0 {
+				assert.Equal(t, apperror.RetryAfter{Seconds: tc.wantRetryAfter}, apperror.As(err).Details)
+			}
+			mockFailureRepo.AssertExpectations(t)
+			mockUserRepo.AssertNotCalled(t, "RecordFailedLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockSessionRepo.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLogin_SuccessResetsFailedLogins(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	authService := newTestAuthService(&repository.Repository{User: mockUserRepo, Session: mockSessionRepo, TwoFactor: mockTwoFactorRepo}, now)
+
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	lastFailed := now.Add(-time.Minute)
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "kasir@gmail.com", PasswordHash: hash, FailedLoginAttempts: 2, LastFailedLoginAt: &lastFailed}
+	mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(nil, repository.ErrNotFound)
+	mockUserRepo.On("ResetFailedLogins", mock.Anything, user.ID).Return(nil)
+	mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, _, err = authService.Login(context.Background(), request.LoginRequest{Email: user.Email, Password: "password123"}, model.ClientInfo{})
+
+	require.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestForgotPassword_DoesNotRevealUnknownEmail(t *testing.T) {
+	mockUserRepo := new(repository.MockUserRepository)
+	mockResetRepo := new(repository.MockPasswordResetRepository)
+	authService := newTestAuthService(&repository.Repository{User: mockUserRepo, PasswordReset: mockResetRepo}, time.Now())
+	mockUserRepo.On("FindByEmail", mock.Anything, "nobody@gmail.com").Return(nil, repository.ErrNotFound)
+
+	err := authService.ForgotPassword(context.Background(), request.ForgotPasswordRequest{Email: "nobody@gmail.com"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, authService.mailer.(*fakeMailer).sent)
+	mockResetRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestForgotPassword_MailsTokenAndStoresOnlyItsHash(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockResetRepo := new(repository.MockPasswordResetRepository)
+	authService := newTestAuthService(&repository.Repository{User: mockUserRepo, PasswordReset: mockResetRepo}, now)
+
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Name: "Staff Satu", Email: "staff@gmail.com"}
+	mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	var stored *model.PasswordResetToken
+	mockResetRepo.On("Create", mock.Anything, mock.Anything, 2*time.Minute).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*model.PasswordResetToken)
+	}).Return(nil)
+
+	err := authService.ForgotPassword(context.Background(), request.ForgotPasswordRequest{Email: user.Email})
+
+	require.NoError(t, err)
+	sent := authService.mailer.(*fakeMailer).sent
+	require.Len(t, sent, 1)
+	assert.Equal(t, user.Email, sent[0].To)
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Equal(t, now.Add(30*time.Minute), stored.ExpiresAt)
+
+	// Token di email harus cocok dengan hash yang disimpan, dan token mentahnya tidak ikut disimpan
+	_, token, found := strings.Cut(sent[0].Body, "reset-password?token=")
+	require.True(t, found)
+	token, _, _ = strings.Cut(token, "\n")
+	assert.Equal(t, utils.HashToken(token), stored.TokenHash)
+}
+
+func TestForgotPassword_SkipsWithinCooldown(t *testing.T) {
+	mockUserRepo := new(repository.MockUserRepository)
+	mockResetRepo := new(repository.MockPasswordResetRepository)
+	authService := newTestAuthService(&repository.Repository{User: mockUserRepo, PasswordReset: mockResetRepo}, time.Now())
+
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Name: "Staff Satu", Email: "staff@gmail.com"}
+	mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	// Email reset terakhir baru dikirim semenit yang lalu
+	mockResetRepo.On("Create", mock.Anything, mock.Anything, 2*time.Minute).Return(repository.ErrResetRecentlyIssued)
+
+	err := authService.ForgotPassword(context.Background(), request.ForgotPasswordRequest{Email: user.Email})
+
+	assert.NoError(t, err)
+	assert.Empty(t, authService.mailer.(*fakeMailer).sent)
+	mockResetRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	mockResetRepo := new(repository.MockPasswordResetRepository)
+	authService := newTestAuthService(&repository.Repository{PasswordReset: mockResetRepo}, time.Now())
+	mockResetRepo.On("Reset", mock.Anything, utils.HashToken("used-token"), mock.Anything).Return(uuid.Nil, repository.ErrNotFound)
+
+	err := authService.ResetPassword(context.Background(), request.ResetPasswordRequest{Token: "used-token", NewPassword: "n3w-Passw0rd"})
+
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+}
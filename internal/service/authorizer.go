@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPermissionDenied = apperror.Forbidden("PERMISSION_DENIED", "forbidden: you do not have the required permission")
+	ErrRoleOutranksYou  = apperror.Forbidden("ROLE_OUTRANKS_YOU", "forbidden: the role has permissions you do not have")
+)
+
+// Authorizer holds the authorization rules that depend on more than the route: what a caller
+// may do to other users and roles. Route-level checks are done by middleware.RequirePermission.
+//
+// The one rule behind every "admin cannot touch super_admin" check: you may only manage a role,
+// or a user holding it, if your own role has every permission that role has. Nobody can grant
+// more than they hold, so an admin can neither edit a super_admin nor promote anyone to one.
+type Authorizer interface {
+	// Require fails with ErrPermissionDenied unless actor holds p.
+	Require(actor model.Actor, p model.Permission) error
+	// CanManageRole fails unless actor's permissions cover those of role. An unknown role is
+	// reported as ErrInvalidUserRole.
+	CanManageRole(ctx context.Context, actor model.Actor, role model.UserRole) error
+	// CanGrant fails unless actor's permissions cover permissions.
+	CanGrant(actor model.Actor, permissions model.Permissions) error
+}
+
+type authorizer struct {
+	repo   *repository.Repository
+	logger *zap.Logger
+}
+
+func NewAuthorizer(repo *repository.Repository, logger *zap.Logger) Authorizer {
+	return &authorizer{repo: repo, logger: logger}
+}
+
+func (a *authorizer) Require(actor model.Actor, p model.Permission) error {
+	if !actor.Can(p) {
+		a.logger.Warn("Permission denied", zap.String("user_id", actor.UserID.String()), zap.String("permission", string(p)))
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func (a *authorizer) CanManageRole(ctx context.Context, actor model.Actor, role model.UserRole) error {
+	target, err := a.repo.Role.FindByName(ctx, role)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidUserRole
+		}
+		a.logger.Error("Database error while fetching role", zap.String("role", string(role)), zap.Error(err))
+		return apperror.Internal(err)
+	}
+	return a.CanGrant(actor, target.Permissions)
+}
+
+func (a *authorizer) CanGrant(actor model.Actor, permissions model.Permissions) error {
+	// 🛡️ GUARD: Gak boleh ngasih (atau ngutak-atik) hak akses yang kita sendiri gak punya
+	if !actor.Permissions.Covers(permissions) {
+		a.logger.Warn("Actor tried to manage a role with more permissions than their own",
+			zap.String("user_id", actor.UserID.String()),
+			zap.String("actor_role", string(actor.Role)),
+		)
+		return ErrRoleOutranksYou
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCategoryNotFound        = apperror.NotFound("CATEGORY_NOT_FOUND", "category not found")
+	ErrParentCategoryNotFound  = apperror.NotFound("PARENT_CATEGORY_NOT_FOUND", "parent category not found")
+	ErrCategoryNameRequired    = apperror.Validation("CATEGORY_NAME_REQUIRED", "category name is required")
+	ErrCategoryNameTaken       = apperror.Conflict("CATEGORY_NAME_TAKEN", "a category with this name already exists under the same parent")
+	ErrCategoryChildNameClash  = apperror.Conflict("CATEGORY_CHILD_NAME_CLASH", "a subcategory has the same name as a category under the parent it would move to; rename it first")
+	ErrCategoryCycle           = apperror.Conflict("CATEGORY_CYCLE", "a category cannot be moved under itself or one of its descendants")
+	ErrInvalidCategoryStrategy = apperror.Validation("INVALID_CATEGORY_STRATEGY", "invalid item strategy. Must be move_to_parent or uncategorize")
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, req request.CreateCategoryRequest) (*response.CategoryResponse, error)
+	GetCategories(ctx context.Context, req request.PaginationQuery) (*response.PaginatedResponse[response.CategoryResponse], error)
+	GetCategoryTree(ctx context.Context) ([]response.CategoryTreeNode, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*response.CategoryResponse, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, version int, req request.UpdateCategoryRequest) (*response.CategoryResponse, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID, version int, strategy model.CategoryItemStrategy) error
+}
+
+type categoryService struct {
+	repo   *repository.Repository
+	logger *zap.Logger
+}
+
+func NewCategoryService(repo *repository.Repository, logger *zap.Logger) CategoryService {
+	return &categoryService{repo: repo, logger: logger}
+}
+
+// CreateCategory adds a new category, optionally under an existing parent.
+func (s *categoryService) CreateCategory(ctx context.Context, req request.CreateCategoryRequest) (*response.CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrCategoryNameRequired
+	}
+
+	if req.ParentID != nil {
+		if err := s.ensureParentExists(ctx, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	category := &model.Category{
+		BaseModel:   model.BaseModel{ID: uuid.New()},
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		ParentID:    req.ParentID,
+	}
+
+	if err := s.repo.Category.Create(ctx, category); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrCategoryNameTaken
+		}
+		s.logger.Error("Failed to insert category to DB", zap.Error(err), zap.String("name", name))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Category created successfully", zap.String("category_id", category.ID.String()))
+
+	res := response.ToCategoryResponse(category)
+	return &res, nil
+}
+
+// GetCategories returns a paginated, flat list of categories.
+func (s *categoryService) GetCategories(ctx context.Context, req request.PaginationQuery) (*response.PaginatedResponse[response.CategoryResponse], error) {
+	req.Normalize()
+
+	totalItems, err := s.repo.Category.Count(ctx, req.Search)
+	if err != nil {
+		s.logger.Error("Failed to count categories", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	categories, err := s.repo.Category.FindPage(ctx, req.Limit, req.Offset(), req.Search)
+	if err != nil {
+		s.logger.Error("Failed to fetch categories", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	categoryResponses := make([]response.CategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		categoryResponses = append(categoryResponses, response.ToCategoryResponse(c))
+	}
+
+	result := response.NewPaginatedResponse(categoryResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// GetCategoryTree returns every category nested under its parent, with item counts per node.
+func (s *categoryService) GetCategoryTree(ctx context.Context) ([]response.CategoryTreeNode, error) {
+	categories, err := s.repo.Category.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to fetch categories", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	counts, err := s.repo.Category.CountItemsByCategory(ctx)
+	if err != nil {
+		s.logger.Error("Failed to count items per category", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	return buildCategoryTree(categories, counts), nil
+}
+
+// GetCategory returns a single category by its UUID.
+func (s *categoryService) GetCategory(ctx context.Context, id uuid.UUID) (*response.CategoryResponse, error) {
+	category, err := s.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := response.ToCategoryResponse(category)
+	return &res, nil
+}
+
+// UpdateCategory replaces the fields of a category still at version, rejecting reparenting that
+// would create a cycle.
+func (s *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, version int, req request.UpdateCategoryRequest) (*response.CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrCategoryNameRequired
+	}
+
+	category, err := s.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	// 🛡️ GUARD: Kategori tidak boleh jadi anak dari dirinya sendiri
+	if req.ParentID != nil && *req.ParentID == id {
+		return nil, ErrCategoryCycle
+	}
+
+	category.Name = name
+	category.Description = strings.TrimSpace(req.Description)
+	category.ParentID = req.ParentID
+
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		// 🛡️ GUARD: Kategori tidak boleh jadi anak dari keturunannya sendiri. Cek & update di bawah lock
+		// yang sama, biar dua pemindahan barengan (A ke B, B ke A) gak lolos dua-duanya.
+		if req.ParentID != nil {
+			if err := s.repo.Category.LockHierarchy(ctx); err != nil {
+				s.logger.Error("Failed to lock the category hierarchy", zap.String("category_id", id.String()), zap.Error(err))
+				return apperror.Internal(err)
+			}
+			if err := s.ensureParentExists(ctx, *req.ParentID); err != nil {
+				return err
+			}
+
+			ancestors, err := s.repo.Category.FindAncestorIDs(ctx, *req.ParentID)
+			if err != nil {
+				s.logger.Error("Failed to resolve category ancestors", zap.String("category_id", id.String()), zap.Error(err))
+				return apperror.Internal(err)
+			}
+			if slices.Contains(ancestors, id) {
+				s.logger.Warn("Rejected category reparent that would create a cycle",
+					zap.String("category_id", id.String()),
+					zap.String("parent_id", req.ParentID.String()),
+				)
+				return ErrCategoryCycle
+			}
+		}
+
+		if err := s.repo.Category.Update(ctx, category); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrCategoryNotFound
+			case errors.Is(err, repository.ErrDuplicate):
+				return ErrCategoryNameTaken
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while updating category", zap.String("category_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Category updated successfully", zap.String("category_id", id.String()))
+
+	res := response.ToCategoryResponse(category)
+	return &res, nil
+}
+
+// DeleteCategory soft-deletes a category at version. Child categories move up to its parent and
+// its items are either moved to the parent or left uncategorised, per strategy.
+func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID, version int, strategy model.CategoryItemStrategy) error {
+	if strategy == "" {
+		strategy = model.CategoryItemsUncategorize
+	}
+	if strategy != model.CategoryItemsUncategorize && strategy != model.CategoryItemsMoveToParent {
+		return ErrInvalidCategoryStrategy
+	}
+
+	if err := s.repo.Category.Delete(ctx, id, version, strategy); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return ErrCategoryNotFound
+		case errors.Is(err, repository.ErrVersionConflict):
+			return ErrVersionMismatch
+		case errors.Is(err, repository.ErrDuplicate):
+			return ErrCategoryChildNameClash
+		}
+		s.logger.Error("Database error while deleting category", zap.String("category_id", id.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Category deleted successfully", zap.String("category_id", id.String()), zap.String("strategy", string(strategy)))
+	return nil
+}
+
+func (s *categoryService) findCategory(ctx context.Context, id uuid.UUID) (*model.Category, error) {
+	category, err := s.repo.Category.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		s.logger.Error("Database error while fetching category", zap.String("category_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	return category, nil
+}
+
+func (s *categoryService) ensureParentExists(ctx context.Context, parentID uuid.UUID) error {
+	if _, err := s.findCategory(ctx, parentID); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return ErrParentCategoryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// buildCategoryTree nests a flat category list and rolls item counts up to every ancestor.
+// Categories whose parent is missing from the list are treated as roots. Sibling order
+// follows the input order.
+func buildCategoryTree(categories []*model.Category, counts map[uuid.UUID]int64) []response.CategoryTreeNode {
+	known := make(map[uuid.UUID]bool, len(categories))
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+
+	childrenOf := make(map[uuid.UUID][]*model.Category)
+	var roots []*model.Category
+	for _, c := range categories {
+		if c.ParentID == nil || !known[*c.ParentID] {
+			roots = append(roots, c)
+			continue
+		}
+		childrenOf[*c.ParentID] = append(childrenOf[*c.ParentID], c)
+	}
+
+	// visited protects against corrupt data containing a cycle.
+	visited := make(map[uuid.UUID]bool, len(categories))
+
+	var build func(c *model.Category) response.CategoryTreeNode
+	build = func(c *model.Category) response.CategoryTreeNode {
+		visited[c.ID] = true
+
+		node := response.CategoryTreeNode{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			ParentID:    c.ParentID,
+			ItemCount:   counts[c.ID],
+			Children:    []response.CategoryTreeNode{},
+		}
+		node.TotalItemCount = node.ItemCount
+
+		for _, child := range childrenOf[c.ID] {
+			if visited[child.ID] {
+				continue
+			}
+			childNode := build(child)
+			node.TotalItemCount += childNode.TotalItemCount
+			node.Children = append(node.Children, childNode)
+		}
+		return node
+	}
+
+	tree := make([]response.CategoryTreeNode, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree
+}
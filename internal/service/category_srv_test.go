@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newCategory(name string, parent *model.Category) *model.Category {
+	c := &model.Category{BaseModel: model.BaseModel{ID: uuid.New(), Version: 1}, Name: name}
+	if parent != nil {
+		c.ParentID = &parent.ID
+	}
+	return c
+}
+
+func TestBuildCategoryTree_NestsAndRollsUpCounts(t *testing.T) {
+	// Beverages > Soft Drinks > Cola, plus a second root "Snacks"
+	beverages := newCategory("Beverages", nil)
+	softDrinks := newCategory("Soft Drinks", beverages)
+	cola := newCategory("Cola", softDrinks)
+	snacks := newCategory("Snacks", nil)
+
+	counts := map[uuid.UUID]int64{
+		beverages.ID:  1,
+		softDrinks.ID: 2,
+		cola.ID:       5,
+	}
+
+	tree := buildCategoryTree([]*model.Category{beverages, softDrinks, cola, snacks}, counts)
+
+	require.Len(t, tree, 2)
+	assert.Equal(t, "Beverages", tree[0].Name)
+	assert.Equal(t, int64(1), tree[0].ItemCount)
+	assert.Equal(t, int64(8), tree[0].TotalItemCount) // 1 + 2 + 5
+
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, int64(7), tree[0].Children[0].TotalItemCount) // 2 + 5
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, "Cola", tree[0].Children[0].Children[0].Name)
+
+	assert.Equal(t, "Snacks", tree[1].Name)
+	assert.Equal(t, int64(0), tree[1].TotalItemCount)
+	assert.NotNil(t, tree[1].Children) // JSON harus [] bukan null
+}
+
+func TestUpdateCategory_RejectsCycle(t *testing.T) {
+	// 1. SETUP
+	mockCategoryRepo := new(repository.MockCategoryRepository)
+	txManager := &repository.FakeTxManager{}
+	categoryService := NewCategoryService(&repository.Repository{Category: mockCategoryRepo, Tx: txManager}, zap.NewNop())
+
+	beverages := newCategory("Beverages", nil)
+	cola := newCategory("Cola", beverages)
+
+	// Pindahin "Beverages" ke bawah "Cola" = lingkaran (Cola adalah keturunan Beverages)
+	mockCategoryRepo.On("FindByID", mock.Anything, beverages.ID).Return(beverages, nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, cola.ID).Return(cola, nil)
+	// Cek lingkaran harus jalan di bawah lock hierarki, di transaksi yang sama dengan Update()
+	mockCategoryRepo.On("LockHierarchy", mock.Anything).Return(nil).Once()
+	mockCategoryRepo.On("FindAncestorIDs", mock.Anything, cola.ID).Return([]uuid.UUID{cola.ID, beverages.ID}, nil)
+
+	// 2. EKSEKUSI
+	res, err := categoryService.UpdateCategory(context.Background(), beverages.ID, 1, request.UpdateCategoryRequest{
+		Name:     "Beverages",
+		ParentID: &cola.ID,
+	})
+
+	// 3. VALIDASI: Update() tidak boleh terpanggil
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrCategoryCycle)
+	assert.Equal(t, 1, txManager.Calls)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestDeleteCategory_VersionConflict(t *testing.T) {
+	mockCategoryRepo := new(repository.MockCategoryRepository)
+	categoryService := NewCategoryService(&repository.Repository{Category: mockCategoryRepo}, zap.NewNop())
+	id := uuid.New()
+
+	// Kategori sudah diubah orang lain sejak versi 3 dibaca
+	mockCategoryRepo.On("Delete", mock.Anything, id, 3, model.CategoryItemsUncategorize).Return(repository.ErrVersionConflict)
+
+	err := categoryService.DeleteCategory(context.Background(), id, 3, "")
+
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	assert.Equal(t, apperror.KindPreconditionFailed, apperror.As(err).Kind)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestDeleteCategory_ChildNameClashesWithParentsCategory(t *testing.T) {
+	mockCategoryRepo := new(repository.MockCategoryRepository)
+	categoryService := NewCategoryService(&repository.Repository{Category: mockCategoryRepo}, zap.NewNop())
+	id := uuid.New()
+
+	// Sub-kategori "Cola" mau naik ke parent yang udah punya "Cola" juga
+	mockCategoryRepo.On("Delete", mock.Anything, id, 1, model.CategoryItemsMoveToParent).Return(repository.ErrDuplicate)
+
+	err := categoryService.DeleteCategory(context.Background(), id, 1, model.CategoryItemsMoveToParent)
+
+	assert.ErrorIs(t, err, ErrCategoryChildNameClash)
+	assert.Equal(t, apperror.KindConflict, apperror.As(err).Kind)
+	mockCategoryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrItemNotFound      = apperror.NotFound("ITEM_NOT_FOUND", "item not found")
+	ErrItemSKURequired   = apperror.Validation("ITEM_SKU_REQUIRED", "item sku is required")
+	ErrItemSKUTooLong    = apperror.Validation("ITEM_SKU_TOO_LONG", "item sku must be at most 50 characters")
+	ErrItemNameRequired  = apperror.Validation("ITEM_NAME_REQUIRED", "item name is required")
+	ErrItemNameTooLong   = apperror.Validation("ITEM_NAME_TOO_LONG", "item name must be at most 255 characters")
+	ErrItemSKUTaken      = apperror.Conflict("ITEM_SKU_TAKEN", "an item with this sku already exists")
+	ErrInvalidItemPrice  = apperror.Validation("INVALID_ITEM_PRICE", "price must be zero or positive with at most 2 decimal places")
+	ErrStockNotEditable  = apperror.Validation("STOCK_NOT_EDITABLE", "stock cannot be edited directly; record a stock movement instead")
+	ErrItemHasStock      = apperror.Conflict("ITEM_HAS_STOCK", "item still has stock; move or adjust the stock to zero first")
+	ErrInvalidItemSort   = apperror.Validation("INVALID_ITEM_SORT", "invalid sort field. Must be name, sku, price or stock")
+	ErrInvalidItemOrder  = apperror.Validation("INVALID_ITEM_ORDER", "invalid sort order. Must be asc or desc")
+	ErrInvalidPriceRange = apperror.Validation("INVALID_PRICE_RANGE", "min_price cannot be greater than max_price")
+	ErrInvalidStockRange = apperror.Validation("INVALID_STOCK_RANGE", "min_stock cannot be greater than max_stock")
+	ErrItemShelfRequired = apperror.Validation("ITEM_SHELF_REQUIRED", "item must be placed on a shelf in one of your warehouses")
+)
+
+// maxItemPrice is the exclusive upper bound of DECIMAL(15, 2): 13 integer digits.
+var maxItemPrice = decimal.New(1, 13)
+
+type ItemService interface {
+	CreateItem(ctx context.Context, req request.CreateItemRequest) (*response.ItemResponse, error)
+	GetItems(ctx context.Context, req request.ItemListQuery) (*response.PaginatedResponse[response.ItemResponse], error)
+	GetItem(ctx context.Context, id uuid.UUID) (*response.ItemResponse, error)
+	GetItemBySKU(ctx context.Context, sku string) (*response.ItemResponse, error)
+	UpdateItem(ctx context.Context, id uuid.UUID, version int, req request.UpdateItemRequest) (*response.ItemResponse, error)
+	DeleteItem(ctx context.Context, id uuid.UUID, version int) error
+}
+
+type itemService struct {
+	repo   *repository.Repository
+	audit  Auditor
+	logger *zap.Logger
+}
+
+func NewItemService(repo *repository.Repository, logger *zap.Logger) ItemService {
+	return &itemService{repo: repo, audit: NewAuditor(repo, logger), logger: logger}
+}
+
+// CreateItem adds a new item to the catalogue with zero stock.
+func (s *itemService) CreateItem(ctx context.Context, req request.CreateItemRequest) (*response.ItemResponse, error) {
+	// 🛡️ GUARD: Stok cuma boleh berubah lewat ledger (stock_logs)
+	if req.Stock != nil {
+		return nil, ErrStockNotEditable
+	}
+
+	item := &model.Item{
+		BaseModel:  model.BaseModel{ID: uuid.New()},
+		SKU:        normalizeSKU(req.SKU),
+		Name:       strings.TrimSpace(req.Name),
+		CategoryID: req.CategoryID,
+		ShelfID:    req.ShelfID,
+		Price:      req.Price,
+	}
+
+	if err := s.validateItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	var res *response.ItemResponse
+	err := withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.Item.Create(ctx, item); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrItemSKUTaken
+			}
+			s.logger.Error("Failed to insert item to DB", zap.Error(err), zap.String("sku", item.SKU))
+			return apperror.Internal(err)
+		}
+
+		// Re-read so the joined category/shelf/warehouse names are filled in.
+		var err error
+		if res, err = s.GetItem(ctx, item.ID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditItemCreate, model.AuditEntityItem, item.ID, nil, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Item created successfully", zap.String("item_id", item.ID.String()), zap.String("sku", item.SKU))
+	return res, nil
+}
+
+// GetItems returns a filtered, sorted and paginated list of items.
+func (s *itemService) GetItems(ctx context.Context, req request.ItemListQuery) (*response.PaginatedResponse[response.ItemResponse], error) {
+	req.Normalize()
+
+	filter, err := toItemFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	totalItems, err := s.repo.Item.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to count items", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	items, err := s.repo.Item.FindAll(ctx, req.Limit, req.Offset(), filter)
+	if err != nil {
+		s.logger.Error("Failed to fetch items", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	itemResponses := make([]response.ItemResponse, 0, len(items))
+	for _, i := range items {
+		itemResponses = append(itemResponses, response.ToItemResponse(i))
+	}
+
+	result := response.NewPaginatedResponse(itemResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// GetItem returns a single item by its UUID.
+func (s *itemService) GetItem(ctx context.Context, id uuid.UUID) (*response.ItemResponse, error) {
+	item, err := s.findItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := response.ToItemResponse(item)
+	return &res, nil
+}
+
+// GetItemBySKU returns a single item by its SKU (case-insensitive).
+func (s *itemService) GetItemBySKU(ctx context.Context, sku string) (*response.ItemResponse, error) {
+	item, err := s.repo.Item.FindBySKU(ctx, normalizeSKU(sku))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		s.logger.Error("Database error while fetching item by sku", zap.String("sku", sku), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := response.ToItemResponse(item)
+	return &res, nil
+}
+
+// UpdateItem replaces the catalogue data of an item still at version. Stock is never touched here.
+func (s *itemService) UpdateItem(ctx context.Context, id uuid.UUID, version int, req request.UpdateItemRequest) (*response.ItemResponse, error) {
+	if req.Stock != nil {
+		s.logger.Warn("Rejected direct stock edit", zap.String("item_id", id.String()))
+		return nil, ErrStockNotEditable
+	}
+
+	item, err := s.findItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	before := response.ToItemResponse(item)
+	item.SKU = normalizeSKU(req.SKU)
+	item.Name = strings.TrimSpace(req.Name)
+	item.CategoryID = req.CategoryID
+	item.ShelfID = req.ShelfID
+	item.Price = req.Price
+
+	if err := s.validateItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	var res *response.ItemResponse
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.Item.Update(ctx, item); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrItemNotFound
+			case errors.Is(err, repository.ErrDuplicate):
+				return ErrItemSKUTaken
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while updating item", zap.String("item_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+
+		var err error
+		if res, err = s.GetItem(ctx, id); err != nil {
+			return err
+		}
+		if err := s.audit.Record(ctx, model.AuditItemUpdate, model.AuditEntityItem, id, before, res); err != nil {
+			return err
+		}
+		// Price changes get an event of their own, so they can be listed without reading every update.
+		if before.Price != res.Price {
+			return s.audit.Record(ctx, model.AuditItemPriceChange, model.AuditEntityItem, id,
+				map[string]string{"price": before.Price}, map[string]string{"price": res.Price})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Item updated successfully", zap.String("item_id", id.String()))
+	return res, nil
+}
+
+// DeleteItem soft-deletes an item at version that no longer has stock on hand.
+func (s *itemService) DeleteItem(ctx context.Context, id uuid.UUID, version int) error {
+	item, err := s.findItem(ctx, id)
+	if err != nil {
+		return err
+	}
+	if item.Version != version {
+		return ErrVersionMismatch
+	}
+
+	if item.Stock > 0 {
+		s.logger.Warn("Attempted to delete an item that still has stock", zap.String("item_id", id.String()), zap.Int("stock", item.Stock))
+		return ErrItemHasStock
+	}
+
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.Item.SoftDelete(ctx, id, version); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrItemNotFound
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while deleting item", zap.String("item_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		return s.audit.Record(ctx, model.AuditItemDelete, model.AuditEntityItem, id, response.ToItemResponse(item), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Item deleted successfully", zap.String("item_id", id.String()))
+	return nil
+}
+
+func (s *itemService) findItem(ctx context.Context, id uuid.UUID) (*model.Item, error) {
+	item, err := s.repo.Item.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		s.logger.Error("Database error while fetching item", zap.String("item_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	return item, nil
+}
+
+// validateItem checks the catalogue fields and that referenced category/shelf exist. The shelf
+// must be in the caller's warehouses.
+func (s *itemService) validateItem(ctx context.Context, item *model.Item) error {
+	switch {
+	case item.SKU == "":
+		return ErrItemSKURequired
+	case len(item.SKU) > 50:
+		return ErrItemSKUTooLong
+	case item.Name == "":
+		return ErrItemNameRequired
+	case len(item.Name) > 255:
+		return ErrItemNameTooLong
+	case item.Price.IsNegative(),
+		!item.Price.Equal(item.Price.Round(2)),
+		item.Price.GreaterThanOrEqual(maxItemPrice):
+		return ErrInvalidItemPrice
+	}
+
+	// 🛡️ GUARD: Barang tanpa rak gak ada di gudang mana pun, jadi gak bakal kelihatan lagi sama user yang gudangnya dibatasi
+	if item.ShelfID == nil && !model.WarehouseScopeFrom(ctx).All {
+		return ErrItemShelfRequired
+	}
+
+	if item.CategoryID != nil {
+		if _, err := s.repo.Category.FindByID(ctx, *item.CategoryID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrCategoryNotFound
+			}
+			s.logger.Error("Database error while fetching category", zap.Error(err))
+			return apperror.Internal(err)
+		}
+	}
+
+	if item.ShelfID != nil {
+		if _, err := s.repo.Shelf.FindByID(ctx, *item.ShelfID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrShelfNotFound
+			}
+			s.logger.Error("Database error while fetching shelf", zap.Error(err))
+			return apperror.Internal(err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeSKU trims and upper-cases a SKU so lookups are case-insensitive.
+func normalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+// toItemFilter validates the list query and converts it into a repository filter.
+func toItemFilter(req request.ItemListQuery) (repository.ItemFilter, error) {
+	filter := repository.ItemFilter{
+		Search:      req.Search,
+		CategoryID:  req.CategoryID,
+		ShelfID:     req.ShelfID,
+		WarehouseID: req.WarehouseID,
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		MinStock:    req.MinStock,
+		MaxStock:    req.MaxStock,
+		SortBy:      strings.ToLower(req.Sort),
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = "name"
+	}
+	if _, ok := repository.ItemSortColumns[filter.SortBy]; !ok {
+		return filter, ErrInvalidItemSort
+	}
+
+	switch strings.ToLower(req.Order) {
+	case "", "asc":
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return filter, ErrInvalidItemOrder
+	}
+
+	if req.MinPrice != nil && req.MaxPrice != nil && req.MinPrice.GreaterThan(*req.MaxPrice) {
+		return filter, ErrInvalidPriceRange
+	}
+	if req.MinStock != nil && req.MaxStock != nil && *req.MinStock > *req.MaxStock {
+		return filter, ErrInvalidStockRange
+	}
+
+	return filter, nil
+}
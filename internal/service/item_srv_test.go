@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestUpdateItem_RejectsDirectStockEdit(t *testing.T) {
+	// Repository kosong: kalau service sampai nyentuh DB, test bakal panic.
+	itemService := NewItemService(&repository.Repository{}, zap.NewNop())
+
+	stock := 999
+	res, err := itemService.UpdateItem(context.Background(), uuid.New(), 1, request.UpdateItemRequest{
+		SKU:   "BEV-COLA-330",
+		Name:  "Cola 330ml",
+		Price: decimal.RequireFromString("7500"),
+		Stock: &stock,
+	})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrStockNotEditable)
+}
+
+func TestCreateItem_ScopedUserMustPickShelf(t *testing.T) {
+	itemService := NewItemService(&repository.Repository{}, zap.NewNop())
+
+	// Staff yang cuma di-assign ke satu gudang gak boleh bikin barang tanpa rak
+	ctx := model.WithWarehouseScope(context.Background(), model.WarehouseScope{IDs: []uuid.UUID{uuid.New()}})
+	res, err := itemService.CreateItem(ctx, request.CreateItemRequest{
+		SKU:   "BEV-COLA-330",
+		Name:  "Cola 330ml",
+		Price: decimal.RequireFromString("7500"),
+	})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrItemShelfRequired)
+}
+
+func TestToItemFilter_ValidatesSortAndRanges(t *testing.T) {
+	minPrice := decimal.RequireFromString("100")
+	maxPrice := decimal.RequireFromString("50")
+
+	cases := map[string]struct {
+		query   request.ItemListQuery
+		wantErr error
+	}{
+		"default sort":   {query: request.ItemListQuery{}},
+		"price desc":     {query: request.ItemListQuery{Sort: "PRICE", Order: "desc"}},
+		"unknown column": {query: request.ItemListQuery{Sort: "password_hash"}, wantErr: ErrInvalidItemSort},
+		"unknown order":  {query: request.ItemListQuery{Order: "sideways"}, wantErr: ErrInvalidItemOrder},
+		"price range":    {query: request.ItemListQuery{MinPrice: &minPrice, MaxPrice: &maxPrice}, wantErr: ErrInvalidPriceRange},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			filter, err := toItemFilter(tc.query)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, repository.ItemSortColumns, filter.SortBy)
+		})
+	}
+}
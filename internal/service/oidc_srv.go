@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-system/internal/config"
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/oidc"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOIDCDisabled         = apperror.NotFound("OIDC_DISABLED", "single sign-on is not configured")
+	ErrInvalidOIDCState     = apperror.Unauthorized("INVALID_OIDC_STATE", "single sign-on login is invalid or expired; please start again")
+	ErrOIDCLoginFailed      = apperror.Unauthorized("OIDC_LOGIN_FAILED", "the identity provider did not confirm your identity")
+	ErrOIDCEmailNotVerified = apperror.Forbidden("OIDC_EMAIL_NOT_VERIFIED", "forbidden: the identity provider has not verified your email address")
+	ErrOIDCNoAccount        = apperror.Forbidden("OIDC_NO_ACCOUNT", "forbidden: there is no account for your email address")
+)
+
+// oidcLoginTTL is how long a user has to sign in at the provider and come back.
+const oidcLoginTTL = 10 * time.Minute
+
+// maxUserNameLength matches users.name.
+const maxUserNameLength = 100
+
+// newOIDCProvider returns the configured OpenID provider, or nil when single sign-on is off.
+func newOIDCProvider(cfg config.AuthConfig) *oidc.Provider {
+	if !cfg.OIDCEnabled() {
+		return nil
+	}
+	return oidc.NewProvider(oidc.Config{
+		IssuerURL:    cfg.OIDCIssuerURL,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       strings.Fields(cfg.OIDCScopes),
+	}, nil)
+}
+
+// StartOIDCLogin remembers a new pending login and returns the provider URL to send the user to,
+// plus a browser binding the caller must hand to the browser (as a cookie) and pass back on callback.
+func (s *authService) StartOIDCLogin(ctx context.Context) (*response.OIDCLoginResponse, string, error) {
+	reqID := middleware.GetReqID(ctx)
+	if s.oidc == nil {
+		return nil, "", ErrOIDCDisabled
+	}
+
+	state, stateHash, err := utils.GenerateToken()
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate OIDC state", zap.String("request_id", reqID), zap.Error(err))
+		return nil, "", apperror.Internal(err)
+	}
+	binding, bindingHash, err := utils.GenerateToken()
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate OIDC browser binding", zap.String("request_id", reqID), zap.Error(err))
+		return nil, "", apperror.Internal(err)
+	}
+	nonce, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, "", apperror.Internal(err)
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, "", apperror.Internal(err)
+	}
+
+	authURL, err := s.oidc.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		s.logger.Error("OpenID provider unavailable", zap.String("request_id", reqID), zap.Error(err))
+		return nil, "", apperror.Internal(err)
+	}
+
+	pending := &model.OIDCLoginState{
+		BaseSimple:   model.BaseSimple{ID: uuid.New()},
+		StateHash:    stateHash,
+		BrowserHash:  bindingHash,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.now().Add(oidcLoginTTL),
+	}
+	if err := s.repo.OIDCState.Create(ctx, pending); err != nil {
+		s.logger.Error("System Error: Failed to save OIDC login state", zap.String("request_id", reqID), zap.Error(err))
+		return nil, "", apperror.Internal(err)
+	}
+
+	return &response.OIDCLoginResponse{AuthorizationURL: authURL, ExpiresAt: pending.ExpiresAt}, binding, nil
+}
+
+// CompleteOIDCLogin redeems the code the provider sent back and signs in the user whose verified
+// email the ID token carries, exactly like a password login: 2FA still applies, and the result is
+// either tokens or a challenge. Unknown emails get an account only when auto-provisioning is on.
+// browserBinding is the value StartOIDCLogin returned, read back from the caller's browser.
+func (s *authService) CompleteOIDCLogin(ctx context.Context, req request.OIDCCallbackRequest, browserBinding string, client model.ClientInfo) (*response.AuthResponse, *response.TwoFactorChallengeResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+	if s.oidc == nil {
+		return nil, nil, ErrOIDCDisabled
+	}
+
+	// 1. The state must belong to a login we started; it works once.
+	pending, err := s.repo.OIDCState.Consume(ctx, utils.HashToken(req.State))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidOIDCState
+		}
+		s.logger.Error("Database error while fetching OIDC login state", zap.String("request_id", reqID), zap.Error(err))
+		return nil, nil, apperror.Internal(err)
+	}
+
+	// 🛡️ GUARD: State dari browser lain (misal link login punya penyerang) gak boleh dipakai
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(browserBinding)), []byte(pending.BrowserHash)) != 1 {
+		s.logger.Warn("OIDC login rejected: state was started in another browser", zap.String("request_id", reqID))
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	// 2. Redeem the code with the PKCE verifier and check the ID token it returns.
+	rawIDToken, err := s.oidc.Exchange(ctx, req.Code, pending.CodeVerifier)
+	if err != nil {
+		s.logger.Warn("OIDC login failed: code exchange", zap.String("request_id", reqID), zap.Error(err))
+		return nil, nil, ErrOIDCLoginFailed
+	}
+	claims, err := s.oidc.Verify(ctx, rawIDToken, pending.Nonce)
+	if err != nil {
+		s.logger.Warn("OIDC login failed: ID token rejected", zap.String("request_id", reqID), zap.Error(err))
+		return nil, nil, ErrOIDCLoginFailed
+	}
+
+	// 🛡️ GUARD: Email yang belum diverifikasi provider gak boleh dipakai buat nyocokin akun
+	if claims.Email == "" || !claims.EmailVerified {
+		s.logger.Warn("OIDC login rejected: email not verified",
+			zap.String("request_id", reqID),
+			zap.String("subject", claims.Subject),
+			zap.String("email", claims.Email),
+		)
+		return nil, nil, ErrOIDCEmailNotVerified
+	}
+
+	// 3. Map the email to a user, creating one if allowed.
+	user, err := s.oidcUser(ctx, reqID, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Info("OIDC login verified", zap.String("request_id", reqID), zap.String("email", user.Email))
+
+	// 4. The provider replaces the password, not the second factor.
+	challenge, err := s.twoFactorChallenge(ctx, reqID, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	res, err := s.issueLogin(ctx, reqID, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, nil, nil
+}
+
+// oidcUser returns the active user with the verified email, provisioning one when configured to.
+// Providers may return the address in any case; users are stored and matched in lower case.
+func (s *authService) oidcUser(ctx context.Context, reqID string, claims *oidc.Claims) (*model.User, error) {
+	email := strings.ToLower(claims.Email)
+	user, err := s.repo.User.FindByEmail(ctx, email)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	if !s.cfg.OIDCAutoProvision {
+		s.logger.Warn("OIDC login rejected: no account", zap.String("request_id", reqID), zap.String("email", email))
+		return nil, ErrOIDCNoAccount
+	}
+
+	role := model.UserRole(s.cfg.OIDCDefaultRole)
+	if _, err := s.repo.Role.FindByName(ctx, role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			err = fmt.Errorf("AUTH_OIDC_DEFAULT_ROLE %q does not exist", role)
+		}
+		s.logger.Error("System Error: Cannot provision OIDC user", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	// The account has no usable password; the user can set one through the forgotten password flow.
+	password, _, err := utils.GenerateToken()
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		s.logger.Error("System Error: Failed to hash password", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	user = &model.User{
+		BaseModel:    model.BaseModel{ID: uuid.New()},
+		Name:         oidcUserName(claims),
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+	}
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.User.Create(ctx, user); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrEmailTaken
+			}
+			s.logger.Error("Database error while provisioning user", zap.String("request_id", reqID), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		return s.audit.Record(ctx, model.AuditUserCreate, model.AuditEntityUser, user.ID, nil, response.ToUserResponse(user))
+	})
+	if err != nil {
+		if !errors.Is(err, ErrEmailTaken) {
+			return nil, err
+		}
+		// A concurrent login created it first, or the email belongs to a deleted user.
+		user, err = s.repo.User.FindByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrOIDCNoAccount
+			}
+			return nil, apperror.Internal(err)
+		}
+		return user, nil
+	}
+
+	s.logger.Info("Provisioned user from OIDC login",
+		zap.String("request_id", reqID),
+		zap.String("user_id", user.ID.String()),
+		zap.String("email", user.Email),
+		zap.String("role", string(role)),
+	)
+	return user, nil
+}
+
+// oidcUserName picks a display name for a provisioned user: the name claim, else the email's local part.
+func oidcUserName(claims *oidc.Claims) string {
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+	if runes := []rune(name); len(runes) > maxUserNameLength {
+		name = string(runes[:maxUserNameLength])
+	}
+	return name
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/oidc/oidctest"
+	"inventory-system/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOIDCAuthService wires the auth service to a stand-in provider. Its clock is the real one,
+// because the provider signs ID tokens with the current time.
+func newTestOIDCAuthService(t *testing.T, repos *repository.Repository, autoProvision bool) (*authService, *oidctest.Issuer) {
+	issuer := oidctest.NewIssuer(t, "inventory")
+	s := newTestAuthService(repos, time.Now())
+	s.cfg.OIDCIssuerURL = issuer.URL
+	s.cfg.OIDCClientID = issuer.ClientID
+	s.cfg.OIDCRedirectURL = "https://inventory.example.com/sso/callback"
+	s.cfg.OIDCScopes = "openid email profile"
+	s.cfg.OIDCAutoProvision = autoProvision
+	s.cfg.OIDCDefaultRole = string(model.RoleStaff)
+	s.oidc = newOIDCProvider(s.cfg)
+	return s, issuer
+}
+
+// startOIDCLogin starts a login and makes the state repository hand the pending login back once.
+// It returns the authorization URL and the browser binding for the callback.
+func startOIDCLogin(t *testing.T, s *authService, stateRepo *repository.MockOIDCStateRepository) (string, string) {
+	var pending *model.OIDCLoginState
+	stateRepo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { pending = args.Get(1).(*model.OIDCLoginState) }).
+		Return(nil).Once()
+
+	res, binding, err := s.StartOIDCLogin(context.Background())
+	require.NoError(t, err)
+	stateRepo.On("Consume", mock.Anything, pending.StateHash).Return(pending, nil).Once()
+	return res.AuthorizationURL, binding
+}
+
+func TestOIDCLogin_SignsInExistingUser(t *testing.T) {
+	mockStateRepo := new(repository.MockOIDCStateRepository)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	mockSessionRepo := new(repository.MockSessionRepository)
+	s, issuer := newTestOIDCAuthService(t, &repository.Repository{
+		OIDCState: mockStateRepo, User: mockUserRepo, TwoFactor: mockTwoFactorRepo, Session: mockSessionRepo,
+	}, false)
+
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "staff@example.com", Role: model.RoleStaff}
+	mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(nil, repository.ErrNotFound)
+	client := model.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "Firefox"}
+	var login *model.LoginSession
+	mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { login = args.Get(1).(*model.LoginSession) }).
+		Return(nil)
+
+	authURL, binding := startOIDCLogin(t, s, mockStateRepo)
+	// Provider boleh balikin email dengan huruf besar, tetap cocok sama akun yang sama
+	code, state := issuer.Authorize(t, authURL, issuer.Claims("", "Staff@Example.com"))
+
+	res, challenge, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, client)
+
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+	assert.NotEmpty(t, res.AccessToken)
+	assert.Equal(t, user.ID, res.User.ID)
+	assert.Equal(t, client.IPAddress, login.IPAddress)
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// State-nya cuma bisa dipakai sekali
+	mockStateRepo.On("Consume", mock.Anything, utils.HashToken(state)).Return(nil, repository.ErrNotFound)
+	_, _, err = s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, client)
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+}
+
+func TestOIDCLogin_TwoFactorStillApplies(t *testing.T) {
+	mockStateRepo := new(repository.MockOIDCStateRepository)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	s, issuer := newTestOIDCAuthService(t, &repository.Repository{
+		OIDCState: mockStateRepo, User: mockUserRepo, TwoFactor: mockTwoFactorRepo,
+	}, false)
+
+	enabledAt := time.Now().Add(-time.Hour)
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "admin@example.com", Role: model.RoleAdmin}
+	mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(&model.TwoFactor{UserID: user.ID, EnabledAt: &enabledAt}, nil)
+	mockTwoFactorRepo.On("CreateChallenge", mock.Anything, mock.Anything).Return(nil)
+
+	authURL, binding := startOIDCLogin(t, s, mockStateRepo)
+	code, state := issuer.Authorize(t, authURL, issuer.Claims("", user.Email))
+
+	res, challenge, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, model.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.Nil(t, res)
+	require.NotNil(t, challenge)
+	assert.True(t, challenge.TwoFactorRequired)
+}
+
+func TestOIDCLogin_AutoProvisionsUnknownEmail(t *testing.T) {
+	mockStateRepo := new(repository.MockOIDCStateRepository)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockRoleRepo := new(repository.MockRoleRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockAuditRepo := new(repository.MockAuditRepository)
+	s, issuer := newTestOIDCAuthService(t, &repository.Repository{
+		OIDCState: mockStateRepo, User: mockUserRepo, Role: mockRoleRepo, TwoFactor: mockTwoFactorRepo, Session: mockSessionRepo,
+		Audit: mockAuditRepo, Tx: &repository.FakeTxManager{},
+	}, true)
+
+	mockUserRepo.On("FindByEmail", mock.Anything, "new.hire@example.com").Return(nil, repository.ErrNotFound)
+	mockRoleRepo.On("FindByName", mock.Anything, model.RoleStaff).Return(&model.Role{Name: model.RoleStaff}, nil)
+	var created *model.User
+	mockUserRepo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*model.User) }).
+		Return(nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+	mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockAuditRepo.On("Append", mock.Anything, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditUserCreate
+	})).Return(nil).Once()
+
+	authURL, binding := startOIDCLogin(t, s, mockStateRepo)
+	claims := issuer.Claims("", "New.Hire@Example.com")
+	claims["name"] = "New Hire"
+	code, state := issuer.Authorize(t, authURL, claims)
+
+	res, _, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, model.ClientInfo{})
+
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, "New Hire", created.Name)
+	assert.Equal(t, "new.hire@example.com", created.Email)
+	assert.Equal(t, model.RoleStaff, created.Role)
+	assert.NotEmpty(t, created.PasswordHash)
+	assert.Equal(t, created.ID, res.User.ID)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestOIDCLogin_Rejections(t *testing.T) {
+	t.Run("unknown email without auto-provisioning", func(t *testing.T) {
+		mockStateRepo := new(repository.MockOIDCStateRepository)
+		mockUserRepo := new(repository.MockUserRepository)
+		s, issuer := newTestOIDCAuthService(t, &repository.Repository{OIDCState: mockStateRepo, User: mockUserRepo}, false)
+		mockUserRepo.On("FindByEmail", mock.Anything, "stranger@example.com").Return(nil, repository.ErrNotFound)
+
+		authURL, binding := startOIDCLogin(t, s, mockStateRepo)
+		code, state := issuer.Authorize(t, authURL, issuer.Claims("", "stranger@example.com"))
+		_, _, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, model.ClientInfo{})
+
+		assert.ErrorIs(t, err, ErrOIDCNoAccount)
+		mockUserRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		mockStateRepo := new(repository.MockOIDCStateRepository)
+		mockUserRepo := new(repository.MockUserRepository)
+		s, issuer := newTestOIDCAuthService(t, &repository.Repository{OIDCState: mockStateRepo, User: mockUserRepo}, true)
+
+		claims := issuer.Claims("", "admin@example.com")
+		claims["email_verified"] = false
+		authURL, binding := startOIDCLogin(t, s, mockStateRepo)
+		code, state := issuer.Authorize(t, authURL, claims)
+		_, _, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, model.ClientInfo{})
+
+		assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+		mockUserRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("code from another login", func(t *testing.T) {
+		mockStateRepo := new(repository.MockOIDCStateRepository)
+		s, issuer := newTestOIDCAuthService(t, &repository.Repository{OIDCState: mockStateRepo}, false)
+
+		// Kode dari login lain gak cocok sama PKCE verifier login ini
+		otherURL, _ := startOIDCLogin(t, s, mockStateRepo)
+		otherCode, _ := issuer.Authorize(t, otherURL, issuer.Claims("", "staff@example.com"))
+		authURL, binding := startOIDCLogin(t, s, mockStateRepo)
+		_, state := issuer.Authorize(t, authURL, issuer.Claims("", "staff@example.com"))
+		_, _, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: otherCode, State: state}, binding, model.ClientInfo{})
+
+		assert.ErrorIs(t, err, ErrOIDCLoginFailed)
+	})
+
+	t.Run("state started in another browser", func(t *testing.T) {
+		mockStateRepo := new(repository.MockOIDCStateRepository)
+		mockUserRepo := new(repository.MockUserRepository)
+		s, issuer := newTestOIDCAuthService(t, &repository.Repository{OIDCState: mockStateRepo, User: mockUserRepo}, false)
+
+		// Penyerang mulai login sendiri, lalu nyodorin code + state-nya ke browser korban
+		authURL, attackerBinding := startOIDCLogin(t, s, mockStateRepo)
+		code, state := issuer.Authorize(t, authURL, issuer.Claims("", "attacker@example.com"))
+		for _, binding := range []string{"", attackerBinding + "x"} {
+			mockStateRepo.On("Consume", mock.Anything, utils.HashToken(state)).Return(&model.OIDCLoginState{BrowserHash: utils.HashToken(attackerBinding)}, nil).Once()
+			_, _, err := s.CompleteOIDCLogin(context.Background(), request.OIDCCallbackRequest{Code: code, State: state}, binding, model.ClientInfo{})
+			assert.ErrorIs(t, err, ErrInvalidOIDCState)
+		}
+		mockUserRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newTestAuthService(&repository.Repository{}, time.Now())
+
+		_, _, err := s.StartOIDCLogin(context.Background())
+		assert.ErrorIs(t, err, ErrOIDCDisabled)
+		assert.Equal(t, apperror.KindNotFound, apperror.As(err).Kind)
+	})
+}
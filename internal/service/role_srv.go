@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRoleNotFound     = apperror.NotFound("ROLE_NOT_FOUND", "role not found")
+	ErrRoleNameTaken    = apperror.Conflict("ROLE_NAME_TAKEN", "a role with this name already exists")
+	ErrRoleInUse        = apperror.Conflict("ROLE_IN_USE", "role is still assigned to users; reassign them first")
+	ErrSystemRole       = apperror.Forbidden("SYSTEM_ROLE", "forbidden: built-in roles cannot be deleted")
+	ErrSuperAdminLocked = apperror.Forbidden("SUPER_ADMIN_LOCKED", "forbidden: the super_admin role always has every permission")
+
+	ErrInvalidRoleName = apperror.Validation("INVALID_ROLE_NAME", "role name must start with a letter and use only lowercase letters, digits and underscores").
+				WithDetails([]apperror.FieldError{{Field: "name", Message: "must start with a letter and use only lowercase letters, digits and underscores"}})
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type RoleService interface {
+	GetRoles(ctx context.Context) ([]response.RoleResponse, error)
+	GetPermissions() []response.PermissionResponse
+	CreateRole(ctx context.Context, req request.CreateRoleRequest, actor model.Actor) (*response.RoleResponse, error)
+	UpdateRole(ctx context.Context, name model.UserRole, req request.UpdateRoleRequest, actor model.Actor) (*response.RoleResponse, error)
+	DeleteRole(ctx context.Context, name model.UserRole, actor model.Actor) error
+}
+
+type roleService struct {
+	repo   *repository.Repository
+	authz  Authorizer
+	logger *zap.Logger
+}
+
+func NewRoleService(repo *repository.Repository, logger *zap.Logger) RoleService {
+	return &roleService{repo: repo, authz: NewAuthorizer(repo, logger), logger: logger}
+}
+
+// GetRoles lists every role with its permissions.
+func (s *roleService) GetRoles(ctx context.Context) ([]response.RoleResponse, error) {
+	roles, err := s.repo.Role.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to fetch roles", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := make([]response.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		res = append(res, response.ToRoleResponse(role))
+	}
+	return res, nil
+}
+
+// GetPermissions lists every permission that can be granted, sorted by name.
+func (s *roleService) GetPermissions() []response.PermissionResponse {
+	res := make([]response.PermissionResponse, 0, len(model.PermissionDescriptions))
+	for p, description := range model.PermissionDescriptions {
+		res = append(res, response.PermissionResponse{Name: string(p), Description: description})
+	}
+	slices.SortFunc(res, func(a, b response.PermissionResponse) int { return strings.Compare(a.Name, b.Name) })
+	return res
+}
+
+// CreateRole defines a custom role. The requester can only grant permissions they hold themselves.
+func (s *roleService) CreateRole(ctx context.Context, req request.CreateRoleRequest, actor model.Actor) (*response.RoleResponse, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, ErrInvalidRoleName
+	}
+
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authz.CanGrant(actor, permissions); err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Name:        model.UserRole(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Permissions: permissions,
+	}
+	if err := s.repo.Role.Create(ctx, role); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrRoleNameTaken
+		}
+		s.logger.Error("Failed to insert role to DB", zap.String("role", req.Name), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Role created successfully", zap.String("role", req.Name), zap.String("created_by", actor.UserID.String()))
+
+	res := response.ToRoleResponse(role)
+	return &res, nil
+}
+
+// UpdateRole replaces a role's description and permissions. The change applies to every user
+// holding the role from their next request. super_admin cannot be changed.
+func (s *roleService) UpdateRole(ctx context.Context, name model.UserRole, req request.UpdateRoleRequest, actor model.Actor) (*response.RoleResponse, error) {
+	// 🛡️ GUARD: super_admin harus selalu punya semua akses, biar gak ada yang kekunci di luar
+	if name == model.RoleSuperAdmin {
+		return nil, ErrSuperAdminLocked
+	}
+
+	permissions, err := parsePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The requester must outrank the role both before and after the change.
+	if err := s.authz.CanManageRole(ctx, actor, name); err != nil {
+		if errors.Is(err, ErrInvalidUserRole) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	if err := s.authz.CanGrant(actor, permissions); err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Permissions: permissions,
+	}
+	if err := s.repo.Role.Update(ctx, role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		s.logger.Error("Database error while updating role", zap.String("role", string(name)), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Role updated successfully", zap.String("role", string(name)), zap.String("updated_by", actor.UserID.String()))
+
+	res := response.ToRoleResponse(role)
+	return &res, nil
+}
+
+// DeleteRole removes a custom role that no user holds any more.
+func (s *roleService) DeleteRole(ctx context.Context, name model.UserRole, actor model.Actor) error {
+	role, err := s.repo.Role.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRoleNotFound
+		}
+		s.logger.Error("Database error while fetching role", zap.String("role", string(name)), zap.Error(err))
+		return apperror.Internal(err)
+	}
+	if role.IsSystem {
+		return ErrSystemRole
+	}
+	if err := s.authz.CanGrant(actor, role.Permissions); err != nil {
+		return err
+	}
+
+	if err := s.repo.Role.Delete(ctx, name); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return ErrRoleNotFound
+		case errors.Is(err, repository.ErrRoleInUse):
+			return ErrRoleInUse
+		}
+		s.logger.Error("Database error while deleting role", zap.String("role", string(name)), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Role deleted successfully", zap.String("role", string(name)), zap.String("deleted_by", actor.UserID.String()))
+	return nil
+}
+
+// parsePermissions validates requested permission names and drops duplicates.
+func parsePermissions(raw []string) (model.Permissions, error) {
+	var fieldErrors []apperror.FieldError
+	permissions := make(model.Permissions, 0, len(raw))
+	for i, name := range raw {
+		p := model.Permission(strings.TrimSpace(name))
+		if !p.Valid() {
+			fieldErrors = append(fieldErrors, apperror.FieldError{
+				Field:   "permissions[" + strconv.Itoa(i) + "]",
+				Message: "is not a known permission",
+			})
+			continue
+		}
+		if !permissions.Has(p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, apperror.Validation("UNKNOWN_PERMISSION", "unknown permission; see GET /roles/permissions").WithDetails(fieldErrors)
+	}
+	return permissions, nil
+}
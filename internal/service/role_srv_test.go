@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// superAdminActor dan adminActor meniru permission bawaan dari migration 0014 dan 0015.
+func superAdminActor() model.Actor {
+	return model.Actor{
+		UserID: uuid.New(),
+		Role:   model.RoleSuperAdmin,
+		Permissions: model.Permissions{
+			model.PermUsersManage, model.PermUsersRestore, model.PermRolesManage,
+			model.PermWarehousesWrite, model.PermWarehousesAll, model.PermCategoriesWrite, model.PermItemsWrite,
+			model.PermStockMove, model.PermStockAdjust,
+			model.PermSalesCreate, model.PermSalesViewAll, model.PermSalesVoid,
+		},
+	}
+}
+
+func adminActor() model.Actor {
+	return model.Actor{
+		UserID: uuid.New(),
+		Role:   model.RoleAdmin,
+		Permissions: model.Permissions{
+			model.PermUsersManage,
+			model.PermWarehousesWrite, model.PermWarehousesAll, model.PermCategoriesWrite, model.PermItemsWrite,
+			model.PermStockMove, model.PermStockAdjust,
+			model.PermSalesCreate, model.PermSalesViewAll, model.PermSalesVoid,
+		},
+	}
+}
+
+func TestCreateRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   model.Actor
+		req     request.CreateRoleRequest
+		wantErr error
+	}{
+		{"invalid name", superAdminActor(), request.CreateRoleRequest{Name: "Gudang Lead", Permissions: []string{"stock:move"}}, ErrInvalidRoleName},
+		{"cannot grant what you do not have", adminActor(), request.CreateRoleRequest{Name: "role_admin", Permissions: []string{"roles:manage"}}, ErrRoleOutranksYou},
+		{"success drops duplicates", superAdminActor(), request.CreateRoleRequest{Name: "warehouse_lead", Permissions: []string{"stock:move", "stock:adjust", "stock:move"}}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRoleRepo := new(repository.MockRoleRepository)
+			roleService := NewRoleService(&repository.Repository{Role: mockRoleRepo}, zap.NewNop())
+
+			if tc.wantErr == nil {
+				mockRoleRepo.On("Create", mock.Anything, mock.MatchedBy(func(role *model.Role) bool {
+					return role.Name == "warehouse_lead" && len(role.Permissions) == 2
+				})).Return(nil)
+			}
+
+			res, err := roleService.CreateRole(context.Background(), tc.req, tc.actor)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockRoleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"stock:move", "stock:adjust"}, res.Permissions)
+			mockRoleRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateRole_UnknownPermission(t *testing.T) {
+	roleService := NewRoleService(&repository.Repository{Role: new(repository.MockRoleRepository)}, zap.NewNop())
+
+	_, err := roleService.CreateRole(context.Background(), request.CreateRoleRequest{
+		Name:        "auditor",
+		Permissions: []string{"sales:view_all", "sales:delete"},
+	}, superAdminActor())
+
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "UNKNOWN_PERMISSION", appErr.Code)
+	assert.Equal(t, []apperror.FieldError{{Field: "permissions[1]", Message: "is not a known permission"}}, appErr.Details)
+}
+
+func TestUpdateRole_SuperAdminIsLocked(t *testing.T) {
+	mockRoleRepo := new(repository.MockRoleRepository)
+	roleService := NewRoleService(&repository.Repository{Role: mockRoleRepo}, zap.NewNop())
+
+	// Super Admin sendiri pun gak boleh ngurangin permission super_admin
+	_, err := roleService.UpdateRole(context.Background(), model.RoleSuperAdmin, request.UpdateRoleRequest{
+		Permissions: []string{"stock:move"},
+	}, superAdminActor())
+
+	assert.ErrorIs(t, err, ErrSuperAdminLocked)
+	mockRoleRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeleteRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      *model.Role
+		deleteErr error
+		wantErr   error
+	}{
+		{"system role", &model.Role{Name: model.RoleStaff, IsSystem: true}, nil, ErrSystemRole},
+		{"still assigned", &model.Role{Name: "warehouse_lead"}, repository.ErrRoleInUse, ErrRoleInUse},
+		{"success", &model.Role{Name: "warehouse_lead"}, nil, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRoleRepo := new(repository.MockRoleRepository)
+			roleService := NewRoleService(&repository.Repository{Role: mockRoleRepo}, zap.NewNop())
+
+			mockRoleRepo.On("FindByName", mock.Anything, tc.role.Name).Return(tc.role, nil)
+			if !tc.role.IsSystem {
+				mockRoleRepo.On("Delete", mock.Anything, tc.role.Name).Return(tc.deleteErr)
+			}
+
+			err := roleService.DeleteRole(context.Background(), tc.role.Name, superAdminActor())
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRoleRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// maxCartLines caps how many lines a single checkout may contain.
+const maxCartLines = 100
+
+var (
+	ErrSaleNotFound        = apperror.NotFound("SALE_NOT_FOUND", "sale not found")
+	ErrEmptyCart           = apperror.Validation("EMPTY_CART", "cart must contain at least one item")
+	ErrCartTooLarge        = apperror.Validation("CART_TOO_LARGE", "cart cannot contain more than 100 lines")
+	ErrInvalidCartLine     = apperror.Validation("INVALID_CART_LINE", "each cart line needs exactly one of item_id or sku")
+	ErrInvalidCartQuantity = apperror.Validation("INVALID_CART_QUANTITY", "cart quantities must be greater than zero")
+	ErrSaleTotalTooLarge   = apperror.Validation("SALE_TOTAL_TOO_LARGE", "sale total exceeds the maximum amount")
+	ErrSaleVoided          = apperror.Conflict("SALE_VOIDED", "sale has been voided")
+	ErrSaleVoidExpired     = apperror.Conflict("SALE_VOID_EXPIRED", "a sale can only be voided on the day it was made")
+	ErrVoidReasonRequired  = apperror.Validation("VOID_REASON_REQUIRED", "a reason is required to void a sale")
+	ErrEmptyReturn         = apperror.Validation("EMPTY_RETURN", "return must contain at least one line")
+	ErrInvalidReturnLine   = apperror.Validation("INVALID_RETURN_LINE", "return quantities must be greater than zero")
+	ErrSaleItemNotFound    = apperror.NotFound("SALE_ITEM_NOT_FOUND", "sale line not found on this sale")
+	ErrReturnExceedsSold   = apperror.Conflict("RETURN_EXCEEDS_SOLD", "cannot return more than was sold")
+)
+
+type SaleService interface {
+	Checkout(ctx context.Context, userID uuid.UUID, req request.CheckoutRequest) (*response.SaleResponse, error)
+	GetSales(ctx context.Context, req request.SaleListQuery, actor model.Actor) (*response.PaginatedResponse[response.SaleResponse], error)
+	GetSale(ctx context.Context, id uuid.UUID, actor model.Actor) (*response.SaleResponse, error)
+	VoidSale(ctx context.Context, id, userID uuid.UUID, req request.VoidSaleRequest) (*response.SaleResponse, error)
+	CreateReturn(ctx context.Context, saleID, userID uuid.UUID, req request.CreateReturnRequest) (*response.SaleReturnResponse, error)
+}
+
+type saleService struct {
+	repo   *repository.Repository
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+func NewSaleService(repo *repository.Repository, logger *zap.Logger) SaleService {
+	return &saleService{repo: repo, logger: logger, now: time.Now}
+}
+
+// Checkout turns a cart into a sale. Prices come from the catalogue at the moment of sale and
+// stock is decremented atomically with the sale and its ledger rows.
+func (s *saleService) Checkout(ctx context.Context, userID uuid.UUID, req request.CheckoutRequest) (*response.SaleResponse, error) {
+	lines, err := s.resolveCart(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	sale := &model.Sale{
+		BaseSimple: model.BaseSimple{ID: uuid.New()},
+		UserID:     userID,
+		Items:      lines,
+	}
+
+	if err := s.repo.Sale.Checkout(ctx, sale, priceSale); err != nil {
+		switch {
+		case errors.Is(err, ErrItemNotFound), errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrSaleTotalTooLarge):
+			s.logger.Warn("Checkout rejected", zap.String("user_id", userID.String()), zap.Error(err))
+			return nil, err
+		}
+		s.logger.Error("Database error during checkout", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Sale completed",
+		zap.String("sale_id", sale.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("total_amount", sale.TotalAmount.StringFixed(2)),
+		zap.Int("lines", len(sale.Items)),
+	)
+
+	// Re-read so the cashier name and line order match GET /sales/{id}.
+	return s.findSale(ctx, sale.ID)
+}
+
+// GetSales returns a paginated list of sales, newest first. Without sales:view_all a user only
+// ever sees their own sales.
+func (s *saleService) GetSales(ctx context.Context, req request.SaleListQuery, actor model.Actor) (*response.PaginatedResponse[response.SaleResponse], error) {
+	req.Normalize()
+
+	if req.From != nil && req.To != nil && req.From.After(*req.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	filter := repository.SaleFilter{UserID: req.UserID, From: req.From, To: req.To}
+	if !actor.Can(model.PermSalesViewAll) {
+		filter.UserID = &actor.UserID
+	}
+
+	totalItems, err := s.repo.Sale.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to count sales", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	sales, err := s.repo.Sale.FindAll(ctx, req.Limit, req.Offset(), filter)
+	if err != nil {
+		s.logger.Error("Failed to fetch sales", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	saleResponses := make([]response.SaleResponse, 0, len(sales))
+	for _, sale := range sales {
+		saleResponses = append(saleResponses, response.ToSaleResponse(sale))
+	}
+
+	result := response.NewPaginatedResponse(saleResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// GetSale returns a single sale with its lines. Without sales:view_all a user can only open their own sales.
+func (s *saleService) GetSale(ctx context.Context, id uuid.UUID, actor model.Actor) (*response.SaleResponse, error) {
+	res, err := s.findSale(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🛡️ GUARD: Tanpa sales:view_all cuma boleh lihat transaksinya sendiri
+	if !actor.Can(model.PermSalesViewAll) && res.UserID != actor.UserID {
+		return nil, ErrSaleNotFound
+	}
+	return res, nil
+}
+
+// VoidSale cancels a completed sale on the day it was made and restocks everything not yet returned.
+func (s *saleService) VoidSale(ctx context.Context, id, userID uuid.UUID, req request.VoidSaleRequest) (*response.SaleResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrVoidReasonRequired
+	}
+
+	now := s.now()
+	err := s.repo.Sale.Void(ctx, id, userID, reason, func(sale *model.Sale) error {
+		return checkVoidable(sale, now)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrSaleNotFound
+		case errors.Is(err, ErrSaleVoided), errors.Is(err, ErrSaleVoidExpired):
+			s.logger.Warn("Sale void rejected", zap.String("sale_id", id.String()), zap.Error(err))
+			return nil, err
+		}
+		s.logger.Error("Database error while voiding sale", zap.String("sale_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Sale voided", zap.String("sale_id", id.String()), zap.String("voided_by", userID.String()))
+	return s.findSale(ctx, id)
+}
+
+// CreateReturn takes back part of one or more sale lines, refunds them at the sale's unit price
+// and restocks the goods.
+func (s *saleService) CreateReturn(ctx context.Context, saleID, userID uuid.UUID, req request.CreateReturnRequest) (*response.SaleReturnResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, ErrEmptyReturn
+	}
+
+	ret := &model.SaleReturn{
+		BaseSimple: model.BaseSimple{ID: uuid.New()},
+		SaleID:     saleID,
+		UserID:     userID,
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+
+	err := s.repo.Sale.CreateReturn(ctx, ret, func(sale *model.Sale) error {
+		return buildReturn(ret, sale, req.Items)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrSaleNotFound
+		case errors.Is(err, ErrInvalidReturnLine),
+			errors.Is(err, ErrSaleItemNotFound),
+			errors.Is(err, ErrReturnExceedsSold),
+			errors.Is(err, ErrSaleVoided):
+			s.logger.Warn("Sale return rejected", zap.String("sale_id", saleID.String()), zap.Error(err))
+			return nil, err
+		}
+		s.logger.Error("Database error while recording sale return", zap.String("sale_id", saleID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Sale return recorded",
+		zap.String("sale_id", saleID.String()),
+		zap.String("return_id", ret.ID.String()),
+		zap.String("refund_amount", ret.RefundAmount.StringFixed(2)),
+	)
+
+	res := response.ToSaleReturnResponse(ret)
+	return &res, nil
+}
+
+func (s *saleService) findSale(ctx context.Context, id uuid.UUID) (*response.SaleResponse, error) {
+	sale, err := s.repo.Sale.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSaleNotFound
+		}
+		s.logger.Error("Database error while fetching sale", zap.String("sale_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := response.ToSaleResponse(sale)
+	return &res, nil
+}
+
+// resolveCart validates the cart, looks SKUs up and merges repeated items into one line each,
+// keeping the order in which items first appear.
+func (s *saleService) resolveCart(ctx context.Context, cart []request.CartItemRequest) ([]model.SaleItem, error) {
+	switch {
+	case len(cart) == 0:
+		return nil, ErrEmptyCart
+	case len(cart) > maxCartLines:
+		return nil, ErrCartTooLarge
+	}
+
+	var lines []model.SaleItem
+	lineOf := make(map[uuid.UUID]int, len(cart))
+
+	for _, entry := range cart {
+		hasSKU := normalizeSKU(entry.SKU) != ""
+		if (entry.ItemID == nil) == !hasSKU {
+			return nil, ErrInvalidCartLine
+		}
+		if entry.Quantity <= 0 {
+			return nil, ErrInvalidCartQuantity
+		}
+
+		itemID, err := s.resolveCartItem(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if idx, ok := lineOf[itemID]; ok {
+			if entry.Quantity > math.MaxInt32-lines[idx].Quantity {
+				return nil, ErrInvalidCartQuantity
+			}
+			lines[idx].Quantity += entry.Quantity
+			continue
+		}
+
+		lineOf[itemID] = len(lines)
+		lines = append(lines, model.SaleItem{
+			BaseSimple: model.BaseSimple{ID: uuid.New()},
+			ItemID:     itemID,
+			Quantity:   entry.Quantity,
+		})
+	}
+
+	return lines, nil
+}
+
+func (s *saleService) resolveCartItem(ctx context.Context, entry request.CartItemRequest) (uuid.UUID, error) {
+	if entry.ItemID != nil {
+		return *entry.ItemID, nil
+	}
+
+	sku := normalizeSKU(entry.SKU)
+	item, err := s.repo.Item.FindBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return uuid.Nil, fmt.Errorf("%w: sku %s", ErrItemNotFound, sku)
+		}
+		s.logger.Error("Database error while resolving cart sku", zap.String("sku", sku), zap.Error(err))
+		return uuid.Nil, apperror.Internal(err)
+	}
+	return item.ID, nil
+}
+
+// priceSale snapshots each line's unit price from the locked item rows, checks stock and computes
+// the subtotals and total with exact decimal arithmetic. It runs inside the checkout transaction.
+func priceSale(sale *model.Sale, locked map[uuid.UUID]*model.Item) error {
+	total := decimal.Zero
+
+	for i := range sale.Items {
+		line := &sale.Items[i]
+
+		item, ok := locked[line.ItemID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrItemNotFound, line.ItemID)
+		}
+		// 🛡️ GUARD: Jangan jual melebihi stok yang ada
+		if item.Stock < line.Quantity {
+			return fmt.Errorf("%w: %s has %d left", ErrInsufficientStock, item.SKU, item.Stock)
+		}
+
+		line.SKU = item.SKU
+		line.Name = item.Name
+		line.UnitPrice = item.Price
+		line.Subtotal = item.Price.Mul(decimal.NewFromInt(int64(line.Quantity)))
+		total = total.Add(line.Subtotal)
+	}
+
+	if total.GreaterThanOrEqual(maxItemPrice) {
+		return ErrSaleTotalTooLarge
+	}
+
+	sale.TotalAmount = total
+	return nil
+}
+
+// checkVoidable allows voiding only completed sales made on the same calendar day as now
+// (in now's location).
+func checkVoidable(sale *model.Sale, now time.Time) error {
+	if sale.Status == model.SaleVoided {
+		return ErrSaleVoided
+	}
+
+	saleYear, saleMonth, saleDay := sale.CreatedAt.In(now.Location()).Date()
+	year, month, day := now.Date()
+	if saleYear != year || saleMonth != month || saleDay != day {
+		return ErrSaleVoidExpired
+	}
+	return nil
+}
+
+// buildReturn fills ret's lines and refund from the locked sale, merging repeated lines and
+// refusing to return more of a line than is still outstanding. It runs inside the return transaction.
+func buildReturn(ret *model.SaleReturn, sale *model.Sale, requested []request.ReturnItemRequest) error {
+	if sale.Status == model.SaleVoided {
+		return ErrSaleVoided
+	}
+
+	lines := make(map[uuid.UUID]*model.SaleItem, len(sale.Items))
+	for i := range sale.Items {
+		lines[sale.Items[i].ID] = &sale.Items[i]
+	}
+
+	ret.Items = nil
+	ret.RefundAmount = decimal.Zero
+	returnLineOf := make(map[uuid.UUID]int, len(requested))
+
+	for _, entry := range requested {
+		if entry.Quantity <= 0 {
+			return ErrInvalidReturnLine
+		}
+		line, ok := lines[entry.SaleItemID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrSaleItemNotFound, entry.SaleItemID)
+		}
+
+		idx, seen := returnLineOf[line.ID]
+		if !seen {
+			idx = len(ret.Items)
+			returnLineOf[line.ID] = idx
+			ret.Items = append(ret.Items, model.SaleReturnItem{
+				ID:         uuid.New(),
+				SaleItemID: line.ID,
+				ItemID:     line.ItemID,
+				SKU:        line.SKU,
+				Name:       line.Name,
+			})
+		}
+		returnLine := &ret.Items[idx]
+
+		// 🛡️ GUARD: Tidak boleh retur melebihi yang dijual (dikurangi retur sebelumnya)
+		if entry.Quantity > line.Quantity-line.ReturnedQuantity-returnLine.Quantity {
+			return fmt.Errorf("%w: %s has %d left to return", ErrReturnExceedsSold, line.SKU, line.Quantity-line.ReturnedQuantity)
+		}
+		returnLine.Quantity += entry.Quantity
+	}
+
+	for i := range ret.Items {
+		returnLine := &ret.Items[i]
+		returnLine.RefundAmount = lines[returnLine.SaleItemID].UnitPrice.Mul(decimal.NewFromInt(int64(returnLine.Quantity)))
+		ret.RefundAmount = ret.RefundAmount.Add(returnLine.RefundAmount)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestPriceSale_UsesExactDecimalArithmetic(t *testing.T) {
+	gum := &model.Item{BaseModel: model.BaseModel{ID: uuid.New()}, SKU: "GUM-MINT", Stock: 10, Price: decimal.RequireFromString("0.10")}
+	cola := &model.Item{BaseModel: model.BaseModel{ID: uuid.New()}, SKU: "BEV-COLA-330", Stock: 5, Price: decimal.RequireFromString("7500.35")}
+
+	sale := &model.Sale{Items: []model.SaleItem{
+		{ItemID: gum.ID, Quantity: 3},
+		{ItemID: cola.ID, Quantity: 2},
+	}}
+
+	err := priceSale(sale, map[uuid.UUID]*model.Item{gum.ID: gum, cola.ID: cola})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.30", sale.Items[0].Subtotal.StringFixed(2))
+	assert.Equal(t, "15000.70", sale.Items[1].Subtotal.StringFixed(2))
+	assert.Equal(t, "15001.00", sale.TotalAmount.StringFixed(2))
+	assert.Equal(t, "BEV-COLA-330", sale.Items[1].SKU)
+}
+
+func TestPriceSale_RejectsOversellAndMissingItems(t *testing.T) {
+	cola := &model.Item{BaseModel: model.BaseModel{ID: uuid.New()}, SKU: "BEV-COLA-330", Stock: 1, Price: decimal.RequireFromString("7500")}
+	locked := map[uuid.UUID]*model.Item{cola.ID: cola}
+
+	err := priceSale(&model.Sale{Items: []model.SaleItem{{ItemID: cola.ID, Quantity: 2}}}, locked)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	err = priceSale(&model.Sale{Items: []model.SaleItem{{ItemID: uuid.New(), Quantity: 1}}}, locked)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+}
+
+func TestCheckout_MergesRepeatedCartLines(t *testing.T) {
+	mockSaleRepo := new(repository.MockSaleRepository)
+	saleService := NewSaleService(&repository.Repository{Sale: mockSaleRepo}, zap.NewNop())
+
+	cola := &model.Item{BaseModel: model.BaseModel{ID: uuid.New()}, SKU: "BEV-COLA-330", Stock: 5, Price: decimal.RequireFromString("7500")}
+	userID := uuid.New()
+
+	// The re-read after checkout returns the very sale that was written.
+	findCall := mockSaleRepo.On("FindByID", mock.Anything, mock.AnythingOfType("uuid.UUID"))
+	mockSaleRepo.On("Checkout", mock.Anything, mock.AnythingOfType("*model.Sale")).
+		Run(func(args mock.Arguments) { findCall.Return(args.Get(1).(*model.Sale), nil) }).
+		Return(map[uuid.UUID]*model.Item{cola.ID: cola}, nil)
+
+	res, err := saleService.Checkout(context.Background(), userID, request.CheckoutRequest{Items: []request.CartItemRequest{
+		{ItemID: &cola.ID, Quantity: 1},
+		{ItemID: &cola.ID, Quantity: 2},
+	}})
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, 3, res.Items[0].Quantity)
+	assert.Equal(t, "22500.00", res.TotalAmount)
+	assert.Equal(t, userID, res.UserID)
+	mockSaleRepo.AssertExpectations(t)
+}
+
+func TestCheckout_RejectsInvalidCartLines(t *testing.T) {
+	// Repository kosong: validasi cart harus gagal sebelum menyentuh DB.
+	saleService := NewSaleService(&repository.Repository{}, zap.NewNop())
+	itemID := uuid.New()
+
+	cases := map[string]struct {
+		cart    []request.CartItemRequest
+		wantErr error
+	}{
+		"empty cart":       {cart: nil, wantErr: ErrEmptyCart},
+		"no identifier":    {cart: []request.CartItemRequest{{Quantity: 1}}, wantErr: ErrInvalidCartLine},
+		"both identifiers": {cart: []request.CartItemRequest{{ItemID: &itemID, SKU: "GUM", Quantity: 1}}, wantErr: ErrInvalidCartLine},
+		"zero quantity":    {cart: []request.CartItemRequest{{ItemID: &itemID}}, wantErr: ErrInvalidCartQuantity},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res, err := saleService.Checkout(context.Background(), uuid.New(), request.CheckoutRequest{Items: tc.cart})
+			assert.Nil(t, res)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCheckVoidable_OnlySameDayCompletedSales(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, jakarta)
+
+	// 23:30 UTC on the 9th is already 06:30 on the 10th in Jakarta.
+	earlyThisMorning := &model.Sale{Status: model.SaleCompleted, BaseSimple: model.BaseSimple{CreatedAt: time.Date(2026, 3, 9, 23, 30, 0, 0, time.UTC)}}
+	yesterday := &model.Sale{Status: model.SaleCompleted, BaseSimple: model.BaseSimple{CreatedAt: now.AddDate(0, 0, -1)}}
+	voided := &model.Sale{Status: model.SaleVoided, BaseSimple: model.BaseSimple{CreatedAt: now}}
+
+	assert.NoError(t, checkVoidable(earlyThisMorning, now))
+	assert.ErrorIs(t, checkVoidable(yesterday, now), ErrSaleVoidExpired)
+	assert.ErrorIs(t, checkVoidable(voided, now), ErrSaleVoided)
+}
+
+func TestBuildReturn_PreventsReturningMoreThanSold(t *testing.T) {
+	line := model.SaleItem{
+		BaseSimple:       model.BaseSimple{ID: uuid.New()},
+		ItemID:           uuid.New(),
+		SKU:              "BEV-COLA-330",
+		Quantity:         3,
+		ReturnedQuantity: 1,
+		UnitPrice:        decimal.RequireFromString("7500.50"),
+	}
+	sale := &model.Sale{Status: model.SaleCompleted, Items: []model.SaleItem{line}}
+
+	t.Run("within what is left", func(t *testing.T) {
+		ret := &model.SaleReturn{}
+		err := buildReturn(ret, sale, []request.ReturnItemRequest{{SaleItemID: line.ID, Quantity: 2}})
+
+		assert.NoError(t, err)
+		assert.Len(t, ret.Items, 1)
+		assert.Equal(t, line.ItemID, ret.Items[0].ItemID)
+		assert.Equal(t, "15001.00", ret.RefundAmount.StringFixed(2))
+	})
+
+	t.Run("repeated lines are added up", func(t *testing.T) {
+		err := buildReturn(&model.SaleReturn{}, sale, []request.ReturnItemRequest{
+			{SaleItemID: line.ID, Quantity: 1},
+			{SaleItemID: line.ID, Quantity: 2},
+		})
+		assert.ErrorIs(t, err, ErrReturnExceedsSold)
+	})
+
+	t.Run("unknown line", func(t *testing.T) {
+		err := buildReturn(&model.SaleReturn{}, sale, []request.ReturnItemRequest{{SaleItemID: uuid.New(), Quantity: 1}})
+		assert.ErrorIs(t, err, ErrSaleItemNotFound)
+	})
+
+	t.Run("voided sale", func(t *testing.T) {
+		voided := &model.Sale{Status: model.SaleVoided, Items: []model.SaleItem{line}}
+		err := buildReturn(&model.SaleReturn{}, voided, []request.ReturnItemRequest{{SaleItemID: line.ID, Quantity: 1}})
+		assert.ErrorIs(t, err, ErrSaleVoided)
+	})
+}
@@ -1,19 +1,39 @@
 package service
 
 import (
+	"inventory-system/internal/config"
 	"inventory-system/internal/repository"
+	"inventory-system/pkg/mailer"
 
 	"go.uber.org/zap"
 )
 
 type Service struct {
-	Auth AuthService
-	User UserService
+	Auth      AuthService
+	User      UserService
+	Warehouse WarehouseService
+	Shelf     ShelfService
+	Category  CategoryService
+	Item      ItemService
+	Stock     StockService
+	Sale      SaleService
+	Role      RoleService
+	APIKey    APIKeyService
+	Audit     AuditService
 }
 
-func NewService(repo *repository.Repository, logger *zap.Logger) *Service {
+func NewService(repo *repository.Repository, cfg config.Config, mail mailer.Mailer, logger *zap.Logger) *Service {
 	return &Service{
-		Auth: NewAuthService(repo, logger),
-		User: NewUserService(repo, logger),
+		Auth:      NewAuthService(repo, cfg.Auth, mail, logger),
+		User:      NewUserService(repo, cfg.Auth, logger),
+		Warehouse: NewWarehouseService(repo, logger),
+		Shelf:     NewShelfService(repo, logger),
+		Category:  NewCategoryService(repo, logger),
+		Item:      NewItemService(repo, logger),
+		Stock:     NewStockService(repo, logger),
+		Sale:      NewSaleService(repo, logger),
+		Role:      NewRoleService(repo, logger),
+		APIKey:    NewAPIKeyService(repo, logger),
+		Audit:     NewAuditService(repo, logger),
 	}
 }
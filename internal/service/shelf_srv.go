@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrShelfNotFound     = apperror.NotFound("SHELF_NOT_FOUND", "shelf not found")
+	ErrShelfNameRequired = apperror.Validation("SHELF_NAME_REQUIRED", "shelf name is required")
+	ErrShelfNameTaken    = apperror.Conflict("SHELF_NAME_TAKEN", "a shelf with this name already exists in the warehouse")
+	ErrShelfHasStock     = apperror.Conflict("SHELF_HAS_STOCK", "shelf still holds stock; move or sell the stock first")
+)
+
+type ShelfService interface {
+	CreateShelf(ctx context.Context, warehouseID uuid.UUID, req request.CreateShelfRequest) (*response.ShelfResponse, error)
+	GetShelves(ctx context.Context, req request.ShelfListQuery) (*response.PaginatedResponse[response.ShelfResponse], error)
+	GetShelf(ctx context.Context, id uuid.UUID) (*response.ShelfResponse, error)
+	GetShelfContents(ctx context.Context, id uuid.UUID) (*response.ShelfContentsResponse, error)
+	UpdateShelf(ctx context.Context, warehouseID, shelfID uuid.UUID, version int, req request.UpdateShelfRequest) (*response.ShelfResponse, error)
+	DeleteShelf(ctx context.Context, warehouseID, shelfID uuid.UUID, version int) error
+}
+
+type shelfService struct {
+	repo   *repository.Repository
+	logger *zap.Logger
+}
+
+func NewShelfService(repo *repository.Repository, logger *zap.Logger) ShelfService {
+	return &shelfService{repo: repo, logger: logger}
+}
+
+// CreateShelf adds a new shelf to an active warehouse.
+func (s *shelfService) CreateShelf(ctx context.Context, warehouseID uuid.UUID, req request.CreateShelfRequest) (*response.ShelfResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrShelfNameRequired
+	}
+
+	// 1. The parent warehouse must exist and not be soft-deleted.
+	warehouse, err := s.repo.Warehouse.FindByID(ctx, warehouseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWarehouseNotFound
+		}
+		s.logger.Error("Database error while fetching warehouse", zap.String("warehouse_id", warehouseID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	shelf := &model.Shelf{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		WarehouseID:   warehouse.ID,
+		WarehouseName: warehouse.Name,
+		Name:          name,
+	}
+
+	// 2. Insert; the unique index enforces one name per warehouse.
+	if err := s.repo.Shelf.Create(ctx, shelf); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrShelfNameTaken
+		}
+		s.logger.Error("Failed to insert shelf to DB", zap.Error(err), zap.String("warehouse_id", warehouseID.String()))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Shelf created successfully", zap.String("shelf_id", shelf.ID.String()), zap.String("warehouse_id", warehouseID.String()))
+
+	res := response.ToShelfResponse(shelf)
+	return &res, nil
+}
+
+// GetShelves returns a paginated list of shelves, optionally within a single warehouse.
+func (s *shelfService) GetShelves(ctx context.Context, req request.ShelfListQuery) (*response.PaginatedResponse[response.ShelfResponse], error) {
+	req.Normalize()
+
+	// A nested listing for a missing warehouse should 404 rather than return an empty page.
+	if req.WarehouseID != nil {
+		if _, err := s.repo.Warehouse.FindByID(ctx, *req.WarehouseID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrWarehouseNotFound
+			}
+			s.logger.Error("Database error while fetching warehouse", zap.Error(err))
+			return nil, apperror.Internal(err)
+		}
+	}
+
+	filter := repository.ShelfFilter{WarehouseID: req.WarehouseID, Search: req.Search}
+
+	totalItems, err := s.repo.Shelf.Count(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to count shelves", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	shelves, err := s.repo.Shelf.FindAll(ctx, req.Limit, req.Offset(), filter)
+	if err != nil {
+		s.logger.Error("Failed to fetch shelves", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	shelfResponses := make([]response.ShelfResponse, 0, len(shelves))
+	for _, sh := range shelves {
+		shelfResponses = append(shelfResponses, response.ToShelfResponse(sh))
+	}
+
+	result := response.NewPaginatedResponse(shelfResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// GetShelf returns a single shelf by its UUID.
+func (s *shelfService) GetShelf(ctx context.Context, id uuid.UUID) (*response.ShelfResponse, error) {
+	shelf, err := s.findShelf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := response.ToShelfResponse(shelf)
+	return &res, nil
+}
+
+// GetShelfContents lists what is currently stocked on a shelf.
+func (s *shelfService) GetShelfContents(ctx context.Context, id uuid.UUID) (*response.ShelfContentsResponse, error) {
+	shelf, err := s.findShelf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := s.repo.Shelf.FindContents(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to fetch shelf contents", zap.String("shelf_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := response.ToShelfContentsResponse(shelf, contents)
+	return &res, nil
+}
+
+// UpdateShelf renames a shelf that belongs to the given warehouse, provided it is still at version.
+func (s *shelfService) UpdateShelf(ctx context.Context, warehouseID, shelfID uuid.UUID, version int, req request.UpdateShelfRequest) (*response.ShelfResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrShelfNameRequired
+	}
+
+	shelf, err := s.findWarehouseShelf(ctx, warehouseID, shelfID)
+	if err != nil {
+		return nil, err
+	}
+	if shelf.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	shelf.Name = name
+	if err := s.repo.Shelf.Update(ctx, shelf); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrShelfNotFound
+		case errors.Is(err, repository.ErrDuplicate):
+			return nil, ErrShelfNameTaken
+		case errors.Is(err, repository.ErrVersionConflict):
+			return nil, ErrVersionMismatch
+		}
+		s.logger.Error("Database error while updating shelf", zap.String("shelf_id", shelfID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Shelf updated successfully", zap.String("shelf_id", shelfID.String()))
+
+	res := response.ToShelfResponse(shelf)
+	return &res, nil
+}
+
+// DeleteShelf soft-deletes an empty shelf at version that belongs to the given warehouse.
+func (s *shelfService) DeleteShelf(ctx context.Context, warehouseID, shelfID uuid.UUID, version int) error {
+	shelf, err := s.findWarehouseShelf(ctx, warehouseID, shelfID)
+	if err != nil {
+		return err
+	}
+	if shelf.Version != version {
+		return ErrVersionMismatch
+	}
+
+	// 🛡️ GUARD: Rak yang masih ada stoknya tidak boleh dihapus
+	hasStock, err := s.repo.Shelf.HasStock(ctx, shelfID)
+	if err != nil {
+		s.logger.Error("Database error while checking shelf stock", zap.String("shelf_id", shelfID.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+	if hasStock {
+		s.logger.Warn("Attempted to delete a shelf that still holds stock", zap.String("shelf_id", shelfID.String()))
+		return ErrShelfHasStock
+	}
+
+	if err := s.repo.Shelf.SoftDelete(ctx, shelfID, version); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return ErrShelfNotFound
+		case errors.Is(err, repository.ErrVersionConflict):
+			return ErrVersionMismatch
+		}
+		s.logger.Error("Database error while deleting shelf", zap.String("shelf_id", shelfID.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Shelf deleted successfully", zap.String("shelf_id", shelfID.String()))
+	return nil
+}
+
+func (s *shelfService) findShelf(ctx context.Context, id uuid.UUID) (*model.Shelf, error) {
+	shelf, err := s.repo.Shelf.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrShelfNotFound
+		}
+		s.logger.Error("Database error while fetching shelf", zap.String("shelf_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	return shelf, nil
+}
+
+// findWarehouseShelf loads a shelf and makes sure it is addressed through its own warehouse.
+func (s *shelfService) findWarehouseShelf(ctx context.Context, warehouseID, shelfID uuid.UUID) (*model.Shelf, error) {
+	shelf, err := s.findShelf(ctx, shelfID)
+	if err != nil {
+		return nil, err
+	}
+	if shelf.WarehouseID != warehouseID {
+		return nil, ErrShelfNotFound
+	}
+	return shelf, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestCreateShelf_DuplicateNameInWarehouse(t *testing.T) {
+	mockWarehouseRepo := new(repository.MockWarehouseRepository)
+	mockShelfRepo := new(repository.MockShelfRepository)
+	shelfService := NewShelfService(&repository.Repository{Warehouse: mockWarehouseRepo, Shelf: mockShelfRepo}, zap.NewNop())
+
+	warehouseID := uuid.New()
+	mockWarehouseRepo.On("FindByID", mock.Anything, warehouseID).Return(&model.Warehouse{BaseModel: model.BaseModel{ID: warehouseID}, Name: "Gudang Utama"}, nil)
+	// Unique index (warehouse_id, nama rak) yang nolak, bukan service
+	mockShelfRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *model.Shelf) bool {
+		return s.WarehouseID == warehouseID && s.Name == "Rak A1"
+	})).Return(repository.ErrDuplicate)
+
+	res, err := shelfService.CreateShelf(context.Background(), warehouseID, request.CreateShelfRequest{Name: "  Rak A1 "})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrShelfNameTaken)
+	assert.Equal(t, apperror.KindConflict, apperror.As(err).Kind)
+	mockShelfRepo.AssertExpectations(t)
+}
+
+func TestShelf_FromAnotherWarehouseIsNotFound(t *testing.T) {
+	warehouseID, otherWarehouseID, shelfID := uuid.New(), uuid.New(), uuid.New()
+	shelf := &model.Shelf{BaseModel: model.BaseModel{ID: shelfID, Version: 1}, WarehouseID: otherWarehouseID, Name: "Rak A1"}
+
+	t.Run("update", func(t *testing.T) {
+		mockShelfRepo := new(repository.MockShelfRepository)
+		shelfService := NewShelfService(&repository.Repository{Shelf: mockShelfRepo}, zap.NewNop())
+		mockShelfRepo.On("FindByID", mock.Anything, shelfID).Return(shelf, nil)
+
+		_, err := shelfService.UpdateShelf(context.Background(), warehouseID, shelfID, 1, request.UpdateShelfRequest{Name: "Rak B1"})
+
+		assert.ErrorIs(t, err, ErrShelfNotFound)
+		mockShelfRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mockShelfRepo := new(repository.MockShelfRepository)
+		shelfService := NewShelfService(&repository.Repository{Shelf: mockShelfRepo}, zap.NewNop())
+		mockShelfRepo.On("FindByID", mock.Anything, shelfID).Return(shelf, nil)
+
+		err := shelfService.DeleteShelf(context.Background(), warehouseID, shelfID, 1)
+
+		assert.ErrorIs(t, err, ErrShelfNotFound)
+		assert.Equal(t, apperror.KindNotFound, apperror.As(err).Kind)
+		mockShelfRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteShelf_RefusedWhileHoldingStock(t *testing.T) {
+	mockShelfRepo := new(repository.MockShelfRepository)
+	shelfService := NewShelfService(&repository.Repository{Shelf: mockShelfRepo}, zap.NewNop())
+
+	warehouseID, shelfID := uuid.New(), uuid.New()
+	mockShelfRepo.On("FindByID", mock.Anything, shelfID).Return(&model.Shelf{BaseModel: model.BaseModel{ID: shelfID, Version: 3}, WarehouseID: warehouseID}, nil)
+	mockShelfRepo.On("HasStock", mock.Anything, shelfID).Return(true, nil)
+
+	// SoftDelete sengaja TIDAK di-mock: kalau kepanggil, test otomatis gagal.
+	err := shelfService.DeleteShelf(context.Background(), warehouseID, shelfID, 3)
+
+	assert.ErrorIs(t, err, ErrShelfHasStock)
+	assert.Equal(t, apperror.KindConflict, apperror.As(err).Kind)
+	mockShelfRepo.AssertExpectations(t)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidStockQuantity     = apperror.Validation("INVALID_STOCK_QUANTITY", "quantity must be greater than zero")
+	ErrInsufficientStock        = apperror.Conflict("INSUFFICIENT_STOCK", "insufficient stock for this movement")
+	ErrStockOverflow            = apperror.Conflict("STOCK_OVERFLOW", "movement would exceed the maximum stock level")
+	ErrInvalidAdjustment        = apperror.Validation("INVALID_ADJUSTMENT", "new_stock must be zero or positive")
+	ErrAdjustmentReasonRequired = apperror.Validation("ADJUSTMENT_REASON_REQUIRED", "a reason is required for stock adjustments")
+	ErrNoStockChange            = apperror.Conflict("NO_STOCK_CHANGE", "adjustment does not change the stock level")
+	ErrInvalidMovementType      = apperror.Validation("INVALID_MOVEMENT_TYPE", "invalid movement type. Must be IN, OUT or ADJUSTMENT")
+	ErrInvalidDateRange         = apperror.Validation("INVALID_DATE_RANGE", "from cannot be after to")
+)
+
+type StockService interface {
+	StockIn(ctx context.Context, itemID, userID uuid.UUID, req request.StockMovementRequest) (*response.StockMovementResponse, error)
+	StockOut(ctx context.Context, itemID, userID uuid.UUID, req request.StockMovementRequest) (*response.StockMovementResponse, error)
+	Adjust(ctx context.Context, itemID, userID uuid.UUID, req request.StockAdjustmentRequest) (*response.StockMovementResponse, error)
+	GetMovements(ctx context.Context, itemID uuid.UUID, req request.StockMovementQuery) (*response.PaginatedResponse[response.StockMovementResponse], error)
+}
+
+type stockService struct {
+	repo   *repository.Repository
+	audit  Auditor
+	logger *zap.Logger
+}
+
+func NewStockService(repo *repository.Repository, logger *zap.Logger) StockService {
+	return &stockService{repo: repo, audit: NewAuditor(repo, logger), logger: logger}
+}
+
+// StockIn adds a positive quantity to an item's stock.
+func (s *stockService) StockIn(ctx context.Context, itemID, userID uuid.UUID, req request.StockMovementRequest) (*response.StockMovementResponse, error) {
+	if req.Quantity <= 0 {
+		return nil, ErrInvalidStockQuantity
+	}
+
+	entry := newStockLog(itemID, userID, model.MovementIn, req.ReferenceID, req.Description)
+	entry.Quantity = req.Quantity
+
+	return s.record(ctx, entry, func(current int) (int, error) {
+		if req.Quantity > math.MaxInt32-current {
+			return 0, ErrStockOverflow
+		}
+		return current + req.Quantity, nil
+	})
+}
+
+// StockOut removes a positive quantity from an item's stock, refusing to go below zero.
+func (s *stockService) StockOut(ctx context.Context, itemID, userID uuid.UUID, req request.StockMovementRequest) (*response.StockMovementResponse, error) {
+	if req.Quantity <= 0 {
+		return nil, ErrInvalidStockQuantity
+	}
+
+	entry := newStockLog(itemID, userID, model.MovementOut, req.ReferenceID, req.Description)
+	entry.Quantity = req.Quantity
+
+	return s.record(ctx, entry, func(current int) (int, error) {
+		// 🛡️ GUARD: Stok tidak boleh minus
+		if req.Quantity > current {
+			return 0, ErrInsufficientStock
+		}
+		return current - req.Quantity, nil
+	})
+}
+
+// Adjust sets an item's stock to a counted value. The ledger records the signed difference.
+func (s *stockService) Adjust(ctx context.Context, itemID, userID uuid.UUID, req request.StockAdjustmentRequest) (*response.StockMovementResponse, error) {
+	if req.NewStock < 0 || req.NewStock > math.MaxInt32 {
+		return nil, ErrInvalidAdjustment
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrAdjustmentReasonRequired
+	}
+
+	entry := newStockLog(itemID, userID, model.MovementAdjustment, nil, reason)
+
+	// The adjustment and its audit event are committed together.
+	var res *response.StockMovementResponse
+	err := withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		var err error
+		// The delta is only known once the row is locked, so it is filled in inside the transaction.
+		res, err = s.record(ctx, entry, func(current int) (int, error) {
+			if req.NewStock == current {
+				return 0, ErrNoStockChange
+			}
+			entry.Quantity = req.NewStock - current
+			return req.NewStock, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Movements in and out have their own ledger; only corrections by hand go to the audit trail.
+		return s.audit.Record(ctx, model.AuditStockAdjust, model.AuditEntityStock, itemID,
+			map[string]any{"stock": res.BalanceAfter - res.Quantity},
+			map[string]any{"stock": res.BalanceAfter, "reason": reason, "stock_log_id": res.ID},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetMovements returns an item's ledger entries, newest first.
+func (s *stockService) GetMovements(ctx context.Context, itemID uuid.UUID, req request.StockMovementQuery) (*response.PaginatedResponse[response.StockMovementResponse], error) {
+	req.Normalize()
+
+	filter := repository.StockLogFilter{
+		MovementType: model.MovementType(strings.ToUpper(req.Type)),
+		From:         req.From,
+		To:           req.To,
+	}
+	switch filter.MovementType {
+	case "", model.MovementIn, model.MovementOut, model.MovementAdjustment:
+	default:
+		return nil, ErrInvalidMovementType
+	}
+	if req.From != nil && req.To != nil && req.From.After(*req.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if _, err := s.repo.Item.FindByID(ctx, itemID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		s.logger.Error("Database error while fetching item", zap.String("item_id", itemID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	totalItems, err := s.repo.Stock.CountByItem(ctx, itemID, filter)
+	if err != nil {
+		s.logger.Error("Failed to count stock movements", zap.String("item_id", itemID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	logs, err := s.repo.Stock.FindByItem(ctx, itemID, req.Limit, req.Offset(), filter)
+	if err != nil {
+		s.logger.Error("Failed to fetch stock movements", zap.String("item_id", itemID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	movementResponses := make([]response.StockMovementResponse, 0, len(logs))
+	for _, l := range logs {
+		movementResponses = append(movementResponses, response.ToStockMovementResponse(l))
+	}
+
+	result := response.NewPaginatedResponse(movementResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// record runs a movement through the repository and maps its errors.
+func (s *stockService) record(ctx context.Context, entry *model.StockLog, apply repository.StockApplyFunc) (*response.StockMovementResponse, error) {
+	if err := s.repo.Stock.Apply(ctx, entry, apply); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrItemNotFound
+		case errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrStockOverflow), errors.Is(err, ErrNoStockChange):
+			s.logger.Warn("Rejected stock movement",
+				zap.String("item_id", entry.ItemID.String()),
+				zap.String("movement_type", string(entry.MovementType)),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		s.logger.Error("Database error while recording stock movement", zap.String("item_id", entry.ItemID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Stock movement recorded",
+		zap.String("item_id", entry.ItemID.String()),
+		zap.String("movement_type", string(entry.MovementType)),
+		zap.Int("quantity", entry.Quantity),
+		zap.Int("balance_after", entry.BalanceAfter),
+	)
+
+	res := response.ToStockMovementResponse(entry)
+	return &res, nil
+}
+
+func newStockLog(itemID, userID uuid.UUID, movementType model.MovementType, referenceID *uuid.UUID, description string) *model.StockLog {
+	return &model.StockLog{
+		BaseSimple:   model.BaseSimple{ID: uuid.New()},
+		ItemID:       itemID,
+		UserID:       userID,
+		MovementType: movementType,
+		ReferenceID:  referenceID,
+		Description:  strings.TrimSpace(description),
+	}
+}
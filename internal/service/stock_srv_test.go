@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newStockTestService(currentStock int) (StockService, *repository.MockStockRepository, *repository.MockAuditRepository) {
+	mockStockRepo := new(repository.MockStockRepository)
+	mockStockRepo.On("Apply", mock.Anything, mock.AnythingOfType("*model.StockLog")).Return(currentStock, nil)
+	mockAuditRepo := new(repository.MockAuditRepository)
+
+	repos := &repository.Repository{Stock: mockStockRepo, Audit: mockAuditRepo, Tx: new(repository.FakeTxManager)}
+	return NewStockService(repos, zap.NewNop()), mockStockRepo, mockAuditRepo
+}
+
+func TestStockOut_RejectsNegativeStock(t *testing.T) {
+	stockService, _, _ := newStockTestService(3)
+
+	res, err := stockService.StockOut(context.Background(), uuid.New(), uuid.New(), request.StockMovementRequest{Quantity: 5})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
+
+func TestStockOut_RecordsBalance(t *testing.T) {
+	stockService, mockStockRepo, mockAuditRepo := newStockTestService(10)
+	itemID, userID := uuid.New(), uuid.New()
+
+	res, err := stockService.StockOut(context.Background(), itemID, userID, request.StockMovementRequest{Quantity: 4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MovementOut, res.MovementType)
+	assert.Equal(t, 4, res.Quantity)
+	assert.Equal(t, 6, res.BalanceAfter)
+	assert.Equal(t, userID, res.UserID)
+	mockStockRepo.AssertExpectations(t)
+	// Barang keluar biasa cukup dicatat di ledger, bukan di audit trail
+	mockAuditRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+}
+
+func TestAdjust_RecordsSignedDelta(t *testing.T) {
+	stockService, _, mockAuditRepo := newStockTestService(24)
+	itemID := uuid.New()
+	var event *model.AuditEvent
+	mockAuditRepo.On("Append", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { event = args.Get(1).(*model.AuditEvent) }).
+		Return(nil).Once()
+
+	res, err := stockService.Adjust(context.Background(), itemID, uuid.New(), request.StockAdjustmentRequest{
+		NewStock: 20,
+		Reason:   "Stock take: 4 cans damaged",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.MovementAdjustment, res.MovementType)
+	assert.Equal(t, -4, res.Quantity)
+	assert.Equal(t, 20, res.BalanceAfter)
+
+	// Koreksi stok manual wajib masuk audit trail
+	require.NotNil(t, event)
+	assert.Equal(t, model.AuditStockAdjust, event.Action)
+	assert.Equal(t, itemID, event.EntityID)
+	assert.Contains(t, string(event.Changes), `"stock":{"new":20,"old":24}`)
+}
+
+func TestStockIn_RejectsNonPositiveQuantity(t *testing.T) {
+	// Repository kosong: validasi harus gagal sebelum menyentuh DB.
+	stockService := NewStockService(&repository.Repository{}, zap.NewNop())
+
+	res, err := stockService.StockIn(context.Background(), uuid.New(), uuid.New(), request.StockMovementRequest{Quantity: 0})
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrInvalidStockQuantity)
+}
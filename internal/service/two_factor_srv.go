@@ -0,0 +1,455 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+	"inventory-system/pkg/totp"
+	"inventory-system/pkg/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = apperror.Conflict("TWO_FACTOR_ALREADY_ENABLED", "two-factor authentication is already enabled")
+	ErrTwoFactorNotSetUp       = apperror.Conflict("TWO_FACTOR_NOT_SET_UP", "start two-factor setup before confirming it")
+	ErrTwoFactorNotEnabled     = apperror.Conflict("TWO_FACTOR_NOT_ENABLED", "two-factor authentication is not enabled")
+	ErrTwoFactorMandatory      = apperror.Forbidden("TWO_FACTOR_MANDATORY", "forbidden: two-factor authentication is mandatory for your role")
+	ErrInvalidLoginChallenge   = apperror.Unauthorized("INVALID_LOGIN_CHALLENGE", "login challenge is invalid or expired; please log in again")
+	ErrInvalidTwoFactorCode    = apperror.Validation("INVALID_TWO_FACTOR_CODE", "two-factor code is invalid").
+					WithDetails([]apperror.FieldError{{Field: "code", Message: "is invalid"}})
+	ErrIncorrectPassword = apperror.Validation("INCORRECT_PASSWORD", "password is incorrect").
+				WithDetails([]apperror.FieldError{{Field: "password", Message: "is incorrect"}})
+)
+
+const (
+	// loginChallengeTTL is how long a user has to enter the second factor after the password.
+	loginChallengeTTL = 5 * time.Minute
+	// maxChallengeAttempts is how many wrong codes one challenge tolerates before the login starts over.
+	maxChallengeAttempts = 5
+	// totpSkew accepts the codes of one step either side of now, for authenticator clock drift.
+	totpSkew = 1
+	// recoveryCodeCount is how many recovery codes a user holds at a time.
+	recoveryCodeCount = 10
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// twoFactorMandatory reports whether the policy requires 2FA for role.
+func (s *authService) twoFactorMandatory(role model.UserRole) bool {
+	return s.cfg.Require2FAForAdmins && (role == model.RoleSuperAdmin || role == model.RoleAdmin)
+}
+
+// twoFactorChallenge returns a challenge when the user has to pass a second factor, or nil when the
+// password alone is enough.
+func (s *authService) twoFactorChallenge(ctx context.Context, reqID string, user *model.User) (*response.TwoFactorChallengeResponse, error) {
+	tf, err := s.repo.TwoFactor.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Database error while fetching two-factor settings", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	enrollmentRequired := false
+	if !tf.Enabled() {
+		if !s.twoFactorMandatory(user.Role) {
+			return nil, nil
+		}
+		enrollmentRequired = true
+	}
+
+	plain, hash, err := utils.GenerateToken()
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate login challenge", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	challenge := &model.LoginChallenge{
+		BaseSimple:         model.BaseSimple{ID: uuid.New()},
+		UserID:             user.ID,
+		TokenHash:          hash,
+		EnrollmentRequired: enrollmentRequired,
+		ExpiresAt:          s.now().Add(loginChallengeTTL),
+	}
+	if err := s.repo.TwoFactor.CreateChallenge(ctx, challenge); err != nil {
+		s.logger.Error("System Error: Failed to save login challenge", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Login requires a second factor",
+		zap.String("request_id", reqID),
+		zap.String("user_id", user.ID.String()),
+		zap.Bool("enrollment_required", enrollmentRequired),
+	)
+	return &response.TwoFactorChallengeResponse{
+		TwoFactorRequired:  true,
+		EnrollmentRequired: enrollmentRequired,
+		ChallengeToken:     plain,
+		ExpiresAt:          challenge.ExpiresAt,
+	}, nil
+}
+
+// findChallenge resolves a challenge token to a live challenge.
+func (s *authService) findChallenge(ctx context.Context, reqID, token string) (*model.LoginChallenge, error) {
+	challenge, err := s.repo.TwoFactor.FindChallenge(ctx, utils.HashToken(token), maxChallengeAttempts)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidLoginChallenge
+		}
+		s.logger.Error("Database error while fetching login challenge", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	return challenge, nil
+}
+
+// CompleteTwoFactorLogin finishes a login with an authenticator or recovery code. When the challenge
+// required enrollment, the code confirms the secret from SetupTwoFactorForLogin and the response
+// carries the first recovery codes.
+func (s *authService) CompleteTwoFactorLogin(ctx context.Context, req request.TwoFactorLoginRequest, client model.ClientInfo) (*response.AuthResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	challenge, err := s.findChallenge(ctx, reqID, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.User.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// The account was deleted between the password and the code.
+			return nil, ErrInvalidLoginChallenge
+		}
+		s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	tf, err := s.repo.TwoFactor.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Database error while fetching two-factor settings", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	var recoveryCodes []string
+	switch {
+	case tf.Enabled():
+		err = s.verifySecondFactor(ctx, reqID, tf, req.Code)
+	case challenge.EnrollmentRequired && tf != nil:
+		recoveryCodes, err = s.confirmEnrollment(ctx, reqID, tf, req.Code)
+	case challenge.EnrollmentRequired:
+		return nil, ErrTwoFactorNotSetUp
+	default:
+		// 2FA was turned off after the challenge was issued; start over.
+		return nil, ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if failErr := s.repo.TwoFactor.FailChallenge(ctx, challenge.ID); failErr != nil {
+				s.logger.Error("Failed to count a wrong two-factor code", zap.String("request_id", reqID), zap.Error(failErr))
+			}
+			s.logger.Warn("Two-factor login failed: invalid code", zap.String("request_id", reqID), zap.String("user_id", user.ID.String()))
+		}
+		return nil, err
+	}
+
+	// 🛡️ GUARD: Satu challenge cuma boleh dipakai sekali, walaupun dua request datang barengan
+	if err := s.repo.TwoFactor.CompleteChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidLoginChallenge
+		}
+		s.logger.Error("Database error while completing login challenge", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res, err := s.issueLogin(ctx, reqID, user, client)
+	if err != nil {
+		return nil, err
+	}
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+// SetupTwoFactorForLogin starts the mandatory enrollment of a user whose login is waiting on it.
+func (s *authService) SetupTwoFactorForLogin(ctx context.Context, req request.LoginChallengeRequest) (*response.TwoFactorSetupResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	challenge, err := s.findChallenge(ctx, reqID, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.EnrollmentRequired {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	user, err := s.repo.User.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidLoginChallenge
+		}
+		s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	return s.startEnrollment(ctx, reqID, user)
+}
+
+// GetTwoFactorStatus reports whether the user has 2FA on and whether their role requires it.
+func (s *authService) GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*response.TwoFactorStatusResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	user, tf, err := s.userWithTwoFactor(ctx, reqID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &response.TwoFactorStatusResponse{
+		Enabled:   tf.Enabled(),
+		Mandatory: s.twoFactorMandatory(user.Role),
+	}
+	if tf.Enabled() {
+		res.EnabledAt = tf.EnabledAt
+	}
+	return res, nil
+}
+
+// SetupTwoFactor creates a new secret for the user. It only takes effect once EnableTwoFactor
+// confirms a code from it.
+func (s *authService) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*response.TwoFactorSetupResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	user, err := s.repo.User.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	return s.startEnrollment(ctx, reqID, user)
+}
+
+// EnableTwoFactor confirms the pending secret with a code from the authenticator and returns the
+// first set of recovery codes.
+func (s *authService) EnableTwoFactor(ctx context.Context, userID uuid.UUID, req request.TwoFactorCodeRequest) (*response.RecoveryCodesResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	_, tf, err := s.userWithTwoFactor(ctx, reqID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if tf == nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	codes, err := s.confirmEnrollment(ctx, reqID, tf, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turns 2FA off. It needs the password and a current code, both counted against the
+// login lockout, and is refused while the policy makes 2FA mandatory for the user's role.
+func (s *authService) DisableTwoFactor(ctx context.Context, userID uuid.UUID, req request.DisableTwoFactorRequest) error {
+	reqID := middleware.GetReqID(ctx)
+
+	user, tf, err := s.userWithTwoFactor(ctx, reqID, userID)
+	if err != nil {
+		return err
+	}
+	if !tf.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+	if s.twoFactorMandatory(user.Role) {
+		return ErrTwoFactorMandatory
+	}
+
+	// 🛡️ GUARD: Session yang dicuri saja gak cukup buat matiin 2FA, dan tebakan password ikut dihitung ke lockout
+	if err := verifyCurrentPassword(ctx, s.repo, s.cfg, s.logger, user, req.Password, ErrIncorrectPassword, s.now()); err != nil {
+		s.logger.Warn("Disabling 2FA rejected", zap.String("request_id", reqID), zap.String("user_id", userID.String()), zap.Error(err))
+		return err
+	}
+	if err := s.confirmWithSecondFactor(ctx, reqID, tf, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.repo.TwoFactor.Disable(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		s.logger.Error("Database error while disabling 2FA", zap.String("request_id", reqID), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("Two-factor authentication disabled", zap.String("request_id", reqID), zap.String("user_id", userID.String()))
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user with a fresh set.
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req request.TwoFactorCodeRequest) (*response.RecoveryCodesResponse, error) {
+	reqID := middleware.GetReqID(ctx)
+
+	user, tf, err := s.userWithTwoFactor(ctx, reqID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !tf.Enabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err := accountLocked(user, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.confirmWithSecondFactor(ctx, reqID, tf, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate recovery codes", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	if err := s.repo.TwoFactor.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		s.logger.Error("Database error while replacing recovery codes", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Recovery codes regenerated", zap.String("request_id", reqID), zap.String("user_id", userID.String()))
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// userWithTwoFactor loads the user and their enrollment; the enrollment is nil when 2FA was never set up.
+func (s *authService) userWithTwoFactor(ctx context.Context, reqID string, userID uuid.UUID) (*model.User, *model.TwoFactor, error) {
+	user, err := s.repo.User.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching user", zap.String("request_id", reqID), zap.Error(err))
+		return nil, nil, apperror.Internal(err)
+	}
+
+	tf, err := s.repo.TwoFactor.Get(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Database error while fetching two-factor settings", zap.String("request_id", reqID), zap.Error(err))
+		return nil, nil, apperror.Internal(err)
+	}
+	return user, tf, nil
+}
+
+// startEnrollment stores a fresh secret for user and returns what the authenticator app needs.
+func (s *authService) startEnrollment(ctx context.Context, reqID string, user *model.User) (*response.TwoFactorSetupResponse, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate TOTP secret", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	if err := s.repo.TwoFactor.StartEnrollment(ctx, user.ID, secret); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		s.logger.Error("Database error while starting 2FA enrollment", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	return &response.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.cfg.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// confirmEnrollment enables a pending enrollment once code matches its secret, and returns the
+// plaintext recovery codes. They are stored hashed, so this is the only time they are shown.
+func (s *authService) confirmEnrollment(ctx context.Context, reqID string, tf *model.TwoFactor, code string) ([]string, error) {
+	step, ok := totp.Validate(tf.Secret, strings.TrimSpace(code), s.now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		s.logger.Error("System Error: Failed to generate recovery codes", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	if err := s.repo.TwoFactor.Enable(ctx, tf.UserID, step, hashes); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorNotSetUp
+		}
+		s.logger.Error("Database error while enabling 2FA", zap.String("request_id", reqID), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	s.logger.Info("Two-factor authentication enabled", zap.String("request_id", reqID), zap.String("user_id", tf.UserID.String()))
+	return codes, nil
+}
+
+// verifySecondFactor accepts either a current authenticator code or an unused recovery code.
+// Both are single use: a code's time step and a recovery code cannot be accepted twice.
+func (s *authService) verifySecondFactor(ctx context.Context, reqID string, tf *model.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+
+	var err error
+	if step, ok := totp.Validate(tf.Secret, code, s.now(), totpSkew); ok {
+		err = s.repo.TwoFactor.UseStep(ctx, tf.UserID, step)
+	} else {
+		err = s.repo.TwoFactor.UseRecoveryCode(ctx, tf.UserID, utils.HashToken(normalizeRecoveryCode(code)))
+		if err == nil {
+			s.logger.Info("Recovery code used", zap.String("request_id", reqID), zap.String("user_id", tf.UserID.String()))
+		}
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		s.logger.Error("Database error while verifying two-factor code", zap.String("request_id", reqID), zap.Error(err))
+		return apperror.Internal(err)
+	}
+	return nil
+}
+
+// confirmWithSecondFactor checks the code a signed-in user gives to change their 2FA. Unlike a login
+// challenge there is no challenge to burn, so a wrong code counts against the account's lockout instead.
+func (s *authService) confirmWithSecondFactor(ctx context.Context, reqID string, tf *model.TwoFactor, code string) error {
+	err := s.verifySecondFactor(ctx, reqID, tf, code)
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		s.logger.Warn("Wrong two-factor code from a signed-in user", zap.String("request_id", reqID), zap.String("user_id", tf.UserID.String()))
+		return recordWrongPassword(ctx, s.repo, s.cfg, s.logger, tf.UserID, ErrInvalidTwoFactorCode, s.now())
+	}
+	return err
+}
+
+// generateRecoveryCodes returns recoveryCodeCount codes shaped like "k3j9d-x8q2m" and their hashes.
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, recoveryCodeCount)
+	hashes = make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7) // 56 random bits; the code keeps 50 of them.
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = utils.HashToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode makes recovery codes match however the user typed them.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
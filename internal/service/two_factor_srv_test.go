@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/totp"
+	"inventory-system/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func TestLogin_TwoFactorChallenge(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	enabledAt := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		role           model.UserRole
+		twoFactor      *model.TwoFactor // nil: never set up
+		requireAdmins  bool
+		wantChallenge  bool
+		wantEnrollment bool
+	}{
+		{"enabled 2FA always asks for a code", model.RoleStaff, &model.TwoFactor{Secret: testTOTPSecret, EnabledAt: &enabledAt}, false, true, false},
+		{"mandatory policy makes admins enroll", model.RoleAdmin, nil, true, true, true},
+		{"unconfirmed setup does not count as enrolled", model.RoleSuperAdmin, &model.TwoFactor{Secret: testTOTPSecret}, true, true, true},
+		{"policy does not apply to staff", model.RoleStaff, nil, true, false, false},
+		{"policy off lets admins in with a password", model.RoleAdmin, nil, false, false, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockSessionRepo := new(repository.MockSessionRepository)
+			mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+			authService := newTestAuthService(&repository.Repository{User: mockUserRepo, Session: mockSessionRepo, TwoFactor: mockTwoFactorRepo}, now)
+			authService.cfg.Require2FAForAdmins = tc.requireAdmins
+
+			user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "admin@gmail.com", PasswordHash: hash, Role: tc.role}
+			mockUserRepo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+			if tc.twoFactor != nil {
+				mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(tc.twoFactor, nil)
+			} else {
+				mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(nil, repository.ErrNotFound)
+			}
+
+			var stored *model.LoginChallenge
+			mockTwoFactorRepo.On("CreateChallenge", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(*model.LoginChallenge) }).Return(nil)
+			mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			res, challenge, err := authService.Login(context.Background(), request.LoginRequest{Email: user.Email, Password: "password123"}, model.ClientInfo{})
+
+			require.NoError(t, err)
+			if !tc.wantChallenge {
+				assert.NotNil(t, res)
+				assert.Nil(t, challenge)
+				mockTwoFactorRepo.AssertNotCalled(t, "CreateChallenge", mock.Anything, mock.Anything)
+				return
+			}
+
+			// Password benar tapi belum boleh dapat token
+			assert.Nil(t, res)
+			require.NotNil(t, challenge)
+			assert.True(t, challenge.TwoFactorRequired)
+			assert.Equal(t, tc.wantEnrollment, challenge.EnrollmentRequired)
+			assert.Equal(t, now.Add(5*time.Minute), challenge.ExpiresAt)
+			assert.Equal(t, utils.HashToken(challenge.ChallengeToken), stored.TokenHash)
+			assert.Equal(t, user.ID, stored.UserID)
+			mockSessionRepo.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCompleteTwoFactorLogin(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	enabledAt := now.Add(-24 * time.Hour)
+	validCode, err := totp.Code(testTOTPSecret, totp.Step(now))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		code    string
+		setup   func(m *repository.MockTwoFactorRepository, userID uuid.UUID)
+		wantErr error
+	}{
+		{
+			name: "current authenticator code",
+			code: validCode,
+			setup: func(m *repository.MockTwoFactorRepository, userID uuid.UUID) {
+				m.On("UseStep", mock.Anything, userID, totp.Step(now)).Return(nil)
+			},
+		},
+		{
+			name: "replayed authenticator code",
+			code: validCode,
+			setup: func(m *repository.MockTwoFactorRepository, userID uuid.UUID) {
+				m.On("UseStep", mock.Anything, userID, totp.Step(now)).Return(repository.ErrNotFound)
+			},
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "recovery code typed in upper case",
+			code: "K3J9D-X8Q2M",
+			setup: func(m *repository.MockTwoFactorRepository, userID uuid.UUID) {
+				m.On("UseRecoveryCode", mock.Anything, userID, utils.HashToken("k3j9dx8q2m")).Return(nil)
+			},
+		},
+		{
+			name: "unknown code",
+			code: "000000",
+			setup: func(m *repository.MockTwoFactorRepository, userID uuid.UUID) {
+				m.On("UseRecoveryCode", mock.Anything, userID, utils.HashToken("000000")).Return(repository.ErrNotFound)
+			},
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockSessionRepo := new(repository.MockSessionRepository)
+			mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+			authService := newTestAuthService(&repository.Repository{User: mockUserRepo, Session: mockSessionRepo, TwoFactor: mockTwoFactorRepo}, now)
+
+			user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "admin@gmail.com", Role: model.RoleAdmin}
+			challenge := &model.LoginChallenge{BaseSimple: model.BaseSimple{ID: uuid.New()}, UserID: user.ID}
+			mockTwoFactorRepo.On("FindChallenge", mock.Anything, utils.HashToken("challenge-token"), 5).Return(challenge, nil)
+			mockUserRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+			mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: testTOTPSecret, EnabledAt: &enabledAt}, nil)
+			tc.setup(mockTwoFactorRepo, user.ID)
+			if tc.wantErr != nil {
+				mockTwoFactorRepo.On("FailChallenge", mock.Anything, challenge.ID).Return(nil)
+			} else {
+				mockTwoFactorRepo.On("CompleteChallenge", mock.Anything, challenge.ID).Return(nil)
+			}
+			mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			res, err := authService.CompleteTwoFactorLogin(context.Background(), request.TwoFactorLoginRequest{ChallengeToken: "challenge-token", Code: tc.code}, model.ClientInfo{})
+
+			if tc.wantErr != nil {
+				assert.Nil(t, res)
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockTwoFactorRepo.AssertExpectations(t)
+				mockTwoFactorRepo.AssertNotCalled(t, "CompleteChallenge", mock.Anything, mock.Anything)
+				mockSessionRepo.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, res.AccessToken)
+			assert.Empty(t, res.RecoveryCodes)
+			mockTwoFactorRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCompleteTwoFactorLogin_EnrollmentReturnsRecoveryCodes(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mockUserRepo := new(repository.MockUserRepository)
+	mockSessionRepo := new(repository.MockSessionRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	authService := newTestAuthService(&repository.Repository{User: mockUserRepo, Session: mockSessionRepo, TwoFactor: mockTwoFactorRepo}, now)
+
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "admin@gmail.com", Role: model.RoleAdmin}
+	challenge := &model.LoginChallenge{BaseSimple: model.BaseSimple{ID: uuid.New()}, UserID: user.ID, EnrollmentRequired: true}
+	mockTwoFactorRepo.On("FindChallenge", mock.Anything, utils.HashToken("challenge-token"), 5).Return(challenge, nil)
+	mockUserRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: testTOTPSecret}, nil)
+
+	var storedHashes []string
+	mockTwoFactorRepo.On("Enable", mock.Anything, user.ID, totp.Step(now), mock.Anything).
+		Run(func(args mock.Arguments) { storedHashes = args.Get(3).([]string) }).Return(nil)
+	mockTwoFactorRepo.On("CompleteChallenge", mock.Anything, challenge.ID).Return(nil)
+	mockSessionRepo.On("Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	code, err := totp.Code(testTOTPSecret, totp.Step(now))
+	require.NoError(t, err)
+	res, err := authService.CompleteTwoFactorLogin(context.Background(), request.TwoFactorLoginRequest{ChallengeToken: "challenge-token", Code: code}, model.ClientInfo{})
+
+	require.NoError(t, err)
+	require.Len(t, res.RecoveryCodes, 10)
+	require.Len(t, storedHashes, 10)
+	assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, res.RecoveryCodes[0])
+	// Yang disimpan cuma hash dari kode tanpa tanda hubung
+	assert.Equal(t, utils.HashToken(normalizeRecoveryCode(res.RecoveryCodes[0])), storedHashes[0])
+	mockTwoFactorRepo.AssertExpectations(t)
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	enabledAt := now.Add(-24 * time.Hour)
+	validCode, err := totp.Code(testTOTPSecret, totp.Step(now))
+	require.NoError(t, err)
+
+	lockedUntil := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name          string
+		role          model.UserRole
+		requireAdmins bool
+		password      string
+		code          string
+		lockedUntil   *time.Time
+		afterFailure  *model.User // state setelah RecordFailedLogin
+		wantErr       error
+	}{
+		{"mandatory for admins", model.RoleAdmin, true, "password123", validCode, nil, nil, ErrTwoFactorMandatory},
+		{"wrong password counts a failed login", model.RoleStaff, true, "wrong", validCode, nil, &model.User{FailedLoginAttempts: 1}, ErrIncorrectPassword},
+		{"wrong password that reaches the limit locks", model.RoleStaff, true, "wrong", validCode, nil, &model.User{FailedLoginAttempts: 5, LockedUntil: &lockedUntil}, ErrAccountLocked},
+		{"locked account rejects even the right password", model.RoleStaff, true, "password123", validCode, &lockedUntil, nil, ErrAccountLocked},
+		{"wrong code counts a failed login", model.RoleStaff, true, "password123", "000000", nil, &model.User{FailedLoginAttempts: 1}, ErrInvalidTwoFactorCode},
+		{"staff with password and code", model.RoleStaff, true, "password123", validCode, nil, nil, nil},
+		{"admin while policy is off", model.RoleAdmin, false, "password123", validCode, nil, nil, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+			authService := newTestAuthService(&repository.Repository{User: mockUserRepo, TwoFactor: mockTwoFactorRepo}, now)
+			authService.cfg.Require2FAForAdmins = tc.requireAdmins
+
+			user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, PasswordHash: hash, Role: tc.role, LockedUntil: tc.lockedUntil}
+			mockUserRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+			mockUserRepo.On("RecordFailedLogin", mock.Anything, user.ID, 5, 15*time.Minute).Return(tc.afterFailure, nil)
+			mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: testTOTPSecret, EnabledAt: &enabledAt}, nil)
+			mockTwoFactorRepo.On("UseStep", mock.Anything, user.ID, totp.Step(now)).Return(nil)
+			mockTwoFactorRepo.On("UseRecoveryCode", mock.Anything, user.ID, mock.Anything).Return(repository.ErrNotFound)
+			mockTwoFactorRepo.On("Disable", mock.Anything, user.ID).Return(nil)
+
+			err := authService.DisableTwoFactor(context.Background(), user.ID, request.DisableTwoFactorRequest{Password: tc.password, Code: tc.code})
+
+			if tc.afterFailure != nil {
+				mockUserRepo.AssertCalled(t, "RecordFailedLogin", mock.Anything, user.ID, 5, 15*time.Minute)
+			} else {
+				mockUserRepo.AssertNotCalled(t, "RecordFailedLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockTwoFactorRepo.AssertNotCalled(t, "Disable", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			mockTwoFactorRepo.AssertCalled(t, "Disable", mock.Anything, user.ID)
+		})
+	}
+}
+
+func TestRegenerateRecoveryCodes_WrongCodeCountsTowardLockout(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	enabledAt := now.Add(-24 * time.Hour)
+	lockedUntil := now.Add(15 * time.Minute)
+
+	mockUserRepo := new(repository.MockUserRepository)
+	mockTwoFactorRepo := new(repository.MockTwoFactorRepository)
+	authService := newTestAuthService(&repository.Repository{User: mockUserRepo, TwoFactor: mockTwoFactorRepo}, now)
+
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Role: model.RoleStaff, FailedLoginAttempts: 4}
+	mockUserRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	mockTwoFactorRepo.On("Get", mock.Anything, user.ID).Return(&model.TwoFactor{UserID: user.ID, Secret: testTOTPSecret, EnabledAt: &enabledAt}, nil)
+	mockTwoFactorRepo.On("UseRecoveryCode", mock.Anything, user.ID, mock.Anything).Return(repository.ErrNotFound)
+	// Tebakan kelima mengunci akun, sama seperti di form login
+	mockUserRepo.On("RecordFailedLogin", mock.Anything, user.ID, 5, 15*time.Minute).Return(&model.User{FailedLoginAttempts: 5, LockedUntil: &lockedUntil}, nil).Once()
+
+	_, err := authService.RegenerateRecoveryCodes(context.Background(), user.ID, request.TwoFactorCodeRequest{Code: "000000"})
+	assert.ErrorIs(t, err, ErrAccountLocked)
+
+	// Setelah dikunci, kode yang benar pun ditolak tanpa dicek
+	user.LockedUntil = &lockedUntil
+	validCode, err := totp.Code(testTOTPSecret, totp.Step(now))
+	require.NoError(t, err)
+	_, err = authService.RegenerateRecoveryCodes(context.Background(), user.ID, request.TwoFactorCodeRequest{Code: validCode})
+	assert.ErrorIs(t, err, ErrAccountLocked)
+	mockTwoFactorRepo.AssertNotCalled(t, "ReplaceRecoveryCodes", mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// withinTx runs fn through the repository's TxManager. Errors returned by fn, already mapped to
+// *apperror.Error, pass through; a failure to begin or commit the transaction is logged and
+// becomes an internal error.
+func withinTx(ctx context.Context, repo *repository.Repository, logger *zap.Logger, fn func(ctx context.Context) error) error {
+	err := repo.Tx.WithinTx(ctx, fn)
+	if err == nil {
+		return nil
+	}
+
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	logger.Error("Database transaction failed", zap.String("request_id", middleware.GetReqID(ctx)), zap.Error(err))
+	return apperror.Internal(err)
+}
@@ -3,97 +3,157 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
+	"inventory-system/internal/config"
 	"inventory-system/internal/dto/request"
 	"inventory-system/internal/dto/response"
 	"inventory-system/internal/model"
 	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
 	"inventory-system/pkg/utils"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrUserNotFound            = apperror.NotFound("USER_NOT_FOUND", "user not found")
+	ErrEmailTaken              = apperror.Conflict("EMAIL_TAKEN", "email already exists")
+	ErrIncludeDeletedForbidden = apperror.Forbidden("INCLUDE_DELETED_FORBIDDEN", "forbidden: listing deleted users needs the users:restore permission")
+
+	ErrInvalidUserRole = apperror.Validation("INVALID_USER_ROLE", "role does not exist").
+				WithDetails([]apperror.FieldError{{Field: "role", Message: "does not exist"}})
+
+	ErrWrongCurrentPassword = apperror.Validation("WRONG_CURRENT_PASSWORD", "current password is incorrect").
+				WithDetails([]apperror.FieldError{{Field: "current_password", Message: "is incorrect"}})
+	ErrPasswordUnchanged = apperror.Validation("PASSWORD_UNCHANGED", "new password must be different from the current password").
+				WithDetails([]apperror.FieldError{{Field: "new_password", Message: "must be different from the current password"}})
+)
+
 type UserService interface {
-	CreateUser(ctx context.Context, req request.CreateUserRequest, requesterRole string) (*response.UserResponse, error)
-	GetUsers(ctx context.Context, req request.PaginationQuery) (*response.PaginatedResponse[response.UserResponse], error)
-	UpdateUser(ctx context.Context, id uuid.UUID, req request.UpdateUserRequest, requesterRole string) (*response.UserResponse, error)
-	DeleteUser(ctx context.Context, id uuid.UUID, requesterRole string) error
+	CreateUser(ctx context.Context, req request.CreateUserRequest, actor model.Actor) (*response.UserResponse, error)
+	GetUsers(ctx context.Context, req request.UserListQuery, actor model.Actor) (*response.PaginatedResponse[response.UserResponse], error)
+	GetUser(ctx context.Context, id uuid.UUID) (*response.UserResponse, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, version int, req request.UpdateUserRequest, actor model.Actor) (*response.UserResponse, error)
+	DeleteUser(ctx context.Context, id uuid.UUID, version int, actor model.Actor) error
+	RestoreUser(ctx context.Context, id uuid.UUID) (*response.UserResponse, error)
+	RevokeUserSessions(ctx context.Context, id uuid.UUID, actor model.Actor) (int64, error)
+	GetLockouts(ctx context.Context) ([]response.LockoutResponse, error)
+	ClearLockout(ctx context.Context, id uuid.UUID, actor model.Actor) error
+	ResetTwoFactor(ctx context.Context, id uuid.UUID, actor model.Actor) error
+	GetUserAPIKeys(ctx context.Context, id uuid.UUID, actor model.Actor) ([]response.APIKeyResponse, error)
+	RevokeUserAPIKey(ctx context.Context, id, keyID uuid.UUID, actor model.Actor) error
+	GetUserWarehouses(ctx context.Context, id uuid.UUID) ([]response.WarehouseResponse, error)
+	SetUserWarehouses(ctx context.Context, id uuid.UUID, req request.SetUserWarehousesRequest, actor model.Actor) ([]response.WarehouseResponse, error)
+
+	// Self-service: every authenticated user manages their own profile.
+	GetProfile(ctx context.Context, userID uuid.UUID) (*response.UserResponse, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, version int, req request.UpdateProfileRequest) (*response.UserResponse, error)
+	ChangePassword(ctx context.Context, userID, currentLoginID uuid.UUID, req request.ChangePasswordRequest) (int64, error)
 }
 
 type userService struct {
-	repo   *repository.Repository
-	logger *zap.Logger
+	repo    *repository.Repository
+	authCfg config.AuthConfig // lockout settings shared with login, for wrong current passwords
+	authz   Authorizer
+	audit   Auditor
+	logger  *zap.Logger
 }
 
-func NewUserService(repo *repository.Repository, logger *zap.Logger) UserService {
-	return &userService{repo: repo, logger: logger}
+func NewUserService(repo *repository.Repository, authCfg config.AuthConfig, logger *zap.Logger) UserService {
+	return &userService{repo: repo, authCfg: authCfg, authz: NewAuthorizer(repo, logger), audit: NewAuditor(repo, logger), logger: logger}
 }
 
-// CreateUser handles the business logic for registering a new user.
-func (s *userService) CreateUser(ctx context.Context, req request.CreateUserRequest, requesterRole string) (*response.UserResponse, error) {
-	if requesterRole == string(model.RoleAdmin) && req.Role == string(model.RoleSuperAdmin) {
-		s.logger.Warn("Admin attempted to create a super_admin", zap.String("requester_role", requesterRole))
-		return nil, errors.New("forbidden: admin cannot create a super_admin")
+// findManageable fetches a user the actor wants to change and checks the actor may manage their role.
+func (s *userService) findManageable(ctx context.Context, actor model.Actor, id uuid.UUID) (*model.User, error) {
+	user, err := s.repo.User.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching user", zap.String("user_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	if err := s.authz.CanManageRole(ctx, actor, user.Role); err != nil {
+		return nil, err
 	}
+	return user, nil
+}
 
-	// 1. Validate the provided Role against allowed enums to ensure data integrity.
+// CreateUser handles the business logic for registering a new user.
+func (s *userService) CreateUser(ctx context.Context, req request.CreateUserRequest, actor model.Actor) (*response.UserResponse, error) {
+	// 1. The role must exist, and the requester may only hand out a role they outrank.
 	role := model.UserRole(req.Role)
-	if role != model.RoleSuperAdmin && role != model.RoleAdmin && role != model.RoleStaff {
-		return nil, errors.New("invalid user role. Must be super_admin, admin, or staff")
+	if err := s.authz.CanManageRole(ctx, actor, role); err != nil {
+		return nil, err
 	}
 
 	// 2. Hash the user's plaintext password securely.
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		s.logger.Error("Failed to hash password", zap.Error(err))
-		return nil, errors.New("internal server error")
+		return nil, apperror.Internal(err)
 	}
 
 	// 3. Construct the User model instance.
 	newUser := &model.User{
 		BaseModel:    model.BaseModel{ID: uuid.New()}, // Adjust based on your actual Base struct
 		Name:         req.Name,
-		Email:        req.Email,
+		Email:        strings.ToLower(req.Email), // matched case-insensitively at login and single sign-on
 		PasswordHash: hashedPassword,
 		Role:         role,
 	}
 
-	// 4. Save the new user to the database via the repository layer.
-	err = s.repo.User.Create(ctx, newUser)
+	// 4. Save the new user and its audit event together via the repository layer.
+	var resp response.UserResponse
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.User.Create(ctx, newUser); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrEmailTaken
+			}
+			s.logger.Error("Failed to insert user to DB", zap.Error(err), zap.String("email", req.Email))
+			return apperror.Internal(err)
+		}
+
+		// 5. Map the saved model to a safe response DTO, omitting sensitive data.
+		resp = response.ToUserResponse(newUser)
+		return s.audit.Record(ctx, model.AuditUserCreate, model.AuditEntityUser, newUser.ID, nil, resp)
+	})
 	if err != nil {
-		s.logger.Error("Failed to insert user to DB", zap.Error(err), zap.String("email", req.Email))
-		return nil, errors.New("email already exists or database error occurred")
+		return nil, err
 	}
-
-	// 5. Map the saved model to a safe response DTO, omitting sensitive data.
-	resp := response.ToUserResponse(newUser)
 	return &resp, nil
 }
 
-func (s *userService) GetUsers(ctx context.Context, req request.PaginationQuery) (*response.PaginatedResponse[response.UserResponse], error) {
-	// 1. Set default values if the URL does not provide page or limit
-	if req.Page < 1 {
-		req.Page = 1
-	}
-	if req.Limit < 1 {
-		req.Limit = 10
+// GetUsers lists active users. Including soft-deleted users needs the users:restore permission.
+func (s *userService) GetUsers(ctx context.Context, req request.UserListQuery, actor model.Actor) (*response.PaginatedResponse[response.UserResponse], error) {
+	// 🛡️ GUARD: Cuma yang boleh restore yang boleh lihat user yang sudah dihapus
+	if req.IncludeDeleted && !actor.Can(model.PermUsersRestore) {
+		return nil, ErrIncludeDeletedForbidden
 	}
 
+	// 1. Set default values if the URL does not provide page or limit
+	req.Normalize()
+	filter := repository.UserFilter{Search: req.Search, IncludeDeleted: req.IncludeDeleted}
+
 	// 2. Offset Formula: (Page - 1) * Limit
 	// Example: If Page 2 and Limit 10 are requested -> (2-1)*10 = 10. (The database skips the first 10 records)
-	offset := (req.Page - 1) * req.Limit
+	offset := req.Offset()
 
 	// 3. Query Repo: "What is the total number of records in the DB?"
-	totalItems, err := s.repo.User.Count(ctx, req.Search)
+	totalItems, err := s.repo.User.Count(ctx, filter)
 	if err != nil {
-		return nil, errors.New("failed to count users")
+		return nil, apperror.Internal(err)
 	}
 
 	// 4. Query Repo: "Fetch [Limit] records starting from the [Offset] position"
-	users, err := s.repo.User.FindAll(ctx, req.Limit, offset, req.Search)
+	users, err := s.repo.User.FindAll(ctx, req.Limit, offset, filter)
 	if err != nil {
-		return nil, errors.New("failed to fetch users")
+		return nil, apperror.Internal(err)
 	}
 
 	// 5. Map Database Models to Data Transfer Objects (DTOs)
@@ -115,66 +175,467 @@ func (s *userService) GetUsers(ctx context.Context, req request.PaginationQuery)
 	return &result, nil
 }
 
-// UpdateUser handles the business logic for updating a user's details.
-func (s *userService) UpdateUser(ctx context.Context, id uuid.UUID, req request.UpdateUserRequest, requesterRole string) (*response.UserResponse, error) {
-	// 1. Check if the user exists
+// GetUser returns a single active user, e.g. to read its current version before an update.
+func (s *userService) GetUser(ctx context.Context, id uuid.UUID) (*response.UserResponse, error) {
 	user, err := s.repo.User.FindByID(ctx, id)
 	if err != nil {
-		s.logger.Warn("Attempted to update a non-existent user", zap.String("user_id", id.String()), zap.Error(err))
-		return nil, errors.New("user not found")
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching user", zap.String("user_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
 	}
 
-	// 🛡️ GUARD 1: Admin tidak boleh mengedit data Super Admin
-	if requesterRole == string(model.RoleAdmin) && user.Role == model.RoleSuperAdmin {
-		s.logger.Warn("Admin attempted to modify a super_admin", zap.String("target_user_id", id.String()))
-		return nil, errors.New("forbidden: admin cannot modify a super_admin")
+	res := response.ToUserResponse(user)
+	return &res, nil
+}
+
+// UpdateUser handles the business logic for updating a user's details, provided the user is still at version.
+func (s *userService) UpdateUser(ctx context.Context, id uuid.UUID, version int, req request.UpdateUserRequest, actor model.Actor) (*response.UserResponse, error) {
+	// 1. Check that the user exists and that the requester outranks their current role...
+	user, err := s.findManageable(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Version != version {
+		return nil, ErrVersionMismatch
 	}
 
-	// 🛡️ GUARD 2: Admin tidak boleh me-naikkan jabatan seseorang menjadi Super Admin
-	if requesterRole == string(model.RoleAdmin) && req.Role == string(model.RoleSuperAdmin) {
-		s.logger.Warn("Admin attempted to promote someone to super_admin", zap.String("target_user_id", id.String()))
-		return nil, errors.New("forbidden: admin cannot promote a user to super_admin")
+	// 🛡️ GUARD: ...dan juga role barunya, biar gak bisa naikin jabatan orang di atas diri sendiri
+	role := model.UserRole(req.Role)
+	if role != user.Role {
+		if err := s.authz.CanManageRole(ctx, actor, role); err != nil {
+			return nil, err
+		}
 	}
 
 	// 2. Update the user model with new data
+	before := response.ToUserResponse(user)
 	user.Name = req.Name
-	user.Role = model.UserRole(req.Role)
-
-	// 3. Save the changes to the database
-	if err := s.repo.User.Update(ctx, user); err != nil {
-		s.logger.Error("Database error while updating user", zap.String("user_id", id.String()), zap.Error(err))
-		return nil, errors.New("internal server error")
+	user.Role = role
+
+	// 3. Save the changes and their audit event to the database
+	var res response.UserResponse
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.User.Update(ctx, user); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrUserNotFound
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while updating user", zap.String("user_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+
+		// 4. Map the updated model to a safe response DTO
+		res = response.ToUserResponse(user)
+		return s.audit.Record(ctx, model.AuditUserUpdate, model.AuditEntityUser, id, before, res)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("User updated successfully", zap.String("user_id", id.String()))
+	return &res, nil
+}
+
+// DeleteUser soft-deletes a user at version and revokes all of their sessions.
+// Their sales and stock movements keep referencing the user.
+func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID, version int, actor model.Actor) error {
+	// 1. Ensure the user exists and the requester may manage them before attempting to delete
+	user, err := s.findManageable(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if user.Version != version {
+		return ErrVersionMismatch
+	}
+
+	// 2. Execute the deletion
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.User.SoftDelete(ctx, id, version); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrUserNotFound
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while deleting user", zap.String("user_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		return s.audit.Record(ctx, model.AuditUserDelete, model.AuditEntityUser, id, response.ToUserResponse(user), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("User deleted successfully", zap.String("user_id", id.String()))
+	return nil
+}
+
+// RestoreUser brings a soft-deleted user back. They have to log in again.
+func (s *userService) RestoreUser(ctx context.Context, id uuid.UUID) (*response.UserResponse, error) {
+	var res response.UserResponse
+	err := withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.User.Restore(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrUserNotFound
+			}
+			s.logger.Error("Database error while restoring user", zap.String("user_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+
+		user, err := s.repo.User.FindByID(ctx, id)
+		if err != nil {
+			s.logger.Error("Database error while fetching restored user", zap.String("user_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		res = response.ToUserResponse(user)
+		return s.audit.Record(ctx, model.AuditUserRestore, model.AuditEntityUser, id, nil, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User restored successfully", zap.String("user_id", id.String()))
+	return &res, nil
+}
+
+// RevokeUserSessions force-signs a user out of every login. API keys are revoked one by one with
+// RevokeUserAPIKey.
+func (s *userService) RevokeUserSessions(ctx context.Context, id uuid.UUID, actor model.Actor) (int64, error) {
+	if _, err := s.findManageable(ctx, actor, id); err != nil {
+		return 0, err
+	}
+
+	revoked, err := s.repo.Session.RevokeAllLogins(ctx, id)
+	if err != nil {
+		s.logger.Error("Database error while revoking user sessions", zap.String("user_id", id.String()), zap.Error(err))
+		return 0, apperror.Internal(err)
+	}
+
+	s.logger.Info("User sessions revoked", zap.String("user_id", id.String()), zap.Int64("revoked", revoked))
+	return revoked, nil
+}
+
+// GetLockouts lists users with failed logins since their last success, including locked accounts.
+func (s *userService) GetLockouts(ctx context.Context) ([]response.LockoutResponse, error) {
+	users, err := s.repo.User.FindLockouts(ctx)
+	if err != nil {
+		s.logger.Error("Database error while listing lockouts", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	now := time.Now()
+	res := make([]response.LockoutResponse, 0, len(users))
+	for _, u := range users {
+		res = append(res, response.ToLockoutResponse(u, now))
+	}
+	return res, nil
+}
+
+// ClearLockout unlocks a user and resets their failed login counter.
+func (s *userService) ClearLockout(ctx context.Context, id uuid.UUID, actor model.Actor) error {
+	if _, err := s.findManageable(ctx, actor, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.User.ClearLockout(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		s.logger.Error("Database error while clearing lockout", zap.String("user_id", id.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("User lockout cleared", zap.String("user_id", id.String()))
+	return nil
+}
+
+// ResetTwoFactor removes a user's 2FA so they can log in after losing their authenticator and
+// recovery codes. If their role requires 2FA, the next login makes them enroll again.
+func (s *userService) ResetTwoFactor(ctx context.Context, id uuid.UUID, actor model.Actor) error {
+	if _, err := s.findManageable(ctx, actor, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.TwoFactor.Disable(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		s.logger.Error("Database error while resetting 2FA", zap.String("user_id", id.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("User two-factor authentication reset", zap.String("user_id", id.String()))
+	return nil
+}
+
+// GetUserAPIKeys lists a user's API keys that have not been revoked.
+func (s *userService) GetUserAPIKeys(ctx context.Context, id uuid.UUID, actor model.Actor) ([]response.APIKeyResponse, error) {
+	if _, err := s.findManageable(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.APIKey.FindByUser(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to fetch user api keys", zap.String("user_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := make([]response.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, response.ToAPIKeyResponse(k))
+	}
+	return res, nil
+}
+
+// RevokeUserAPIKey disables one of a user's API keys, e.g. one leaked from a script, without
+// signing the user out.
+func (s *userService) RevokeUserAPIKey(ctx context.Context, id, keyID uuid.UUID, actor model.Actor) error {
+	if _, err := s.findManageable(ctx, actor, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.APIKey.Revoke(ctx, id, keyID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		s.logger.Error("Database error while revoking user api key", zap.String("user_id", id.String()), zap.String("api_key_id", keyID.String()), zap.Error(err))
+		return apperror.Internal(err)
+	}
+
+	s.logger.Info("User API key revoked", zap.String("user_id", id.String()), zap.String("api_key_id", keyID.String()))
+	return nil
+}
+
+// GetUserWarehouses lists the warehouses a user is assigned to. Users whose role has
+// warehouses:all work in every warehouse regardless of this list.
+func (s *userService) GetUserWarehouses(ctx context.Context, id uuid.UUID) ([]response.WarehouseResponse, error) {
+	if _, err := s.repo.User.FindByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching user", zap.String("user_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	warehouses, err := s.repo.UserWarehouse.FindByUser(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to fetch user warehouses", zap.String("user_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	res := make([]response.WarehouseResponse, 0, len(warehouses))
+	for _, w := range warehouses {
+		res = append(res, response.ToWarehouseResponse(w))
+	}
+	return res, nil
+}
+
+// SetUserWarehouses replaces the warehouses a user is assigned to. The change applies from the
+// user's next request. A requester can only hand out warehouses they can see themselves.
+func (s *userService) SetUserWarehouses(ctx context.Context, id uuid.UUID, req request.SetUserWarehousesRequest, actor model.Actor) ([]response.WarehouseResponse, error) {
+	if _, err := s.findManageable(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	// 🛡️ GUARD: Gudang harus ada dan kelihatan sama yang nge-assign (repository sudah nge-filter scope-nya)
+	warehouseIDs := make([]uuid.UUID, 0, len(req.WarehouseIDs))
+	for _, warehouseID := range req.WarehouseIDs {
+		if slices.Contains(warehouseIDs, warehouseID) {
+			continue
+		}
+		if _, err := s.repo.Warehouse.FindByID(ctx, warehouseID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrWarehouseNotFound
+			}
+			s.logger.Error("Database error while fetching warehouse", zap.String("warehouse_id", warehouseID.String()), zap.Error(err))
+			return nil, apperror.Internal(err)
+		}
+		warehouseIDs = append(warehouseIDs, warehouseID)
+	}
+
+	// Reading the old assignment in the same transaction keeps the audited diff exact.
+	err := withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		previous, err := s.repo.UserWarehouse.FindByUser(ctx, id)
+		if err != nil {
+			s.logger.Error("Failed to fetch user warehouses", zap.String("user_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+
+		if err := s.repo.UserWarehouse.Replace(ctx, id, warehouseIDs); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrWarehouseNotFound
+			}
+			s.logger.Error("Database error while assigning warehouses", zap.String("user_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+
+		previousIDs := make([]uuid.UUID, 0, len(previous))
+		for _, w := range previous {
+			previousIDs = append(previousIDs, w.ID)
+		}
+		return s.audit.Record(ctx, model.AuditUserSetWarehouses, model.AuditEntityUser, id,
+			userWarehousesAudit(previousIDs), userWarehousesAudit(warehouseIDs))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User warehouses updated",
+		zap.String("user_id", id.String()),
+		zap.Int("warehouses", len(warehouseIDs)),
+		zap.String("updated_by", actor.UserID.String()),
+	)
+
+	return s.GetUserWarehouses(ctx, id)
+}
+
+// userWarehousesAudit is how a user's warehouse assignment appears in the audit trail. The IDs are
+// sorted so that only a different set, not a different order, shows up as a change.
+func userWarehousesAudit(ids []uuid.UUID) map[string][]uuid.UUID {
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	return map[string][]uuid.UUID{"warehouse_ids": sorted}
+}
+
+// GetProfile returns the caller's own user data.
+func (s *userService) GetProfile(ctx context.Context, userID uuid.UUID) (*response.UserResponse, error) {
+	user, err := s.repo.User.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching profile", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
 
-	// 4. Map the updated model to a safe response DTO
 	res := response.ToUserResponse(user)
 	return &res, nil
 }
 
-// DeleteUser handles the business logic for removing a user.
-func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID, requesterRole string) error {
-	// 1. Ensure the user exists before attempting to delete
-	user, err := s.repo.User.FindByID(ctx, id)
+// UpdateProfile changes the caller's own name, provided their profile is still at version. Role and
+// email stay admin-managed.
+func (s *userService) UpdateProfile(ctx context.Context, userID uuid.UUID, version int, req request.UpdateProfileRequest) (*response.UserResponse, error) {
+	user, err := s.repo.User.FindByID(ctx, userID)
 	if err != nil {
-		s.logger.Warn("Attempted to delete a non-existent user", zap.String("user_id", id.String()), zap.Error(err))
-		return errors.New("user not found")
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching profile", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	if user.Version != version {
+		return nil, ErrVersionMismatch
 	}
 
-	// 🛡️ GUARD: Admin tidak boleh menghapus Super Admin
-	if requesterRole == string(model.RoleAdmin) && user.Role == model.RoleSuperAdmin {
-		s.logger.Warn("Admin attempted to delete a super_admin", zap.String("target_user_id", id.String()))
-		return errors.New("forbidden: admin cannot delete a super_admin")
+	before := response.ToUserResponse(user)
+	user.Name = req.Name
+	var res response.UserResponse
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.User.Update(ctx, user); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrUserNotFound
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while updating profile", zap.String("user_id", userID.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		res = response.ToUserResponse(user)
+		return s.audit.Record(ctx, model.AuditUserUpdate, model.AuditEntityUser, userID, before, res)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. Execute the deletion
-	if err := s.repo.User.Delete(ctx, id); err != nil {
-		s.logger.Error("Database error while deleting user", zap.String("user_id", id.String()), zap.Error(err))
-		return errors.New("internal server error")
+	s.logger.Info("Profile updated", zap.String("user_id", userID.String()))
+	return &res, nil
+}
+
+// ChangePassword replaces the caller's password after checking the current one, then signs them out
+// of every other login. The login making the request stays signed in. It returns how many logins were revoked.
+func (s *userService) ChangePassword(ctx context.Context, userID, currentLoginID uuid.UUID, req request.ChangePasswordRequest) (int64, error) {
+	user, err := s.repo.User.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, ErrUserNotFound
+		}
+		s.logger.Error("Database error while fetching profile", zap.String("user_id", userID.String()), zap.Error(err))
+		return 0, apperror.Internal(err)
 	}
 
-	s.logger.Info("User deleted successfully", zap.String("user_id", id.String()))
+	// 🛡️ GUARD: Wajib tahu password lama, biar session yang dicuri gak bisa ganti password
+	if err := verifyCurrentPassword(ctx, s.repo, s.authCfg, s.logger, user, req.CurrentPassword, ErrWrongCurrentPassword, time.Now()); err != nil {
+		s.logger.Warn("Password change rejected", zap.String("user_id", userID.String()), zap.Error(err))
+		return 0, err
+	}
+	if utils.CheckPasswordHash(req.NewPassword, user.PasswordHash) {
+		return 0, ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.Error(err))
+		return 0, apperror.Internal(err)
+	}
+
+	revoked, err := s.repo.User.UpdatePassword(ctx, userID, hashedPassword, currentLoginID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, ErrUserNotFound
+		}
+		s.logger.Error("Database error while changing password", zap.String("user_id", userID.String()), zap.Error(err))
+		return 0, apperror.Internal(err)
+	}
+
+	s.logger.Info("Password changed, other sessions revoked", zap.String("user_id", userID.String()), zap.Int64("revoked", revoked))
+	return revoked, nil
+}
+
+// verifyCurrentPassword checks the password a signed-in user typed to confirm a sensitive change,
+// under the same lockout as the login form: a locked account is refused, a wrong password counts
+// as a failed login and the right one clears earlier failures. wrongErr is returned for a wrong
+// password that did not lock the account.
+func verifyCurrentPassword(ctx context.Context, repo *repository.Repository, cfg config.AuthConfig, logger *zap.Logger, user *model.User, password string, wrongErr error, now time.Time) error {
+	if err := accountLocked(user, now); err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return recordWrongPassword(ctx, repo, cfg, logger, user.ID, wrongErr, now)
+	}
+	if user.FailedLoginAttempts > 0 {
+		// Best effort, as after a successful login.
+		if err := repo.User.ResetFailedLogins(ctx, user.ID); err != nil {
+			logger.Error("Failed to reset failed login counter", zap.String("user_id", user.ID.String()), zap.Error(err))
+		}
+	}
+	return nil
+}
 
+// accountLocked returns ErrAccountLocked while user is locked out after failed logins.
+func accountLocked(user *model.User, now time.Time) error {
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return ErrAccountLocked.WithRetryAfter(user.LockedUntil.Sub(now))
+	}
 	return nil
 }
+
+// recordWrongPassword counts a wrong password or code from a signed-in user as a failed login, so
+// guessing through a stolen session locks the account just like guessing on the login form.
+// It returns wrongErr, or ErrAccountLocked once this failure locks the account.
+func recordWrongPassword(ctx context.Context, repo *repository.Repository, cfg config.AuthConfig, logger *zap.Logger, userID uuid.UUID, wrongErr error, now time.Time) error {
+	state, err := repo.User.RecordFailedLogin(ctx, userID, cfg.MaxFailedLogins, cfg.LockoutDuration)
+	if err != nil {
+		logger.Error("Failed to record failed login", zap.String("user_id", userID.String()), zap.Error(err))
+		return wrongErr
+	}
+	if err := accountLocked(state, now); err != nil {
+		logger.Warn("Account locked after repeated wrong passwords", zap.String("user_id", userID.String()))
+		return err
+	}
+	return wrongErr
+}
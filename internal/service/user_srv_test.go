@@ -3,35 +3,44 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"inventory-system/internal/config"
 	"inventory-system/internal/dto/request"
 	"inventory-system/internal/model"
 	"inventory-system/internal/repository"
+	"inventory-system/pkg/utils"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
+// testLockoutConfig locks an account after 5 wrong passwords for 15 minutes, as login does in tests.
+var testLockoutConfig = config.AuthConfig{MaxFailedLogins: 5, LockoutDuration: 15 * time.Minute}
+
 func TestUpdateUser_AdminCannotUpdateSuperAdmin(t *testing.T) {
 	// 1. SETUP: Siapkan Stuntman dan Logger dummy
 	mockUserRepo := new(repository.MockUserRepository)
+	mockRoleRepo := new(repository.MockRoleRepository)
 	logger := zap.NewNop() // Nop = No Operation (Logger yang gak nge-print apa-apa biar testnya bersih)
 
 	// Inject stuntman ke dalam struktur Repository utama
 	mockRepos := &repository.Repository{
 		User: mockUserRepo,
+		Role: mockRoleRepo,
 	}
 
 	// Bikin Service-nya menggunakan Mock Repository
-	userService := NewUserService(mockRepos, logger)
+	userService := NewUserService(mockRepos, testLockoutConfig, logger)
 
 	// 2. DATA DUMMY
 	targetUserID := uuid.New()
 	targetUserRole := model.RoleSuperAdmin // Targetnya adalah Bos Besar!
 
-	requesterRole := string(model.RoleAdmin) // Yang nge-request adalah Admin biasa
+	requester := adminActor() // Yang nge-request adalah Admin biasa
 	reqPayload := request.UpdateUserRequest{
 		Name: "Hacker Jahat",
 		Role: string(model.RoleAdmin),
@@ -40,22 +49,261 @@ func TestUpdateUser_AdminCannotUpdateSuperAdmin(t *testing.T) {
 	// 3. ATUR SKENARIO MOCK ("Kalau Service manggil FindByID, kasih targetUserRole ini ya!")
 	mockUserRepo.On("FindByID", mock.Anything, targetUserID).Return(&model.User{
 		BaseModel: model.BaseModel{ // <-- Panggil nama Base struct-nya di sini
-			ID: targetUserID,
+			ID:      targetUserID,
+			Version: 1,
 		},
 		Role: targetUserRole,
 	}, nil)
+	// Permission super_admin lebih banyak dari punya admin
+	mockRoleRepo.On("FindByName", mock.Anything, model.RoleSuperAdmin).Return(&model.Role{
+		Name:        model.RoleSuperAdmin,
+		Permissions: superAdminActor().Permissions,
+	}, nil)
 
 	// Perhatikan: Kita TIDAK nge-mock Update(), karena logika kita HARUSNYA
 	// menolak request sebelum nyentuh db.Update(). Kalau Update() terpanggil, berarti test GAGAL.
 
 	// 4. EKSEKUSI: Panggil fungsi aslinya
-	res, err := userService.UpdateUser(context.Background(), targetUserID, reqPayload, requesterRole)
+	res, err := userService.UpdateUser(context.Background(), targetUserID, 1, reqPayload, requester)
 
 	// 5. VALIDASI (Assert): Pastikan hasilnya sesuai harapan kita!
-	assert.Error(t, err)                                                         // Harus ada error
-	assert.Nil(t, res)                                                           // Respon harus kosong (nil)
-	assert.Equal(t, "forbidden: admin cannot modify a super_admin", err.Error()) // Pesan error harus presisi
+	assert.ErrorIs(t, err, ErrRoleOutranksYou)                                          // Harus ada error
+	assert.Nil(t, res)                                                                  // Respon harus kosong (nil)
+	assert.Equal(t, "forbidden: the role has permissions you do not have", err.Error()) // Pesan error harus presisi
 
 	// Pastikan Stuntman bekerja sesuai skenario (FindByID dipanggil 1x)
 	mockUserRepo.AssertExpectations(t)
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func TestDeleteUser_NotFound(t *testing.T) {
+	mockUserRepo := new(repository.MockUserRepository)
+	userService := NewUserService(&repository.Repository{User: mockUserRepo}, testLockoutConfig, zap.NewNop())
+
+	targetUserID := uuid.New()
+	mockUserRepo.On("FindByID", mock.Anything, targetUserID).Return(nil, repository.ErrNotFound)
+
+	err := userService.DeleteUser(context.Background(), targetUserID, 1, superAdminActor())
+
+	// Handler cukup lihat tipe error-nya, bukan string-nya
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUpdateProfile_VersionMismatch(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("stale If-Match", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		userService := NewUserService(&repository.Repository{User: mockUserRepo}, testLockoutConfig, zap.NewNop())
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&model.User{BaseModel: model.BaseModel{ID: userID, Version: 4}}, nil)
+
+		// Client masih pegang versi 3; Update() gak boleh kepanggil
+		res, err := userService.UpdateProfile(context.Background(), userID, 3, request.UpdateProfileRequest{Name: "Nama Baru"})
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("changed between read and write", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		userService := NewUserService(&repository.Repository{User: mockUserRepo, Tx: &repository.FakeTxManager{}}, testLockoutConfig, zap.NewNop())
+		mockUserRepo.On("FindByID", mock.Anything, userID).Return(&model.User{BaseModel: model.BaseModel{ID: userID, Version: 4}}, nil)
+		mockUserRepo.On("Update", mock.Anything, mock.Anything).Return(repository.ErrVersionConflict)
+
+		_, err := userService.UpdateProfile(context.Background(), userID, 4, request.UpdateProfileRequest{Name: "Nama Baru"})
+
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+	})
+}
+
+func TestGetUsers_IncludeDeletedIsSuperAdminOnly(t *testing.T) {
+	mockUserRepo := new(repository.MockUserRepository)
+	userService := NewUserService(&repository.Repository{User: mockUserRepo}, testLockoutConfig, zap.NewNop())
+
+	query := request.UserListQuery{IncludeDeleted: true}
+
+	// Admin biasa ditolak sebelum nyentuh DB
+	_, err := userService.GetUsers(context.Background(), query, adminActor())
+	assert.ErrorIs(t, err, ErrIncludeDeletedForbidden)
+
+	// Super Admin boleh, dan filter-nya diteruskan ke repository
+	filter := repository.UserFilter{IncludeDeleted: true}
+	mockUserRepo.On("Count", mock.Anything, filter).Return(int64(1), nil)
+	mockUserRepo.On("FindAll", mock.Anything, 10, 0, filter).Return([]*model.User{{Name: "Mantan Staff"}}, nil)
+
+	res, err := userService.GetUsers(context.Background(), query, superAdminActor())
+	assert.NoError(t, err)
+	assert.Len(t, res.Data, 1)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	lockedUntil := time.Now().Add(10 * time.Minute)
+
+	tests := []struct {
+		name     string
+		user     model.User
+		req      request.ChangePasswordRequest
+		recorded *model.User // state returned by RecordFailedLogin; nil if it must not be called
+		reset    bool        // ResetFailedLogins is expected
+		wantErr  error
+	}{
+		{"wrong current password counts a failed login", model.User{}, request.ChangePasswordRequest{CurrentPassword: "tebak-tebak1", NewPassword: "n3w-Passw0rd"}, &model.User{FailedLoginAttempts: 1}, false, ErrWrongCurrentPassword},
+		{"wrong current password reaching the threshold locks", model.User{FailedLoginAttempts: 4}, request.ChangePasswordRequest{CurrentPassword: "tebak-tebak1", NewPassword: "n3w-Passw0rd"}, &model.User{FailedLoginAttempts: 5, LockedUntil: &lockedUntil}, false, ErrAccountLocked},
+		{"locked account rejects even the right password", model.User{FailedLoginAttempts: 5, LockedUntil: &lockedUntil}, request.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "n3w-Passw0rd"}, nil, false, ErrAccountLocked},
+		{"same as current password", model.User{}, request.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "password123"}, nil, false, ErrPasswordUnchanged},
+		{"success keeps only the current login", model.User{}, request.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "n3w-Passw0rd"}, nil, false, nil},
+		{"success clears earlier failures", model.User{FailedLoginAttempts: 2}, request.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "n3w-Passw0rd"}, nil, true, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			userService := NewUserService(&repository.Repository{User: mockUserRepo}, testLockoutConfig, zap.NewNop())
+
+			userID, loginID := uuid.New(), uuid.New()
+			user := tc.user
+			user.ID, user.PasswordHash = userID, hash
+			mockUserRepo.On("FindByID", mock.Anything, userID).Return(&user, nil)
+			if tc.recorded != nil {
+				mockUserRepo.On("RecordFailedLogin", mock.Anything, userID, 5, 15*time.Minute).Return(tc.recorded, nil)
+			}
+			if tc.reset {
+				mockUserRepo.On("ResetFailedLogins", mock.Anything, userID).Return(nil)
+			}
+			if tc.wantErr == nil {
+				mockUserRepo.On("UpdatePassword", mock.Anything, userID, mock.MatchedBy(func(newHash string) bool {
+					return utils.CheckPasswordHash(tc.req.NewPassword, newHash)
+				}), loginID).Return(int64(2), nil)
+			}
+
+			revoked, err := userService.ChangePassword(context.Background(), userID, loginID, tc.req)
+
+			mockUserRepo.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockUserRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), revoked)
+		})
+	}
+}
+
+func TestSetUserWarehouses(t *testing.T) {
+	targetUserID := uuid.New()
+	jakarta, bandung := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		req     []uuid.UUID
+		wantErr error
+	}{
+		{"unknown or out-of-scope warehouse", []uuid.UUID{jakarta, uuid.New()}, ErrWarehouseNotFound},
+		{"success drops duplicates", []uuid.UUID{jakarta, bandung, jakarta}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockRoleRepo := new(repository.MockRoleRepository)
+			mockWarehouseRepo := new(repository.MockWarehouseRepository)
+			mockUserWarehouseRepo := new(repository.MockUserWarehouseRepository)
+			txManager := new(repository.FakeTxManager)
+			userService := NewUserService(&repository.Repository{
+				User:          mockUserRepo,
+				Role:          mockRoleRepo,
+				Warehouse:     mockWarehouseRepo,
+				UserWarehouse: mockUserWarehouseRepo,
+				Tx:            txManager,
+			}, testLockoutConfig, zap.NewNop())
+
+			mockUserRepo.On("FindByID", mock.Anything, targetUserID).Return(&model.User{BaseModel: model.BaseModel{ID: targetUserID}, Role: model.RoleStaff}, nil)
+			mockRoleRepo.On("FindByName", mock.Anything, model.RoleStaff).Return(&model.Role{
+				Name:        model.RoleStaff,
+				Permissions: model.Permissions{model.PermStockMove, model.PermSalesCreate},
+			}, nil)
+			mockWarehouseRepo.On("FindByID", mock.Anything, jakarta).Return(&model.Warehouse{BaseModel: model.BaseModel{ID: jakarta}}, nil)
+			mockWarehouseRepo.On("FindByID", mock.Anything, bandung).Return(&model.Warehouse{BaseModel: model.BaseModel{ID: bandung}}, nil)
+			mockWarehouseRepo.On("FindByID", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound)
+
+			if tc.wantErr == nil {
+				mockUserWarehouseRepo.On("Replace", mock.Anything, targetUserID, []uuid.UUID{jakarta, bandung}).Return(nil)
+				mockUserWarehouseRepo.On("FindByUser", mock.Anything, targetUserID).Return([]*model.Warehouse{{BaseModel: model.BaseModel{ID: bandung}}, {BaseModel: model.BaseModel{ID: jakarta}}}, nil)
+			}
+
+			res, err := userService.SetUserWarehouses(context.Background(), targetUserID, request.SetUserWarehousesRequest{WarehouseIDs: tc.req}, adminActor())
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockUserWarehouseRepo.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, res, 2)
+			mockUserWarehouseRepo.AssertExpectations(t)
+			assert.Equal(t, 1, txManager.Calls)
+		})
+	}
+}
+
+func TestRevokeUserAPIKey(t *testing.T) {
+	targetUserID, keyID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{"key of someone else or already revoked", repository.ErrNotFound, ErrAPIKeyNotFound},
+		{"success", nil, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(repository.MockUserRepository)
+			mockRoleRepo := new(repository.MockRoleRepository)
+			mockAPIKeyRepo := new(repository.MockAPIKeyRepository)
+			userService := NewUserService(&repository.Repository{User: mockUserRepo, Role: mockRoleRepo, APIKey: mockAPIKeyRepo}, testLockoutConfig, zap.NewNop())
+
+			mockUserRepo.On("FindByID", mock.Anything, targetUserID).Return(&model.User{BaseModel: model.BaseModel{ID: targetUserID}, Role: model.RoleStaff}, nil)
+			mockRoleRepo.On("FindByName", mock.Anything, model.RoleStaff).Return(&model.Role{
+				Name:        model.RoleStaff,
+				Permissions: model.Permissions{model.PermStockMove, model.PermSalesCreate},
+			}, nil)
+			// Revoke selalu dibatasi user pemilik key, jadi key milik user lain gak bisa kena
+			mockAPIKeyRepo.On("Revoke", mock.Anything, targetUserID, keyID).Return(tc.repoErr)
+
+			err := userService.RevokeUserAPIKey(context.Background(), targetUserID, keyID, adminActor())
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			mockAPIKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	mockUserRepo := new(repository.MockUserRepository)
+	userService := NewUserService(&repository.Repository{User: mockUserRepo}, testLockoutConfig, zap.NewNop())
+
+	userID, missingID := uuid.New(), uuid.New()
+	mockUserRepo.On("FindByID", mock.Anything, userID).Return(&model.User{BaseModel: model.BaseModel{ID: userID, Version: 4}, Name: "Staff Satu"}, nil)
+	mockUserRepo.On("FindByID", mock.Anything, missingID).Return(nil, repository.ErrNotFound)
+
+	res, err := userService.GetUser(context.Background(), userID)
+	require.NoError(t, err)
+	// Versinya dipakai handler buat ETag
+	assert.Equal(t, 4, res.Version)
+
+	_, err = userService.GetUser(context.Background(), missingID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
 }
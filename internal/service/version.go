@@ -0,0 +1,7 @@
+package service
+
+import "inventory-system/pkg/apperror"
+
+// ErrVersionMismatch is returned when an update or delete names a version (the ETag sent as If-Match)
+// that the resource has moved on from. The client has to re-read it and decide again.
+var ErrVersionMismatch = apperror.PreconditionFailed("VERSION_MISMATCH", "the resource was changed by someone else since you read it; fetch it again and retry")
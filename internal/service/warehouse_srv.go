@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/dto/response"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrWarehouseNotFound     = apperror.NotFound("WAREHOUSE_NOT_FOUND", "warehouse not found")
+	ErrWarehouseNameRequired = apperror.Validation("WAREHOUSE_NAME_REQUIRED", "warehouse name is required")
+	ErrWarehouseHasStock     = apperror.Conflict("WAREHOUSE_HAS_STOCK", "warehouse still has shelves holding stock; move or sell the stock first")
+)
+
+type WarehouseService interface {
+	CreateWarehouse(ctx context.Context, req request.CreateWarehouseRequest) (*response.WarehouseResponse, error)
+	GetWarehouses(ctx context.Context, req request.PaginationQuery) (*response.PaginatedResponse[response.WarehouseResponse], error)
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*response.WarehouseResponse, error)
+	UpdateWarehouse(ctx context.Context, id uuid.UUID, version int, req request.UpdateWarehouseRequest) (*response.WarehouseResponse, error)
+	DeleteWarehouse(ctx context.Context, id uuid.UUID, version int) error
+	RestoreWarehouse(ctx context.Context, id uuid.UUID) (*response.WarehouseResponse, error)
+}
+
+type warehouseService struct {
+	repo   *repository.Repository
+	audit  Auditor
+	logger *zap.Logger
+}
+
+func NewWarehouseService(repo *repository.Repository, logger *zap.Logger) WarehouseService {
+	return &warehouseService{repo: repo, audit: NewAuditor(repo, logger), logger: logger}
+}
+
+// CreateWarehouse registers a new warehouse.
+func (s *warehouseService) CreateWarehouse(ctx context.Context, req request.CreateWarehouseRequest) (*response.WarehouseResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrWarehouseNameRequired
+	}
+
+	warehouse := &model.Warehouse{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		Name:      name,
+		Location:  strings.TrimSpace(req.Location),
+	}
+
+	var res response.WarehouseResponse
+	err := withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.Warehouse.Create(ctx, warehouse); err != nil {
+			s.logger.Error("Failed to insert warehouse to DB", zap.Error(err), zap.String("name", name))
+			return apperror.Internal(err)
+		}
+		res = response.ToWarehouseResponse(warehouse)
+		return s.audit.Record(ctx, model.AuditWarehouseCreate, model.AuditEntityWarehouse, warehouse.ID, nil, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Warehouse created successfully", zap.String("warehouse_id", warehouse.ID.String()))
+	return &res, nil
+}
+
+// GetWarehouses returns a paginated list of active warehouses filtered by name or location.
+func (s *warehouseService) GetWarehouses(ctx context.Context, req request.PaginationQuery) (*response.PaginatedResponse[response.WarehouseResponse], error) {
+	req.Normalize()
+
+	totalItems, err := s.repo.Warehouse.Count(ctx, req.Search)
+	if err != nil {
+		s.logger.Error("Failed to count warehouses", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	warehouses, err := s.repo.Warehouse.FindAll(ctx, req.Limit, req.Offset(), req.Search)
+	if err != nil {
+		s.logger.Error("Failed to fetch warehouses", zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+
+	warehouseResponses := make([]response.WarehouseResponse, 0, len(warehouses))
+	for _, w := range warehouses {
+		warehouseResponses = append(warehouseResponses, response.ToWarehouseResponse(w))
+	}
+
+	result := response.NewPaginatedResponse(warehouseResponses, req.Page, req.Limit, totalItems)
+	return &result, nil
+}
+
+// GetWarehouse returns a single active warehouse.
+func (s *warehouseService) GetWarehouse(ctx context.Context, id uuid.UUID) (*response.WarehouseResponse, error) {
+	warehouse, err := s.findWarehouse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := response.ToWarehouseResponse(warehouse)
+	return &res, nil
+}
+
+// UpdateWarehouse changes a warehouse's name and location, provided it is still at version.
+func (s *warehouseService) UpdateWarehouse(ctx context.Context, id uuid.UUID, version int, req request.UpdateWarehouseRequest) (*response.WarehouseResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrWarehouseNameRequired
+	}
+
+	warehouse, err := s.findWarehouse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if warehouse.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	before := response.ToWarehouseResponse(warehouse)
+	warehouse.Name = name
+	warehouse.Location = strings.TrimSpace(req.Location)
+
+	var res response.WarehouseResponse
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.Warehouse.Update(ctx, warehouse); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrWarehouseNotFound
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while updating warehouse", zap.String("warehouse_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		res = response.ToWarehouseResponse(warehouse)
+		return s.audit.Record(ctx, model.AuditWarehouseUpdate, model.AuditEntityWarehouse, id, before, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Warehouse updated successfully", zap.String("warehouse_id", id.String()))
+	return &res, nil
+}
+
+// DeleteWarehouse soft-deletes a warehouse at version, refusing when any of its shelves still hold stock.
+func (s *warehouseService) DeleteWarehouse(ctx context.Context, id uuid.UUID, version int) error {
+	warehouse, err := s.findWarehouse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if warehouse.Version != version {
+		return ErrVersionMismatch
+	}
+
+	// 🛡️ GUARD: Jangan hapus gudang yang raknya masih ada barang. Cek & hapus dalam satu transaksi
+	// yang mengunci rak & barangnya, biar stok gak bisa masuk di antara keduanya.
+	err = withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		hasStock, err := s.repo.Warehouse.HasStockedShelves(ctx, id)
+		if err != nil {
+			s.logger.Error("Database error while checking warehouse stock", zap.String("warehouse_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		if hasStock {
+			s.logger.Warn("Attempted to delete a warehouse that still holds stock", zap.String("warehouse_id", id.String()))
+			return ErrWarehouseHasStock
+		}
+
+		if err := s.repo.Warehouse.SoftDelete(ctx, id, version); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				return ErrWarehouseNotFound
+			case errors.Is(err, repository.ErrVersionConflict):
+				return ErrVersionMismatch
+			}
+			s.logger.Error("Database error while deleting warehouse", zap.String("warehouse_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+		return s.audit.Record(ctx, model.AuditWarehouseDelete, model.AuditEntityWarehouse, id, response.ToWarehouseResponse(warehouse), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Warehouse deleted successfully", zap.String("warehouse_id", id.String()))
+	return nil
+}
+
+// RestoreWarehouse brings a soft-deleted warehouse back.
+func (s *warehouseService) RestoreWarehouse(ctx context.Context, id uuid.UUID) (*response.WarehouseResponse, error) {
+	var res *response.WarehouseResponse
+	err := withinTx(ctx, s.repo, s.logger, func(ctx context.Context) error {
+		if err := s.repo.Warehouse.Restore(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrWarehouseNotFound
+			}
+			s.logger.Error("Database error while restoring warehouse", zap.String("warehouse_id", id.String()), zap.Error(err))
+			return apperror.Internal(err)
+		}
+
+		var err error
+		if res, err = s.GetWarehouse(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditWarehouseRestore, model.AuditEntityWarehouse, id, nil, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Warehouse restored successfully", zap.String("warehouse_id", id.String()))
+	return res, nil
+}
+
+func (s *warehouseService) findWarehouse(ctx context.Context, id uuid.UUID) (*model.Warehouse, error) {
+	warehouse, err := s.repo.Warehouse.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWarehouseNotFound
+		}
+		s.logger.Error("Database error while fetching warehouse", zap.String("warehouse_id", id.String()), zap.Error(err))
+		return nil, apperror.Internal(err)
+	}
+	return warehouse, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"inventory-system/internal/dto/request"
+	"inventory-system/internal/model"
+	"inventory-system/internal/repository"
+	"inventory-system/pkg/apperror"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestDeleteWarehouse_RefusedWhenShelvesHoldStock(t *testing.T) {
+	// 1. SETUP
+	mockWarehouseRepo := new(repository.MockWarehouseRepository)
+	txManager := &repository.FakeTxManager{}
+	warehouseService := NewWarehouseService(&repository.Repository{Warehouse: mockWarehouseRepo, Tx: txManager}, zap.NewNop())
+
+	warehouseID := uuid.New()
+	mockWarehouseRepo.On("FindByID", mock.Anything, warehouseID).Return(&model.Warehouse{
+		BaseModel: model.BaseModel{ID: warehouseID, Version: 2},
+		Name:      "Gudang Utama",
+	}, nil)
+	mockWarehouseRepo.On("HasStockedShelves", mock.Anything, warehouseID).Return(true, nil)
+
+	// SoftDelete sengaja TIDAK di-mock: kalau kepanggil, test otomatis gagal.
+
+	// 2. EKSEKUSI
+	err := warehouseService.DeleteWarehouse(context.Background(), warehouseID, 2)
+
+	// 3. VALIDASI
+	assert.ErrorIs(t, err, ErrWarehouseHasStock)
+	// Cek stok & hapus harus di transaksi yang sama
+	assert.Equal(t, 1, txManager.Calls)
+	mockWarehouseRepo.AssertExpectations(t)
+}
+
+func TestDeleteWarehouse_NotFound(t *testing.T) {
+	mockWarehouseRepo := new(repository.MockWarehouseRepository)
+	warehouseService := NewWarehouseService(&repository.Repository{Warehouse: mockWarehouseRepo}, zap.NewNop())
+
+	warehouseID := uuid.New()
+	mockWarehouseRepo.On("FindByID", mock.Anything, warehouseID).Return(nil, repository.ErrNotFound)
+
+	err := warehouseService.DeleteWarehouse(context.Background(), warehouseID, 1)
+
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	mockWarehouseRepo.AssertExpectations(t)
+}
+
+func TestUpdateWarehouse_VersionMismatch(t *testing.T) {
+	warehouseID := uuid.New()
+	current := &model.Warehouse{BaseModel: model.BaseModel{ID: warehouseID, Version: 4}, Name: "Gudang Utama"}
+
+	t.Run("client read an older version", func(t *testing.T) {
+		mockWarehouseRepo := new(repository.MockWarehouseRepository)
+		warehouseService := NewWarehouseService(&repository.Repository{Warehouse: mockWarehouseRepo}, zap.NewNop())
+		mockWarehouseRepo.On("FindByID", mock.Anything, warehouseID).Return(current, nil)
+
+		_, err := warehouseService.UpdateWarehouse(context.Background(), warehouseID, 3, request.UpdateWarehouseRequest{Name: "Gudang Baru"})
+
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+		mockWarehouseRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("someone else saved first", func(t *testing.T) {
+		// Versinya masih cocok pas dibaca, tapi keburu diubah orang lain sebelum UPDATE jalan
+		mockWarehouseRepo := new(repository.MockWarehouseRepository)
+		warehouseService := NewWarehouseService(&repository.Repository{Warehouse: mockWarehouseRepo, Tx: &repository.FakeTxManager{}}, zap.NewNop())
+		mockWarehouseRepo.On("FindByID", mock.Anything, warehouseID).Return(current, nil)
+		mockWarehouseRepo.On("Update", mock.Anything, mock.MatchedBy(func(w *model.Warehouse) bool {
+			return w.Version == 4
+		})).Return(repository.ErrVersionConflict)
+
+		_, err := warehouseService.UpdateWarehouse(context.Background(), warehouseID, 4, request.UpdateWarehouseRequest{Name: "Gudang Baru"})
+
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+		assert.Equal(t, apperror.KindPreconditionFailed, apperror.As(err).Kind)
+	})
+}
+
+func TestCreateWarehouse_FailedAuditUndoesTheChange(t *testing.T) {
+	mockWarehouseRepo := new(repository.MockWarehouseRepository)
+	mockAuditRepo := new(repository.MockAuditRepository)
+	txManager := &repository.FakeTxManager{}
+	warehouseService := NewWarehouseService(&repository.Repository{Warehouse: mockWarehouseRepo, Audit: mockAuditRepo, Tx: txManager}, zap.NewNop())
+
+	mockWarehouseRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockAuditRepo.On("Append", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+	res, err := warehouseService.CreateWarehouse(context.Background(), request.CreateWarehouseRequest{Name: "Gudang Baru"})
+
+	// Error dari audit dibalikin ke transaksi, jadi gudangnya ikut di-rollback
+	assert.Nil(t, res)
+	assert.Equal(t, apperror.KindInternal, apperror.As(err).Kind)
+	assert.Equal(t, 1, txManager.Calls)
+	mockAuditRepo.AssertExpectations(t)
+}
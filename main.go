@@ -22,7 +22,12 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer " followed by your UUID token.
+// @description Type "Bearer " followed by your access token.
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description A personal API key from POST /api/v1/me/api-keys. Accepted wherever BearerAuth is, except where passwords, 2FA, sessions and API keys are managed.
 func main() {
 	cmd.Execute()
 }
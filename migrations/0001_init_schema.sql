@@ -1,3 +1,4 @@
+-- +migrate Up
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 -- ==========================================
@@ -119,3 +120,15 @@ CREATE TABLE stock_logs (
 );
 CREATE INDEX idx_stock_logs_item_id ON stock_logs(item_id);
 CREATE INDEX idx_stock_logs_created_at ON stock_logs(created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS stock_logs;
+DROP TABLE IF EXISTS sale_items;
+DROP TABLE IF EXISTS sales;
+DROP TABLE IF EXISTS items;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS shelves;
+DROP TABLE IF EXISTS warehouses;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
+DROP EXTENSION IF EXISTS "uuid-ossp";
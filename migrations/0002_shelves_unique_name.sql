@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Shelf names must be unique within a warehouse (case-insensitive), ignoring soft-deleted shelves.
+CREATE UNIQUE INDEX uq_shelves_warehouse_name ON shelves (warehouse_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS uq_shelves_warehouse_name;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Categories can now be nested, e.g. "Beverages > Soft Drinks > Cola".
+ALTER TABLE categories ADD COLUMN parent_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+-- Sibling names must be unique (case-insensitive), ignoring soft-deleted categories.
+CREATE UNIQUE INDEX uq_categories_parent_name
+    ON categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), LOWER(name))
+    WHERE deleted_at IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS uq_categories_parent_name;
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
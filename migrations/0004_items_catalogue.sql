@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SKUs are unique among active items only, so a soft-deleted item no longer blocks its SKU.
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_sku_key;
+CREATE UNIQUE INDEX uq_items_sku ON items (UPPER(sku)) WHERE deleted_at IS NULL;
+
+ALTER TABLE items ADD CONSTRAINT chk_items_price_non_negative CHECK (price >= 0);
+
+-- +migrate Down
+ALTER TABLE items DROP CONSTRAINT IF EXISTS chk_items_price_non_negative;
+DROP INDEX IF EXISTS uq_items_sku;
+ALTER TABLE items ADD CONSTRAINT items_sku_key UNIQUE (sku);
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Stock may never go negative; the ledger service checks this too, the constraint is the backstop.
+ALTER TABLE items ADD CONSTRAINT chk_items_stock_non_negative CHECK (stock >= 0);
+
+ALTER TABLE stock_logs ADD CONSTRAINT chk_stock_logs_movement_type
+    CHECK (movement_type IN ('IN', 'OUT', 'ADJUSTMENT'));
+
+-- Movements are always listed per item, newest first.
+DROP INDEX IF EXISTS idx_stock_logs_item_id;
+CREATE INDEX idx_stock_logs_item_id_created_at ON stock_logs(item_id, created_at DESC);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_stock_logs_item_id_created_at;
+CREATE INDEX idx_stock_logs_item_id ON stock_logs(item_id);
+ALTER TABLE stock_logs DROP CONSTRAINT IF EXISTS chk_stock_logs_movement_type;
+ALTER TABLE items DROP CONSTRAINT IF EXISTS chk_items_stock_non_negative;
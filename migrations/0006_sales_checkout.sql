@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE sales ADD CONSTRAINT chk_sales_total_non_negative CHECK (total_amount >= 0);
+
+ALTER TABLE sale_items ADD CONSTRAINT chk_sale_items_quantity_positive CHECK (quantity > 0);
+ALTER TABLE sale_items ADD CONSTRAINT chk_sale_items_amounts_non_negative CHECK (unit_price >= 0 AND subtotal >= 0);
+
+-- Checkout merges repeated cart lines, so an item appears at most once per sale.
+ALTER TABLE sale_items ADD CONSTRAINT uq_sale_items_sale_item UNIQUE (sale_id, item_id);
+
+-- Stock movements caused by a sale are looked up by the sale id.
+CREATE INDEX idx_stock_logs_reference_id ON stock_logs(reference_id) WHERE reference_id IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_stock_logs_reference_id;
+ALTER TABLE sale_items DROP CONSTRAINT IF EXISTS uq_sale_items_sale_item;
+ALTER TABLE sale_items DROP CONSTRAINT IF EXISTS chk_sale_items_amounts_non_negative;
+ALTER TABLE sale_items DROP CONSTRAINT IF EXISTS chk_sale_items_quantity_positive;
+ALTER TABLE sales DROP CONSTRAINT IF EXISTS chk_sales_total_non_negative;
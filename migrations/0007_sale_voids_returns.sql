@@ -0,0 +1,45 @@
+-- +migrate Up
+ALTER TABLE sales
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'completed',
+    ADD COLUMN voided_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    ADD COLUMN voided_by UUID DEFAULT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    ADD COLUMN void_reason TEXT DEFAULT NULL,
+    ADD CONSTRAINT chk_sales_status CHECK (status IN ('completed', 'voided'));
+
+-- How much of each line has come back; a line can never be returned beyond what was sold.
+ALTER TABLE sale_items
+    ADD COLUMN returned_quantity INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_sale_items_returned_quantity CHECK (returned_quantity >= 0 AND returned_quantity <= quantity);
+
+CREATE TABLE sale_returns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sale_id UUID NOT NULL REFERENCES sales(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    reason TEXT,
+    refund_amount DECIMAL(15, 2) NOT NULL CHECK (refund_amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_sale_returns_sale_id ON sale_returns(sale_id);
+
+CREATE TABLE sale_return_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    return_id UUID NOT NULL REFERENCES sale_returns(id) ON DELETE CASCADE,
+    sale_item_id UUID NOT NULL REFERENCES sale_items(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    refund_amount DECIMAL(15, 2) NOT NULL CHECK (refund_amount >= 0),
+    UNIQUE (return_id, sale_item_id)
+);
+CREATE INDEX idx_sale_return_items_sale_item_id ON sale_return_items(sale_item_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS sale_return_items;
+DROP TABLE IF EXISTS sale_returns;
+ALTER TABLE sale_items
+    DROP CONSTRAINT IF EXISTS chk_sale_items_returned_quantity,
+    DROP COLUMN IF EXISTS returned_quantity;
+ALTER TABLE sales
+    DROP CONSTRAINT IF EXISTS chk_sales_status,
+    DROP COLUMN IF EXISTS void_reason,
+    DROP COLUMN IF EXISTS voided_by,
+    DROP COLUMN IF EXISTS voided_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,21 @@
+-- +migrate Up
+-- Every login starts a token family; refreshing rotates tokens inside the same family.
+ALTER TABLE sessions ADD COLUMN family_id UUID DEFAULT NULL;
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
+
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY, -- Generate token UUID dari backend (Golang)
+    family_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE DEFAULT NULL, -- set once the token has been exchanged
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS refresh_tokens;
+DROP INDEX IF EXISTS idx_sessions_family_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,41 @@
+-- +migrate Up
+-- One row per login (token family), so a user can see and revoke where they are signed in.
+CREATE TABLE login_sessions (
+    id UUID PRIMARY KEY, -- sama dengan family_id di sessions & refresh_tokens
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL, -- latest expiry of any token in the family
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_login_sessions_user_id ON login_sessions(user_id);
+
+-- Sessions issued before refresh tokens become single-session families.
+UPDATE sessions SET family_id = id WHERE family_id IS NULL;
+INSERT INTO login_sessions (id, user_id, expires_at, last_seen_at, revoked_at, created_at)
+SELECT id, user_id, expired_at, COALESCE(created_at, CURRENT_TIMESTAMP), revoked_at, created_at
+FROM sessions
+WHERE family_id = id
+ON CONFLICT (id) DO NOTHING;
+
+-- Families created by refresh-token logins.
+INSERT INTO login_sessions (id, user_id, expires_at, revoked_at, created_at)
+SELECT family_id, user_id, MAX(expired_at), MIN(revoked_at), MIN(created_at)
+FROM refresh_tokens
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE sessions
+    ALTER COLUMN family_id SET NOT NULL,
+    ADD CONSTRAINT fk_sessions_family_id FOREIGN KEY (family_id) REFERENCES login_sessions(id) ON DELETE CASCADE;
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_family_id FOREIGN KEY (family_id) REFERENCES login_sessions(id) ON DELETE CASCADE;
+
+-- +migrate Down
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_tokens_family_id;
+ALTER TABLE sessions
+    DROP CONSTRAINT IF EXISTS fk_sessions_family_id,
+    ALTER COLUMN family_id DROP NOT NULL;
+DROP TABLE IF EXISTS login_sessions;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Bearer tokens used to be the row IDs themselves. From now on only a SHA-256 of a random
+-- token is stored, so every token issued before this migration is invalidated: users log in again.
+UPDATE login_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE revoked_at IS NULL;
+DELETE FROM refresh_tokens;
+DELETE FROM sessions;
+
+ALTER TABLE sessions ADD COLUMN token_hash CHAR(64) NOT NULL;
+ALTER TABLE sessions ADD CONSTRAINT sessions_token_hash_key UNIQUE (token_hash);
+
+ALTER TABLE refresh_tokens ADD COLUMN token_hash CHAR(64) NOT NULL;
+ALTER TABLE refresh_tokens ADD CONSTRAINT refresh_tokens_token_hash_key UNIQUE (token_hash);
+
+-- +migrate Down
+-- Hashed tokens cannot be turned back into ID tokens; drop them so everyone logs in again.
+DELETE FROM refresh_tokens;
+DELETE FROM sessions;
+ALTER TABLE refresh_tokens DROP COLUMN token_hash;
+ALTER TABLE sessions DROP COLUMN token_hash;
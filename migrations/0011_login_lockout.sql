@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Failed login tracking for progressive delays and temporary lockout.
+ALTER TABLE users
+    ADD COLUMN failed_login_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_failed_login_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE users
+    DROP COLUMN locked_until,
+    DROP COLUMN last_failed_login_at,
+    DROP COLUMN failed_login_attempts;
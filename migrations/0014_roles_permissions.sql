@@ -0,0 +1,57 @@
+-- +migrate Up
+-- Roles are rows now, each mapped to a set of permissions. The three built-in roles are marked
+-- is_system: they cannot be renamed or deleted, and super_admin's permissions cannot be edited.
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description, is_system) VALUES
+    ('super_admin', 'Full access, including roles and restoring deleted users', TRUE),
+    ('admin', 'Manages users, master data, stock adjustments and voids', TRUE),
+    ('staff', 'Moves stock and rings up sales', TRUE);
+
+-- The grants below reproduce what the hard-coded role checks allowed before.
+INSERT INTO role_permissions (role, permission)
+SELECT 'super_admin', p FROM unnest(ARRAY[
+    'users:manage', 'users:restore', 'roles:manage',
+    'warehouses:write', 'categories:write', 'items:write',
+    'stock:move', 'stock:adjust',
+    'sales:create', 'sales:view_all', 'sales:void'
+]) AS p;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', p FROM unnest(ARRAY[
+    'users:manage',
+    'warehouses:write', 'categories:write', 'items:write',
+    'stock:move', 'stock:adjust',
+    'sales:create', 'sales:view_all', 'sales:void'
+]) AS p;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'staff', p FROM unnest(ARRAY['stock:move', 'sales:create']) AS p;
+
+-- Users may only hold a role that exists. Rows left over from the old 'cashier' default had no
+-- more access than staff, so they become staff.
+UPDATE users SET role = 'staff' WHERE role NOT IN (SELECT name FROM roles);
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50);
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name);
+ALTER TABLE sessions ALTER COLUMN role TYPE VARCHAR(50);
+
+-- +migrate Down
+ALTER TABLE sessions ALTER COLUMN role TYPE VARCHAR(20);
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+UPDATE users SET role = 'staff' WHERE role NOT IN ('super_admin', 'admin', 'staff');
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(20);
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
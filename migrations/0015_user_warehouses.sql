@@ -0,0 +1,21 @@
+-- +migrate Up
+-- Which warehouses a user may see and move stock in. Users whose role has warehouses:all
+-- ignore this table.
+CREATE TABLE user_warehouses (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, warehouse_id)
+);
+
+CREATE INDEX idx_user_warehouses_warehouse ON user_warehouses (warehouse_id);
+
+-- Admins create and run the warehouses themselves, so they keep seeing all of them like super
+-- admins do. Revoke the permission from admin to scope admins as well.
+INSERT INTO role_permissions (role, permission) VALUES
+    ('super_admin', 'warehouses:all'),
+    ('admin', 'warehouses:all');
+
+-- +migrate Down
+DELETE FROM role_permissions WHERE permission = 'warehouses:all';
+DROP TABLE IF EXISTS user_warehouses;
@@ -0,0 +1,21 @@
+-- +migrate Up
+-- Long-lived credentials for scripts and scanners, sent in the X-API-Key header. Only a hash of
+-- the key is stored. permissions NULL means "everything the owner's role has"; otherwise the key
+-- grants the intersection of this list and the owner's current role.
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL, -- the first characters of the key, to recognise it in listings
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    permissions TEXT[] DEFAULT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id) WHERE revoked_at IS NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS api_keys;
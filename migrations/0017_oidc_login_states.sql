@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Pending single sign-on logins, between sending the user to the OpenID provider and their return.
+-- Only a SHA-256 of the state is stored; the nonce and PKCE verifier are needed as they are.
+CREATE TABLE oidc_login_states (
+    id UUID PRIMARY KEY,
+    state_hash CHAR(64) NOT NULL UNIQUE,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS oidc_login_states;
@@ -0,0 +1,42 @@
+-- +migrate Up
+-- Append-only record of administrative changes. Every row stores the SHA-256 of its content and
+-- of the row before it (prev_hash), so editing or removing a row breaks the chain from there on.
+CREATE TABLE audit_events (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    actor_id UUID DEFAULT NULL, -- no foreign keys: the trail must outlive the users and keys it names
+    api_key_id UUID DEFAULT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id UUID NOT NULL,
+    changes JSON NOT NULL, -- JSON rather than JSONB keeps the text exactly as it was hashed
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL, -- empty for the first event
+    hash CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_audit_events_entity ON audit_events (entity_type, entity_id);
+CREATE INDEX idx_audit_events_actor ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_change
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO role_permissions (role, permission) VALUES ('super_admin', 'audit:read');
+
+-- +migrate Down
+DELETE FROM role_permissions WHERE permission = 'audit:read';
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Optimistic concurrency: every edit bumps a row's version, and an edit made against an older
+-- version is refused instead of silently overwriting the newer one. The API exposes it as the ETag.
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE warehouses ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE shelves ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE items ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +migrate Down
+ALTER TABLE items DROP COLUMN IF EXISTS version;
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
+ALTER TABLE shelves DROP COLUMN IF EXISTS version;
+ALTER TABLE warehouses DROP COLUMN IF EXISTS version;
+ALTER TABLE users DROP COLUMN IF EXISTS version;
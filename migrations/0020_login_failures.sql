@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Failed login tracking for emails that have no account, so they are throttled and locked exactly
+-- like real accounts and the login response does not reveal which emails exist.
+CREATE TABLE login_failures (
+    email TEXT PRIMARY KEY,
+    failed_login_attempts INT NOT NULL DEFAULT 0,
+    last_failed_login_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS login_failures;
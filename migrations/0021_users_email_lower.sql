@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Emails are stored and compared in lower case, so "Staff@Example.com" and "staff@example.com" are
+-- one account whether it signs in with a password or through single sign-on. This fails if two
+-- existing users differ only by the case of their email; merge or rename them first.
+UPDATE users SET email = LOWER(email) WHERE email <> LOWER(email);
+CREATE UNIQUE INDEX uq_users_email_lower ON users (LOWER(email));
+
+-- +migrate Down
+DROP INDEX IF EXISTS uq_users_email_lower;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- A pending single sign-on login belongs to the browser that started it: that browser holds a
+-- cookie whose SHA-256 is stored here, and the callback is refused without it.
+DELETE FROM oidc_login_states;
+ALTER TABLE oidc_login_states ADD COLUMN browser_hash CHAR(64) NOT NULL;
+
+-- +migrate Down
+ALTER TABLE oidc_login_states DROP COLUMN IF EXISTS browser_hash;
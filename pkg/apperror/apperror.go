@@ -0,0 +1,152 @@
+// Package apperror defines the typed errors services return to handlers.
+// Every error carries a Kind, which decides the HTTP status, and a stable machine-readable
+// Code that clients can rely on instead of parsing the English message.
+package apperror
+
+import (
+	"errors"
+	"time"
+)
+
+// Kind classifies an error. Handlers map it to an HTTP status code.
+type Kind string
+
+const (
+	KindBadRequest           Kind = "bad_request"
+	KindTooLarge             Kind = "too_large"
+	KindValidation           Kind = "validation"
+	KindUnauthorized         Kind = "unauthorized"
+	KindForbidden            Kind = "forbidden"
+	KindNotFound             Kind = "not_found"
+	KindConflict             Kind = "conflict"
+	KindPreconditionFailed   Kind = "precondition_failed"
+	KindPreconditionRequired Kind = "precondition_required"
+	KindTooMany              Kind = "too_many_requests"
+	KindInternal             Kind = "internal"
+)
+
+// CodeInternal is the code of every internal error. Its cause is never shown to clients.
+const CodeInternal = "INTERNAL_ERROR"
+
+// Error is a domain error with a stable code.
+type Error struct {
+	Kind    Kind
+	Code    string // e.g. ITEM_NOT_FOUND; stable across releases
+	Message string // human-readable, English
+	Details any    // optional structured details, e.g. per-field validation errors
+	Err     error  // optional underlying cause, for logs only
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any *Error with the same code, so a copy made by WithDetails or a
+// sentinel wrapped with fmt.Errorf("%w: ...") still satisfies errors.Is.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of e carrying the given details.
+func (e *Error) WithDetails(details any) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// WithRetryAfter returns a copy of e telling the client to wait d, rounded up to whole seconds.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return e.WithDetails(RetryAfter{Seconds: seconds})
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// FieldError describes why a single request field was rejected. Validation errors carry a
+// []FieldError in Details.
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
+
+// RetryAfter is the detail carried by TooManyRequests errors. Handlers also send it as a
+// Retry-After header.
+type RetryAfter struct {
+	Seconds int `json:"retry_after_seconds" example:"30"`
+}
+
+// BadRequest reports a request that could not be parsed at all, e.g. malformed JSON.
+func BadRequest(code, message string) *Error {
+	return newError(KindBadRequest, code, message)
+}
+
+// TooLarge reports a request body over the accepted size.
+func TooLarge(code, message string) *Error {
+	return newError(KindTooLarge, code, message)
+}
+
+// Validation reports input that is well-formed but not acceptable.
+func Validation(code, message string) *Error {
+	return newError(KindValidation, code, message)
+}
+
+// Unauthorized reports missing or invalid credentials.
+func Unauthorized(code, message string) *Error {
+	return newError(KindUnauthorized, code, message)
+}
+
+// Forbidden reports an authenticated caller that may not perform the action.
+func Forbidden(code, message string) *Error {
+	return newError(KindForbidden, code, message)
+}
+
+// NotFound reports a missing (or hidden) resource.
+func NotFound(code, message string) *Error {
+	return newError(KindNotFound, code, message)
+}
+
+// Conflict reports a request that clashes with the current state of a resource.
+func Conflict(code, message string) *Error {
+	return newError(KindConflict, code, message)
+}
+
+// PreconditionFailed reports a conditional request whose condition no longer holds, e.g. an update
+// made against a version of the resource that has since changed.
+func PreconditionFailed(code, message string) *Error {
+	return newError(KindPreconditionFailed, code, message)
+}
+
+// PreconditionRequired reports a request that must be conditional but was sent without a condition.
+func PreconditionRequired(code, message string) *Error {
+	return newError(KindPreconditionRequired, code, message)
+}
+
+// TooManyRequests reports a caller that has to slow down. Use WithRetryAfter to tell the client when
+// to try again.
+func TooManyRequests(code, message string) *Error {
+	return newError(KindTooMany, code, message)
+}
+
+// Internal wraps an unexpected failure. Clients only ever see a generic message.
+func Internal(cause error) *Error {
+	return &Error{Kind: KindInternal, Code: CodeInternal, Message: "internal server error", Err: cause}
+}
+
+// As returns the *Error in err's chain, or an internal error wrapping err if there is none.
+func As(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_WritesOneEmlPerMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := NewFileMailer(dir, "no-reply@inventory.local")
+
+	err := m.Send(context.Background(), Message{To: "staff@gmail.com", Subject: "Reset your password", Body: "token: abc"})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "To: staff@gmail.com\r\n")
+	assert.Contains(t, string(raw), "From: no-reply@inventory.local\r\n")
+	assert.Contains(t, string(raw), "\r\n\r\ntoken: abc")
+}
@@ -0,0 +1,41 @@
+// Package mailer sends transactional email. Services depend on the Mailer interface;
+// the implementation is picked from the configuration at startup.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers a message or returns an error.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// headerValue drops line breaks so a value cannot inject extra headers.
+var headerValue = strings.NewReplacer("\r", "", "\n", "")
+
+// build renders msg as an RFC 5322 message with the given sender.
+func build(from string, msg Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue.Replace(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue.Replace(msg.Subject)))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return b.Bytes()
+}
@@ -0,0 +1,132 @@
+package oidc_test
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"inventory-system/pkg/oidc"
+	"inventory-system/pkg/oidc/oidctest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProvider(issuer *oidctest.Issuer) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		IssuerURL:    issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: "s3cret",
+		RedirectURL:  "https://inventory.example.com/sso/callback",
+		Scopes:       []string{"email", "profile"},
+	}, nil)
+}
+
+func TestCodeFlow_WithPKCE(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "inventory")
+	provider := newProvider(issuer)
+	ctx := context.Background()
+
+	verifier, err := oidc.GenerateVerifier()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, "the-state", "the-nonce", verifier)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(authURL, issuer.URL+"/authorize?"))
+	assert.Contains(t, authURL, "scope=openid+email+profile")
+
+	code, state := issuer.Authorize(t, authURL, issuer.Claims("", "staff@example.com"))
+	assert.Equal(t, "the-state", state)
+
+	// Verifier yang salah harus ditolak provider, dan kodenya hangus
+	_, err = provider.Exchange(ctx, code, "not-the-verifier")
+	require.Error(t, err)
+	_, err = provider.Exchange(ctx, code, verifier)
+	require.Error(t, err, "a code can be redeemed once")
+
+	code, _ = issuer.Authorize(t, authURL, issuer.Claims("", "staff@example.com"))
+	rawIDToken, err := provider.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+
+	claims, err := provider.Verify(ctx, rawIDToken, "the-nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "staff@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "sub-staff@example.com", claims.Subject)
+}
+
+func TestVerify_RejectsUntrustedTokens(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "inventory")
+	provider := newProvider(issuer)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		modify func(map[string]any)
+	}{
+		{"wrong nonce", func(c map[string]any) { c["nonce"] = "replayed" }},
+		{"other audience", func(c map[string]any) { c["aud"] = "another-app" }},
+		{"other issuer", func(c map[string]any) { c["iss"] = "https://evil.example.com" }},
+		{"expired", func(c map[string]any) { c["exp"] = time.Now().Add(-10 * time.Minute).Unix() }},
+		{"issued in the future", func(c map[string]any) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{"authorized for someone else", func(c map[string]any) {
+			c["aud"] = []string{"inventory", "another-app"}
+			c["azp"] = "another-app"
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := issuer.Claims("the-nonce", "staff@example.com")
+			tc.modify(claims)
+
+			_, err := provider.Verify(ctx, issuer.Sign(claims), "the-nonce")
+			assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+		})
+	}
+
+	t.Run("tampered payload", func(t *testing.T) {
+		parts := strings.Split(issuer.Sign(issuer.Claims("the-nonce", "staff@example.com")), ".")
+		forged := issuer.Claims("the-nonce", "admin@example.com")
+		parts[1] = strings.Split(issuer.Sign(forged), ".")[1]
+
+		_, err := provider.Verify(ctx, strings.Join(parts, "."), "the-nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		parts := strings.Split(issuer.Sign(issuer.Claims("the-nonce", "staff@example.com")), ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+
+		_, err := provider.Verify(ctx, parts[0]+"."+parts[1]+".", "the-nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+	})
+}
+
+func TestVerify_EmailVerifiedAsString(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "inventory")
+	provider := newProvider(issuer)
+
+	claims := issuer.Claims("n", "staff@example.com")
+	claims["email_verified"] = "true"
+	got, err := provider.Verify(context.Background(), issuer.Sign(claims), "n")
+	require.NoError(t, err)
+	assert.True(t, got.EmailVerified)
+
+	delete(claims, "email_verified")
+	got, err = provider.Verify(context.Background(), issuer.Sign(claims), "n")
+	require.NoError(t, err)
+	assert.False(t, got.EmailVerified)
+}
+
+func TestDiscovery_IssuerMustMatch(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "inventory")
+	// Server yang sama, tapi dokumennya mengaku issuer lain
+	sameServer := strings.Replace(issuer.URL, "127.0.0.1", "localhost", 1)
+	provider := oidc.NewProvider(oidc.Config{IssuerURL: sameServer, ClientID: "inventory"}, nil)
+
+	_, err := provider.AuthCodeURL(context.Background(), "s", "n", "v")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match")
+}
@@ -0,0 +1,186 @@
+// Package oidctest provides a stand-in OpenID provider for tests. It serves a discovery
+// document, a JWKS and a token endpoint that enforces PKCE, and signs ID tokens with a
+// throwaway RSA key.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"inventory-system/pkg/oidc"
+)
+
+// Issuer is a running stand-in provider. Its URL is the issuer identifier.
+type Issuer struct {
+	URL      string
+	ClientID string
+
+	key    *rsa.PrivateKey
+	keyID  string
+	server *httptest.Server
+
+	mu    sync.Mutex
+	codes map[string]grant
+}
+
+// grant is an issued authorization code waiting to be redeemed.
+type grant struct {
+	redirectURI string
+	challenge   string
+	idToken     string
+}
+
+// NewIssuer starts a provider for clientID and stops it when the test ends.
+func NewIssuer(t testing.TB, clientID string) *Issuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("oidctest: generate key: %v", err)
+	}
+
+	i := &Issuer{ClientID: clientID, key: key, keyID: "test-key", codes: make(map[string]grant)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", i.discovery)
+	mux.HandleFunc("GET /jwks", i.jwks)
+	mux.HandleFunc("POST /token", i.token)
+	i.server = httptest.NewServer(mux)
+	i.URL = i.server.URL
+	t.Cleanup(i.server.Close)
+	return i
+}
+
+// Claims returns valid ID token claims for this client; adjust them before signing.
+func (i *Issuer) Claims(nonce, email string) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":            i.URL,
+		"aud":            i.ClientID,
+		"sub":            "sub-" + email,
+		"email":          email,
+		"email_verified": true,
+		"name":           "Test User",
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+// Sign returns claims as an RS256-signed ID token.
+func (i *Issuer) Sign(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": i.keyID})
+	payload, _ := json.Marshal(claims)
+	signingInput := segment(header) + "." + segment(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: sign: %v", err))
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Authorize plays the user signing in at the provider: it reads the authorization URL the
+// application produced and returns the code and state the provider would redirect back with.
+// claims, with the nonce from the URL filled in, become the ID token the code is redeemed for.
+func (i *Issuer) Authorize(t testing.TB, authURL string, claims map[string]any) (code, state string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("oidctest: parse authorization URL: %v", err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != i.ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("oidctest: unexpected authorization request %s", authURL)
+	}
+
+	claims["nonce"] = q.Get("nonce")
+	code, err = oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatalf("oidctest: generate code: %v", err)
+	}
+
+	i.mu.Lock()
+	i.codes[code] = grant{redirectURI: q.Get("redirect_uri"), challenge: q.Get("code_challenge"), idToken: i.Sign(claims)}
+	i.mu.Unlock()
+	return code, q.Get("state")
+}
+
+func (i *Issuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.URL,
+		"authorization_endpoint":                i.URL + "/authorize",
+		"token_endpoint":                        i.URL + "/token",
+		"jwks_uri":                              i.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (i *Issuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": i.keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+// token redeems a code once, if the redirect URI and the PKCE verifier match the authorization request.
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	clientID := r.PostForm.Get("client_id")
+	if user, _, ok := r.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(user)
+	}
+
+	i.mu.Lock()
+	g, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+
+	if !ok || clientID != i.ClientID || g.redirectURI != r.PostForm.Get("redirect_uri") ||
+		oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "test-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     g.idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func segment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,53 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Secret dari RFC 6238 appendix B (SHA1): ASCII "12345678901234567890".
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_MatchesRFC6238Vectors(t *testing.T) {
+	// RFC-nya pakai 8 digit; 6 digit terakhir harus sama.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(tc.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, code, "t=%d", tc.unix)
+	}
+}
+
+func TestValidate_AllowsOneStepOfDriftAndReturnsStep(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, err := Code(rfcSecret, Step(now)-1)
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Validate(rfcSecret, previous, now.Add(2*Period), 1)
+	assert.False(t, ok)
+	_, ok = Validate(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Inventory System", "admin@gmail.com", "JBSWY3DPEHPK3PXP")
+	assert.Equal(t, "otpauth://totp/Inventory%20System:admin@gmail.com?algorithm=SHA1&digits=6&issuer=Inventory+System&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
@@ -1,9 +1,17 @@
 package utils
 
 import (
+	"unicode"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Password policy for passwords chosen by users. bcrypt only reads the first 72 bytes.
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72
+)
+
 // HashPassword encrypts a plain text password using bcrypt.
 func HashPassword(password string) (string, error) {
 	// bcrypt.DefaultCost is 10. This provides a good balance between security and performance.
@@ -20,3 +28,22 @@ func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// MeetsPasswordPolicy reports whether a password is 8 to 72 bytes long and contains at least
+// one letter and one digit. Request DTOs apply it with the `password` validate tag.
+func MeetsPasswordPolicy(password string) bool {
+	if len(password) < PasswordMinLength || len(password) > PasswordMaxLength {
+		return false
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
@@ -3,6 +3,9 @@ package utils
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+
+	"inventory-system/pkg/apperror"
 
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -11,6 +14,7 @@ import (
 type Response struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`   // Stable machine-readable error code, only set on errors
 	Data    any    `json:"data,omitempty"`   // omitempty: omitted if empty
 	Meta    any    `json:"meta,omitempty"`   // Used for pagination metadata
 	Errors  any    `json:"errors,omitempty"` // Used for specific error details/validation
@@ -37,10 +41,64 @@ func Success(w http.ResponseWriter, r *http.Request, status int, message string,
 }
 
 // Error sends a standard failure response (e.g., 400 Bad Request, 500 Internal Server Error).
+// The error code is derived from the status; use HandleError for domain errors with their own code.
 func Error(w http.ResponseWriter, r *http.Request, status int, message string, errors any) {
+	writeError(w, r, status, statusCodes[status], message, errors)
+}
+
+// HandleError sends the failure response for an error returned by a service.
+// *apperror.Error values are mapped to their HTTP status and code; anything else is a 500.
+// Internal errors never expose their message or cause.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr := apperror.As(err)
+
+	status, ok := kindStatus[appErr.Kind]
+	if !ok || appErr.Kind == apperror.KindInternal {
+		writeError(w, r, http.StatusInternalServerError, apperror.CodeInternal, "Internal server error", nil)
+		return
+	}
+
+	if ra, ok := appErr.Details.(apperror.RetryAfter); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(ra.Seconds))
+	}
+
+	// err.Error() keeps any context added with fmt.Errorf("%w: ...") around the sentinel.
+	writeError(w, r, status, appErr.Code, err.Error(), appErr.Details)
+}
+
+// kindStatus maps error kinds to HTTP status codes.
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindBadRequest:           http.StatusBadRequest,
+	apperror.KindTooLarge:             http.StatusRequestEntityTooLarge,
+	apperror.KindValidation:           http.StatusUnprocessableEntity,
+	apperror.KindUnauthorized:         http.StatusUnauthorized,
+	apperror.KindForbidden:            http.StatusForbidden,
+	apperror.KindNotFound:             http.StatusNotFound,
+	apperror.KindConflict:             http.StatusConflict,
+	apperror.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	apperror.KindPreconditionRequired: http.StatusPreconditionRequired,
+	apperror.KindTooMany:              http.StatusTooManyRequests,
+	apperror.KindInternal:             http.StatusInternalServerError,
+}
+
+// statusCodes are the generic codes used by Error when no domain code is available.
+var statusCodes = map[int]string{
+	http.StatusBadRequest:           "BAD_REQUEST",
+	http.StatusUnauthorized:         "UNAUTHORIZED",
+	http.StatusForbidden:            "FORBIDDEN",
+	http.StatusNotFound:             "NOT_FOUND",
+	http.StatusConflict:             "CONFLICT",
+	http.StatusPreconditionFailed:   "PRECONDITION_FAILED",
+	http.StatusPreconditionRequired: "PRECONDITION_REQUIRED",
+	http.StatusTooManyRequests:      "TOO_MANY_REQUESTS",
+	http.StatusInternalServerError:  apperror.CodeInternal,
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string, errors any) {
 	res := Response{
 		Status:  status,
 		Message: message,
+		Code:    code,
 		Errors:  errors,
 		Meta: map[string]string{
 			"request_id": middleware.GetReqID(r.Context()),
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inventory-system/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleError_MapsKindToStatusAndCode(t *testing.T) {
+	notFound := apperror.NotFound("ITEM_NOT_FOUND", "item not found")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{"not found", notFound, http.StatusNotFound, "ITEM_NOT_FOUND", "item not found"},
+		{"wrapped sentinel keeps context", fmt.Errorf("%w: sku ABC", notFound), http.StatusNotFound, "ITEM_NOT_FOUND", "item not found: sku ABC"},
+		{"conflict", apperror.Conflict("INSUFFICIENT_STOCK", "insufficient stock"), http.StatusConflict, "INSUFFICIENT_STOCK", "insufficient stock"},
+		{"forbidden", apperror.Forbidden("CANNOT_DELETE_SUPER_ADMIN", "forbidden"), http.StatusForbidden, "CANNOT_DELETE_SUPER_ADMIN", "forbidden"},
+		{"precondition failed", apperror.PreconditionFailed("VERSION_MISMATCH", "changed"), http.StatusPreconditionFailed, "VERSION_MISMATCH", "changed"},
+		{"precondition required", apperror.PreconditionRequired("IF_MATCH_REQUIRED", "if-match required"), http.StatusPreconditionRequired, "IF_MATCH_REQUIRED", "if-match required"},
+		// Error mentah / internal tidak boleh bocor ke client
+		{"internal hides cause", apperror.Internal(errors.New("pq: connection refused")), http.StatusInternalServerError, apperror.CodeInternal, "Internal server error"},
+		{"untyped error is internal", errors.New("boom"), http.StatusInternalServerError, apperror.CodeInternal, "Internal server error"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HandleError(w, httptest.NewRequest(http.MethodGet, "/", nil), tc.err)
+
+			var res Response
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, tc.wantStatus, res.Status)
+			assert.Equal(t, tc.wantCode, res.Code)
+			assert.Equal(t, tc.wantMessage, res.Message)
+		})
+	}
+}
+
+func TestHandleError_SetsRetryAfter(t *testing.T) {
+	w := httptest.NewRecorder()
+	HandleError(w, httptest.NewRequest(http.MethodPost, "/", nil), apperror.TooManyRequests("RATE_LIMITED", "too many requests").WithRetryAfter(1500*time.Millisecond))
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
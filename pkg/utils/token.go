@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// tokenBytes is the amount of randomness in a generated token (256 bits).
+const tokenBytes = 32
+
+// GenerateToken returns a new random bearer token together with the hash to store for it.
+// Only the hash may be persisted; the plain token is handed to the client once.
+func GenerateToken() (plain, hash string, err error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	plain = base64.RawURLEncoding.EncodeToString(b)
+	return plain, HashToken(plain), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 of a bearer token, as stored in the database.
+// A fast hash is enough here because the token itself is high-entropy random data.
+func HashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
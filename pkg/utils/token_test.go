@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateToken_StoresOnlyTheHash(t *testing.T) {
+	plain, hash, err := GenerateToken()
+	require.NoError(t, err)
+
+	assert.Len(t, plain, 43) // 32 bytes, base64url tanpa padding
+	assert.Len(t, hash, 64)
+	assert.NotEqual(t, plain, hash)
+	assert.Equal(t, hash, HashToken(plain))
+
+	other, _, err := GenerateToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, plain, other)
+}
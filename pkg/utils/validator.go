@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"inventory-system/pkg/apperror"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MaxRequestBodyBytes caps the size of every JSON request body (1 MB).
+const MaxRequestBodyBytes = 1 << 20
+
+var (
+	ErrEmptyBody        = apperror.BadRequest("EMPTY_BODY", "request body is required")
+	ErrMalformedJSON    = apperror.BadRequest("MALFORMED_JSON", "invalid request payload format")
+	ErrBodyTooLarge     = apperror.TooLarge("PAYLOAD_TOO_LARGE", "request body must not exceed 1 MB")
+	ErrValidationFailed = apperror.Validation("VALIDATION_FAILED", "request validation failed")
+)
+
+// validate is shared by all requests; validator.Validate caches struct metadata and is safe for concurrent use.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name ("new_stock") instead of the Go name ("NewStock").
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// `password` applies the password policy to a field chosen by the user.
+	_ = v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return MeetsPasswordPolicy(fl.Field().String())
+	})
+	return v
+}
+
+// BindJSON decodes the request body into dst and runs its `validate` struct tags.
+// Bodies over MaxRequestBodyBytes, unknown fields and trailing data are rejected.
+// The returned error is an *apperror.Error ready for HandleError; validation failures
+// carry a []apperror.FieldError in Details.
+func BindJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodyBytes)
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	// 🛡️ GUARD: Body harus berisi tepat satu objek JSON
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return ErrBodyTooLarge
+		}
+		return ErrMalformedJSON
+	}
+
+	return Validate(dst)
+}
+
+// Validate runs the `validate` struct tags on v.
+func Validate(v any) error {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return apperror.Internal(err)
+	}
+
+	fields := make([]apperror.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, apperror.FieldError{
+			Field:   fieldPath(fe),
+			Message: fieldMessage(fe),
+		})
+	}
+	return ErrValidationFailed.WithDetails(fields)
+}
+
+// decodeError turns a json.Decoder error into an *apperror.Error.
+func decodeError(err error) error {
+	var maxErr *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &maxErr):
+		return ErrBodyTooLarge
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return ErrValidationFailed.WithDetails([]apperror.FieldError{
+			{Field: typeErr.Field, Message: typeMessage(typeErr.Type)},
+		})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for this case.
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return ErrValidationFailed.WithDetails([]apperror.FieldError{
+			{Field: field, Message: "is not a recognised field"},
+		})
+	}
+	return ErrMalformedJSON
+}
+
+// fieldPath drops the struct name from the namespace: "CheckoutRequest.items[0].quantity" -> "items[0].quantity".
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(param, " ", ", ")
+	case "min", "max", "len":
+		return lengthMessage(fe.Tag(), fe.Kind(), param)
+	case "password":
+		return fmt.Sprintf("must be %d to %d characters long and contain at least one letter and one digit", PasswordMinLength, PasswordMaxLength)
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be greater than or equal to " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be less than or equal to " + param
+	}
+	return fmt.Sprintf("failed the %q rule", fe.Tag())
+}
+
+// lengthMessage words min/max/len for strings, collections and numbers.
+func lengthMessage(tag string, kind reflect.Kind, param string) string {
+	bound := map[string]string{"min": "at least", "max": "at most", "len": "exactly"}[tag]
+
+	switch kind {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", bound, param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s items", bound, param)
+	}
+	return fmt.Sprintf("must be %s %s", bound, param)
+}
+
+func typeMessage(t reflect.Type) string {
+	// Types like uuid.UUID and time.Time are sent as JSON strings.
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		return "must be a string"
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.String:
+		return "must be a string"
+	case reflect.Bool:
+		return "must be a boolean"
+	case reflect.Slice, reflect.Array:
+		return "must be an array"
+	case reflect.Struct, reflect.Map:
+		return "must be an object"
+	}
+	return "has an invalid type"
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inventory-system/pkg/apperror"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLine struct {
+	Quantity int `json:"quantity" validate:"required,gt=0"`
+}
+
+type testPayload struct {
+	Email string     `json:"email" validate:"required,email"`
+	Role  string     `json:"role" validate:"required,oneof=admin staff"`
+	Lines []testLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+func bind(body string) error {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	var dst testPayload
+	return BindJSON(httptest.NewRecorder(), r, &dst)
+}
+
+func TestBindJSON_ReportsEveryInvalidField(t *testing.T) {
+	err := bind(`{"email":"not-an-email","role":"boss","lines":[{"quantity":0}]}`)
+
+	appErr := apperror.As(err)
+	require.Equal(t, apperror.KindValidation, appErr.Kind)
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "email", Message: "must be a valid email address"},
+		{Field: "role", Message: "must be one of: admin, staff"},
+		{Field: "lines[0].quantity", Message: "is required"},
+	}, appErr.Details)
+}
+
+func TestBindJSON_RejectsMalformedBodies(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+		wantKind apperror.Kind
+	}{
+		{"empty body", ``, "EMPTY_BODY", apperror.KindBadRequest},
+		{"syntax error", `{"email":`, "MALFORMED_JSON", apperror.KindBadRequest},
+		{"trailing data", `{"email":"a@b.co","role":"admin","lines":[{"quantity":1}]} {}`, "MALFORMED_JSON", apperror.KindBadRequest},
+		{"unknown field", `{"email":"a@b.co","is_admin":true}`, "VALIDATION_FAILED", apperror.KindValidation},
+		{"wrong type", `{"lines":[{"quantity":"two"}]}`, "VALIDATION_FAILED", apperror.KindValidation},
+		{"oversize body", `{"email":"` + strings.Repeat("a", MaxRequestBodyBytes) + `"}`, "PAYLOAD_TOO_LARGE", apperror.KindTooLarge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			appErr := apperror.As(bind(tc.body))
+			assert.Equal(t, tc.wantKind, appErr.Kind)
+			assert.Equal(t, tc.wantCode, appErr.Code)
+		})
+	}
+}
+
+func TestBindJSON_AcceptsValidPayload(t *testing.T) {
+	assert.NoError(t, bind(`{"email":"staff@gmail.com","role":"staff","lines":[{"quantity":2}]}`))
+}
+
+func TestValidate_PasswordPolicy(t *testing.T) {
+	type payload struct {
+		Password string `json:"password" validate:"required,password"`
+	}
+
+	for _, pw := range []string{"short1", "onlyletters", "12345678", strings.Repeat("a1", 37)} {
+		appErr := apperror.As(Validate(payload{Password: pw}))
+		assert.Equal(t, apperror.KindValidation, appErr.Kind, pw)
+	}
+	assert.NoError(t, Validate(payload{Password: "n3w-Passw0rd"}))
+}